@@ -54,10 +54,18 @@ func (g *GrpcHandler) PostSpans(_ context.Context, request *api_v2.PostSpansRequ
 }
 
 func (g *GrpcHandler) UpdateTags(_ context.Context, request *api_v1.UpdateTagsRequest) (*api_v1.NullRely, error) {
-	g.logger.Info("Received request to updateEvaluatorTags", zap.Any("tags", request.GetTags()))
+	g.logger.Info("Received request to updateEvaluatorTags", zap.Any("tags", request.GetTags()),
+		zap.Any("rules", request.GetRules()))
 
 	g.eval.Update(&api_v1.EvaluatingTags{
-		Tags: request.GetTags(),
+		Tags:  request.GetTags(),
+		Rules: request.GetRules(),
 	})
 	return &api_v1.NullRely{}, nil
 }
+
+func (g *GrpcHandler) GetMatches(context.Context, *api_v1.GetMatchesRequest) (*api_v1.GetMatchesResponse, error) {
+	return &api_v1.GetMatchesResponse{
+		Matches: g.eval.Matches(),
+	}, nil
+}
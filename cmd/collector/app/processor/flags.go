@@ -18,22 +18,32 @@ import (
 	"flag"
 	"github.com/houyi-tracing/houyi/ports"
 	"github.com/spf13/viper"
+	"time"
 )
 
 const (
 	numWorkers       = "num.workers"
 	configServerAddr = "sampling.config.server.addr"
 	configServerPort = "sampling.config.server.port"
+	statsInterval    = "relation.stats.report.interval"
+	spanCacheTTL     = "span.cache.ttl"
+	spanCacheSize    = "span.cache.size"
 
 	DefaultNumWorkers       = 4
 	DefaultConfigServerAddr = "config-server"
 	DefaultConfigServerPort = ports.ConfigServerGrpcListenPort
+	DefaultStatsInterval    = time.Second * 10
+	DefaultSpanCacheTTL     = time.Second * 30
+	DefaultSpanCacheSize    = 100000
 )
 
 type Flags struct {
 	NumWorkers       int
 	ConfigServerAddr string
 	ConfigServerPort int
+	StatsInterval    time.Duration
+	SpanCacheTTL     time.Duration
+	SpanCacheSize    int
 }
 
 func AddFlags(flags *flag.FlagSet) {
@@ -41,12 +51,22 @@ func AddFlags(flags *flag.FlagSet) {
 		DefaultNumWorkers, "Number of workers to consume dynamic queue in span processor.")
 	flags.String(configServerAddr, DefaultConfigServerAddr, "[Sampling] IP or domain name of configuration server.")
 	flags.Int(configServerPort, DefaultConfigServerPort, "[Sampling] Port to server gRPC for configuration server.")
+	flags.Duration(statsInterval, DefaultStatsInterval,
+		"[Gossip] Interval to report statistics of calls between operations to gossip seeds.")
+	flags.Duration(spanCacheTTL, DefaultSpanCacheTTL,
+		"Time to remember operations of spans for discovering relations by span references, which is also the "+
+			"longest time a span would wait for its parent.")
+	flags.Int(spanCacheSize, DefaultSpanCacheSize,
+		"Maximum number of spans remembered for discovering relations by span references.")
 }
 
 func (f *Flags) InitFromViper(v *viper.Viper) *Flags {
 	f.NumWorkers = v.GetInt(numWorkers)
 	f.ConfigServerAddr = v.GetString(configServerAddr)
 	f.ConfigServerPort = v.GetInt(configServerPort)
+	f.StatsInterval = v.GetDuration(statsInterval)
+	f.SpanCacheTTL = v.GetDuration(spanCacheTTL)
+	f.SpanCacheSize = v.GetInt(spanCacheSize)
 
 	return f
 }
@@ -21,10 +21,14 @@ import (
 	"github.com/houyi-tracing/houyi/pkg/routing"
 	"github.com/houyi-tracing/houyi/pkg/tg"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
+	"time"
 )
 
 type options struct {
 	numWorkers       int
+	statsInterval    time.Duration
+	spanCacheTTL     time.Duration
+	spanCacheSize    int
 	registryEndpoint *routing.Endpoint
 
 	filterSpan     filter.FilterSpan
@@ -45,6 +49,24 @@ func (options) NumWorkers(n int) Option {
 	}
 }
 
+func (options) StatsInterval(interval time.Duration) Option {
+	return func(opt *options) {
+		opt.statsInterval = interval
+	}
+}
+
+func (options) SpanCacheTTL(ttl time.Duration) Option {
+	return func(opt *options) {
+		opt.spanCacheTTL = ttl
+	}
+}
+
+func (options) SpanCacheSize(size int) Option {
+	return func(opt *options) {
+		opt.spanCacheSize = size
+	}
+}
+
 func (options) FilterSpan(f filter.FilterSpan) Option {
 	return func(opt *options) {
 		opt.filterSpan = f
@@ -89,5 +111,14 @@ func (o *options) apply(opts ...Option) *options {
 	if o.numWorkers == 0 {
 		o.numWorkers = DefaultNumWorkers
 	}
+	if o.statsInterval <= 0 {
+		o.statsInterval = DefaultStatsInterval
+	}
+	if o.spanCacheTTL <= 0 {
+		o.spanCacheTTL = DefaultSpanCacheTTL
+	}
+	if o.spanCacheSize <= 0 {
+		o.spanCacheSize = DefaultSpanCacheSize
+	}
 	return o
 }
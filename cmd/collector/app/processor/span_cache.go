@@ -0,0 +1,151 @@
+// Copyright (c) 2021 The Houyi Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package processor
+
+import (
+	"container/list"
+	"github.com/houyi-tracing/houyi/idl/api_v1"
+	"github.com/jaegertracing/jaeger/model"
+	"sync"
+	"time"
+)
+
+type spanKey struct {
+	traceID model.TraceID
+	spanID  model.SpanID
+}
+
+// call is a span calling an operation, which would be recorded as a relation once its parent is known.
+type call struct {
+	op        *api_v1.Operation
+	duration  time.Duration
+	isError   bool
+	startTime time.Time
+}
+
+type cachedOp struct {
+	op        *api_v1.Operation
+	expiresAt time.Time
+}
+
+type pendingCalls struct {
+	calls     []*call
+	expiresAt time.Time
+}
+
+type cacheEntry struct {
+	key       spanKey
+	pending   bool
+	expiresAt time.Time
+}
+
+// spanCache remembers operations of recently processed spans, so that the operation of the parent of a span could be
+// resolved by span references even if they are in different batches. Spans arriving earlier than their parents wait
+// in the cache until their parents arrive. Both kinds of entries expire after ttl, and the oldest entries are evicted
+// if there are more than size entries.
+type spanCache struct {
+	sync.Mutex
+
+	ttl     time.Duration
+	size    int
+	ops     map[spanKey]*cachedOp
+	pending map[spanKey]*pendingCalls
+
+	// entries are ordered by insertion time, which is also the order of expiration time.
+	entries *list.List
+}
+
+func newSpanCache(ttl time.Duration, size int) *spanCache {
+	return &spanCache{
+		ttl:     ttl,
+		size:    size,
+		ops:     make(map[spanKey]*cachedOp),
+		pending: make(map[spanKey]*pendingCalls),
+		entries: list.New(),
+	}
+}
+
+// observe remembers op as the operation of span key and returns calls waiting for it. If parent is not nil, it returns
+// the operation of parent if it is known, otherwise c would wait for parent in the cache and nil is returned.
+func (c *spanCache) observe(key spanKey, op *api_v1.Operation, parent *spanKey, child *call) (*api_v1.Operation, []*call) {
+	c.Lock()
+	defer c.Unlock()
+
+	now := time.Now()
+	c.purge(now)
+
+	expiresAt := now.Add(c.ttl)
+	c.ops[key] = &cachedOp{op: op, expiresAt: expiresAt}
+	c.entries.PushBack(&cacheEntry{key: key, expiresAt: expiresAt})
+
+	var waiting []*call
+	if p, has := c.pending[key]; has {
+		waiting = p.calls
+		delete(c.pending, key)
+	}
+
+	if parent == nil {
+		return nil, waiting
+	}
+	if cached, has := c.ops[*parent]; has {
+		return cached.op, waiting
+	}
+	if p, has := c.pending[*parent]; has {
+		p.calls = append(p.calls, child)
+	} else {
+		c.pending[*parent] = &pendingCalls{calls: []*call{child}, expiresAt: expiresAt}
+		c.entries.PushBack(&cacheEntry{key: *parent, pending: true, expiresAt: expiresAt})
+	}
+	return nil, waiting
+}
+
+// purge removes expired entries and the oldest entries beyond size.
+func (c *spanCache) purge(now time.Time) {
+	for front := c.entries.Front(); front != nil; front = c.entries.Front() {
+		e := front.Value.(*cacheEntry)
+		if !e.expiresAt.Before(now) && len(c.ops)+len(c.pending) < c.size {
+			return
+		}
+		c.entries.Remove(front)
+
+		// the entry might have been replaced by a newer one of the same key.
+		if e.pending {
+			if p, has := c.pending[e.key]; has && p.expiresAt.Equal(e.expiresAt) {
+				delete(c.pending, e.key)
+			}
+		} else if cached, has := c.ops[e.key]; has && cached.expiresAt.Equal(e.expiresAt) {
+			delete(c.ops, e.key)
+		}
+	}
+}
+
+// parentReference returns the span which inputted span is caused by. CHILD_OF references are preferred to
+// FOLLOWS_FROM ones.
+func parentReference(span *model.Span) *spanKey {
+	var ret *spanKey
+	for _, ref := range span.GetReferences() {
+		if ref.SpanID == 0 {
+			continue
+		}
+		key := &spanKey{traceID: ref.TraceID, spanID: ref.SpanID}
+		if ref.RefType == model.SpanRefType_CHILD_OF {
+			return key
+		}
+		if ret == nil {
+			ret = key
+		}
+	}
+	return ret
+}
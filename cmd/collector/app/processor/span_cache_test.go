@@ -0,0 +1,81 @@
+// Copyright (c) 2021 The Houyi Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package processor
+
+import (
+	"github.com/houyi-tracing/houyi/idl/api_v1"
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestSpanCacheOutOfOrder(t *testing.T) {
+	c := newSpanCache(time.Minute, 100)
+	traceID := model.NewTraceID(1, 1)
+	parent := spanKey{traceID: traceID, spanID: 1}
+	child := spanKey{traceID: traceID, spanID: 2}
+	parentOp := &api_v1.Operation{Service: "a", Operation: "a1"}
+	childOp := &api_v1.Operation{Service: "b", Operation: "b1"}
+
+	// child arrives earlier than its parent.
+	op, waiting := c.observe(child, childOp, &parent, &call{op: childOp})
+	assert.Nil(t, op)
+	assert.Empty(t, waiting)
+
+	op, waiting = c.observe(parent, parentOp, nil, nil)
+	assert.Nil(t, op)
+	assert.Len(t, waiting, 1)
+	assert.Equal(t, childOp, waiting[0].op)
+
+	// another child arrives later than its parent.
+	sibling := spanKey{traceID: traceID, spanID: 3}
+	op, waiting = c.observe(sibling, childOp, &parent, &call{op: childOp})
+	assert.Equal(t, parentOp, op)
+	assert.Empty(t, waiting)
+}
+
+func TestSpanCachePurge(t *testing.T) {
+	c := newSpanCache(time.Minute, 2)
+	traceID := model.NewTraceID(1, 1)
+	op := &api_v1.Operation{Service: "a", Operation: "a1"}
+	for i := 1; i <= 3; i++ {
+		c.observe(spanKey{traceID: traceID, spanID: model.SpanID(i)}, op, nil, nil)
+	}
+	assert.Len(t, c.ops, 2)
+	_, has := c.ops[spanKey{traceID: traceID, spanID: 1}]
+	assert.False(t, has)
+
+	c.purge(time.Now().Add(2 * time.Minute))
+	assert.Empty(t, c.ops)
+	assert.Equal(t, 0, c.entries.Len())
+}
+
+func TestParentReference(t *testing.T) {
+	traceID := model.NewTraceID(1, 1)
+	span := &model.Span{
+		References: []model.SpanRef{
+			model.NewFollowsFromRef(traceID, 1),
+			model.NewChildOfRef(traceID, 0),
+			model.NewChildOfRef(traceID, 2),
+		},
+	}
+	assert.Equal(t, &spanKey{traceID: traceID, spanID: 2}, parentReference(span))
+
+	span.References = span.References[:2]
+	assert.Equal(t, &spanKey{traceID: traceID, spanID: 1}, parentReference(span))
+
+	assert.Nil(t, parentReference(&model.Span{}))
+}
@@ -35,10 +35,16 @@ import (
 const (
 	ParentTagNameService   = "p-svc"
 	ParentTagNameOperation = "p-op"
+	ErrorTagName           = "error"
 
 	QueueCapacity = 1048576 // 2 ^ 20
 )
 
+type promoteItem struct {
+	op     *api_v1.Operation
+	weight int
+}
+
 type queueItem struct {
 	queuedTime time.Time
 	span       *model.Span
@@ -47,7 +53,8 @@ type queueItem struct {
 type spanProcessor struct {
 	logger *zap.Logger
 
-	workers int
+	workers       int
+	statsInterval time.Duration
 
 	strategyManagerEndpoint *routing.Endpoint
 
@@ -60,9 +67,10 @@ type spanProcessor struct {
 
 	traceGraph tg.TraceGraph
 	seed       gossip.Seed
+	spanCache  *spanCache
 
 	stopCh chan *sync.WaitGroup
-	opCh   chan *api_v1.Operation
+	opCh   chan *promoteItem
 }
 
 func NewSpanProcessor(logger *zap.Logger, opts ...Option) SpanProcessor {
@@ -75,6 +83,7 @@ func NewSpanProcessor(logger *zap.Logger, opts ...Option) SpanProcessor {
 	})
 
 	go sp.strategyManagerClient()
+	go sp.relationStatsReporter()
 
 	return sp
 }
@@ -90,9 +99,11 @@ func newSpanProcessor(logger *zap.Logger, opts ...Option) *spanProcessor {
 		queue:                   queue.NewSyncPoolQueue(QueueCapacity),
 		traceGraph:              o.traceGraph,
 		seed:                    o.seed,
-		opCh:                    make(chan *api_v1.Operation, 1000),
+		spanCache:               newSpanCache(o.spanCacheTTL, o.spanCacheSize),
+		opCh:                    make(chan *promoteItem, 1000),
 		stopCh:                  make(chan *sync.WaitGroup),
 		workers:                 o.numWorkers,
+		statsInterval:           o.statsInterval,
 	}
 	processSpanFuncs := []ProcessSpan{sp.parseSpan, sp.saveSpan}
 	sp.processSpan = ChainedProcessSpan(processSpanFuncs...)
@@ -113,8 +124,9 @@ func (sp *spanProcessor) Close() error {
 	sp.queue.Stop()
 
 	var wg sync.WaitGroup
-	wg.Add(1)
-	// stop strategy manager client
+	wg.Add(2)
+	// stop strategy manager client and relation stats reporter
+	sp.stopCh <- &wg
 	sp.stopCh <- &wg
 	wg.Wait()
 
@@ -167,42 +179,74 @@ func (sp *spanProcessor) parseSpan(span *model.Span) {
 	}
 
 	// Evaluate a span whether it is need to be promoted
-	if sp.evaluateSpan(span) {
-		sp.opCh <- currOp
+	if weight := sp.evaluateSpan(span); weight > 0 {
+		sp.opCh <- &promoteItem{op: currOp, weight: weight}
 	}
-	if !sp.traceGraph.Has(currOp) {
-		_ = sp.traceGraph.Add(currOp)
-		sp.seed.MongerNewOperation(currOp)
+	sp.addOperation(currOp)
+
+	curr := &call{
+		op:        currOp,
+		duration:  span.Duration,
+		isError:   isErrorSpan(span),
+		startTime: span.StartTime,
 	}
+	key := spanKey{traceID: span.TraceID, spanID: span.SpanID}
 
+	// Parent tags set by Houyi clients take precedence over span references.
+	var parentOp *api_v1.Operation
+	var waiting []*call
 	pSvc, pOp := getTagStrVal(span, ParentTagNameService), getTagStrVal(span, ParentTagNameOperation)
-	if pSvc == "" || pOp == "" {
-		return
+	if pSvc != "" && pOp != "" {
+		parentOp = &api_v1.Operation{
+			Service:   pSvc,
+			Operation: pOp,
+		}
+		_, waiting = sp.spanCache.observe(key, currOp, nil, nil)
+	} else {
+		parentOp, waiting = sp.spanCache.observe(key, currOp, parentReference(span), curr)
 	}
-	parentOp := &api_v1.Operation{
-		Service:   pSvc,
-		Operation: pOp,
+
+	if parentOp != nil {
+		sp.addCall(parentOp, curr)
 	}
-	rel := &api_v1.Relation{
-		From: parentOp,
-		To:   currOp,
+	// children of this span which arrived earlier than it.
+	for _, child := range waiting {
+		sp.addCall(currOp, child)
 	}
+}
+
+func (sp *spanProcessor) addOperation(op *api_v1.Operation) {
+	if !sp.traceGraph.Has(op) {
+		_ = sp.traceGraph.Add(op)
+		sp.seed.MongerGraphDelta(sp.traceGraph.FlushDelta())
+	}
+}
 
-	if !sp.traceGraph.Has(parentOp) {
-		_ = sp.traceGraph.Add(parentOp)
-		sp.seed.MongerNewOperation(parentOp)
+// addCall adds the relation from parent to the operation of c if it does not exist and records c.
+func (sp *spanProcessor) addCall(parent *api_v1.Operation, c *call) {
+	if parent.GetService() == c.op.GetService() && parent.GetOperation() == c.op.GetOperation() {
+		return
+	}
+
+	rel := &api_v1.Relation{
+		From: parent,
+		To:   c.op,
 	}
+	sp.addOperation(parent)
 	if !sp.traceGraph.HasRelation(rel) {
 		_ = sp.traceGraph.AddRelation(rel)
-		sp.seed.MongerNewRelation(rel)
+		sp.seed.MongerGraphDelta(sp.traceGraph.FlushDelta())
+	}
+	if err := sp.traceGraph.RecordCall(rel, c.duration, c.isError, c.startTime); err != nil {
+		sp.logger.Error("Failed to record call", zap.String("relation", rel.String()), zap.Error(err))
 	}
 }
 
 func (sp *spanProcessor) strategyManagerClient() {
 	for {
 		select {
-		case op := <-sp.opCh:
-			sp.promoteOperation(op)
+		case item := <-sp.opCh:
+			sp.promoteOperation(item.op, item.weight)
 		case wg := <-sp.stopCh:
 			wg.Done()
 			return
@@ -210,7 +254,26 @@ func (sp *spanProcessor) strategyManagerClient() {
 	}
 }
 
-func (sp *spanProcessor) promoteOperation(op *api_v1.Operation) {
+// relationStatsReporter reports statistics of calls observed by this collector to gossip seeds periodically.
+func (sp *spanProcessor) relationStatsReporter() {
+	ticker := time.NewTicker(sp.statsInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if stats := sp.traceGraph.FlushRelationStats(); len(stats) > 0 {
+				sp.seed.MongerRelationStats(stats)
+				sp.logger.Debug("Reported relation stats", zap.Int("relations", len(stats)))
+			}
+		case wg := <-sp.stopCh:
+			wg.Done()
+			return
+		}
+	}
+}
+
+func (sp *spanProcessor) promoteOperation(op *api_v1.Operation, weight int) {
 	conn, err := grpc.Dial(sp.strategyManagerEndpoint.String(), grpc.WithInsecure(), grpc.WithBlock())
 	if conn == nil || err != nil {
 		sp.logger.Error("Could not dial to strategy manager",
@@ -223,12 +286,17 @@ func (sp *spanProcessor) promoteOperation(op *api_v1.Operation) {
 	ctx, cancel := context.WithCancel(context.TODO())
 	defer cancel()
 
-	req := op
+	req := &api_v1.PromoteRequest{
+		Service:   op.GetService(),
+		Operation: op.GetOperation(),
+		Weight:    int32(weight),
+	}
 	if _, err = c.Promote(ctx, req); err != nil {
 		sp.logger.Error("Failed to send promote request to strategy manager", zap.Error(err))
 	} else {
 		sp.logger.Debug("Received promote reply from strategy manager",
-			zap.String("operation", op.String()))
+			zap.String("operation", op.String()),
+			zap.Int("weight", weight))
 	}
 }
 
@@ -236,6 +304,22 @@ func (sp *spanProcessor) processItemFromQueue(item *queueItem) {
 	sp.processSpan(item.span)
 }
 
+// isErrorSpan returns true if the span is tagged with error=true as defined in OpenTracing semantic conventions.
+func isErrorSpan(span *model.Span) bool {
+	for _, t := range span.GetTags() {
+		if t.Key != ErrorTagName {
+			continue
+		}
+		switch t.VType {
+		case model.ValueType_BOOL:
+			return t.VBool
+		case model.ValueType_STRING:
+			return t.VStr == "true"
+		}
+	}
+	return false
+}
+
 func getTagStrVal(span *model.Span, tagName string) string {
 	tags := span.GetTags()
 	for _, t := range tags {
@@ -69,9 +69,30 @@ func main() {
 
 			// Trace Graph
 			traceGraph := tg.NewTraceGraph(logger)
+			tgOpts := new(tg.Flags).InitFromViper(v)
+			var tgSnapshotter tg.Snapshotter
+			if tgOpts.SnapshotPath != "" {
+				tgSnapshotter = tg.NewSnapshotter(logger, tgOpts.SnapshotPath, tgOpts.SnapshotInterval, traceGraph)
+				if err := tgSnapshotter.Load(); err != nil {
+					logger.Error("failed to load snapshot of trace graph", zap.Error(err))
+				}
+			}
+
+			baseFactory := svc.MetricsFactory.Namespace(metrics.NSOptions{Name: "houyi"})
 
 			// evaluator
-			eval := evaluator.NewEvaluator(logger)
+			eval := evaluator.NewEvaluator(logger, baseFactory)
+			evalOpts := new(evaluator.Flags).InitFromViper(v)
+			latencyEval := evaluator.NewLatencyEvaluator(&evaluator.LatencyEvaluatorParams{
+				Logger:            logger,
+				ZScore:            evalOpts.LatencyZScore,
+				Percentile:        evalOpts.LatencyPercentile,
+				Alpha:             evalOpts.LatencyAlpha,
+				MinSamples:        evalOpts.LatencyMinSamples,
+				Weight:            evalOpts.LatencyWeight,
+				PromotionInterval: evalOpts.LatencyInterval,
+				MaxOperations:     evalOpts.LatencyMaxOps,
+			})
 
 			// Filter
 			sf := filter.NewSpanFilter()
@@ -87,14 +108,14 @@ func main() {
 					Addr: seedOpts.ConfigServerAddress,
 					Port: seedOpts.ConfigServerGrpcPort,
 				},
-				TraceGraph: traceGraph,
+				TraceGraph:          traceGraph,
+				AntiEntropyInterval: seedOpts.AntiEntropyInterval,
 			})
 			if err != nil {
 				return err
 			}
 
 			// reuse span writer of Jaeger
-			baseFactory := svc.MetricsFactory.Namespace(metrics.NSOptions{Name: "houyi"})
 			storageFactory.InitFromViper(v)
 			if err := storageFactory.Initialize(baseFactory, logger); err != nil {
 				logger.Fatal("Failed to init storage factory", zap.Error(err))
@@ -111,9 +132,12 @@ func main() {
 			spOpts := new(processor.Flags).InitFromViper(v)
 			sp := processor.NewSpanProcessor(logger,
 				processor.Options.NumWorkers(spOpts.NumWorkers),
+				processor.Options.StatsInterval(spOpts.StatsInterval),
+				processor.Options.SpanCacheTTL(spOpts.SpanCacheTTL),
+				processor.Options.SpanCacheSize(spOpts.SpanCacheSize),
 				processor.Options.GossipSeed(gossipSeed),
 				processor.Options.TraceGraph(traceGraph),
-				processor.Options.EvaluateSpan(eval.Evaluate),
+				processor.Options.EvaluateSpan(evaluator.MaxWeight(eval.Weigh, latencyEval.Weigh)),
 				processor.Options.FilterSpan(sf.Filter),
 				processor.Options.SpanWriter(sw),
 				processor.Options.ConfigServerEndpoint(&routing.Endpoint{
@@ -143,6 +167,9 @@ func main() {
 			} else {
 				logger.Info("Started collector")
 			}
+			if tgSnapshotter != nil {
+				tgSnapshotter.Start()
+			}
 
 			svc.RunAndThen(func() {
 				// Do some nothing before completing shutting down.
@@ -153,6 +180,9 @@ func main() {
 				if err := gossipSeed.Stop(); err != nil {
 					logger.Fatal("Failed to stop gossip seed", zap.Error(err))
 				}
+				if tgSnapshotter != nil {
+					tgSnapshotter.Stop()
+				}
 			})
 			return nil
 		},
@@ -163,6 +193,8 @@ func main() {
 		rootCmd,
 		processor.AddFlags,
 		seed.AddFlags,
+		tg.AddFlags,
+		evaluator.AddFlags,
 		app.AddFlags,
 		storageFactory.AddFlags,
 		svc.AddFlags)
@@ -15,6 +15,7 @@
 package app
 
 import (
+	"github.com/houyi-tracing/houyi/cmd/cs/app/generator"
 	"github.com/houyi-tracing/houyi/cmd/cs/app/server"
 	"github.com/houyi-tracing/houyi/cmd/cs/app/store"
 	"github.com/houyi-tracing/houyi/pkg/evaluator"
@@ -46,9 +47,7 @@ type ConfigurationServerParams struct {
 
 	OperationStore store.OperationStore
 
-	ScaleFactor float64
-
-	MinSamplingRate float64
+	RateGenerators generator.Registry
 }
 
 type ConfigurationServer struct {
@@ -74,25 +73,22 @@ type ConfigurationServer struct {
 
 	opStore store.OperationStore
 
-	scaleFactor float64
-
-	minSamplingRate float64
+	rateGenerators generator.Registry
 }
 
 func NewConfigServer(params *ConfigurationServerParams) *ConfigurationServer {
 	return &ConfigurationServer{
-		logger:          params.Logger,
-		gossipSeed:      params.GossipSeed,
-		gossipRegistry:  params.GossipRegistry,
-		traceGraph:      params.TraceGraph,
-		evaluator:       params.Evaluator,
-		strategyStore:   params.StrategyStore,
-		opStore:         params.OperationStore,
-		grpcListenPort:  params.GrpcListenPort,
-		httpListenPort:  params.HttpListenPort,
-		scaleFactor:     params.ScaleFactor,
-		sst:             params.SST,
-		minSamplingRate: params.MinSamplingRate,
+		logger:         params.Logger,
+		gossipSeed:     params.GossipSeed,
+		gossipRegistry: params.GossipRegistry,
+		traceGraph:     params.TraceGraph,
+		evaluator:      params.Evaluator,
+		strategyStore:  params.StrategyStore,
+		opStore:        params.OperationStore,
+		grpcListenPort: params.GrpcListenPort,
+		httpListenPort: params.HttpListenPort,
+		sst:            params.SST,
+		rateGenerators: params.RateGenerators,
 	}
 }
 
@@ -100,17 +96,16 @@ func (cs *ConfigurationServer) Start() error {
 	var err error
 
 	if cs.grpcServer, err = server.StartGrpcServer(&server.GrpcServerParams{
-		ListenPort:      cs.grpcListenPort,
-		Logger:          cs.logger,
-		GossipRegistry:  cs.gossipRegistry,
-		GossipSeed:      cs.gossipSeed,
-		ScaleFactor:     cs.scaleFactor,
-		SST:             cs.sst,
-		TraceGraph:      cs.traceGraph,
-		OperationStore:  cs.opStore,
-		Evaluator:       cs.evaluator,
-		StrategyStore:   cs.strategyStore,
-		MinSamplingRate: cs.minSamplingRate,
+		ListenPort:     cs.grpcListenPort,
+		Logger:         cs.logger,
+		GossipRegistry: cs.gossipRegistry,
+		GossipSeed:     cs.gossipSeed,
+		SST:            cs.sst,
+		TraceGraph:     cs.traceGraph,
+		OperationStore: cs.opStore,
+		Evaluator:      cs.evaluator,
+		StrategyStore:  cs.strategyStore,
+		RateGenerators: cs.rateGenerators,
 	}); err != nil {
 		return err
 	}
@@ -122,6 +117,7 @@ func (cs *ConfigurationServer) Start() error {
 		StrategyStore:  cs.strategyStore,
 		Evaluator:      cs.evaluator,
 		GossipRegistry: cs.gossipRegistry,
+		SST:            cs.sst,
 	}); err != nil {
 		return err
 	}
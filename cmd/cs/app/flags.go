@@ -28,12 +28,22 @@ const (
 	DefaultHttpListenPort = ports.ConfigServerHttpListenPort
 
 	operationExpire        = "sampling.operation.expire"
+	relationExpire         = "sampling.relation.expire"
 	scaleFactor            = "sampling.scale.factor"
 	minSamplingRate        = "sampling.min.sampling.rate"
 	DefaultOperationExpire = time.Minute
+	DefaultRelationExpire  = time.Minute * 10
 	DefaultScaleFactor     = 1.0
 	DefaultMinSamplingRate = 0.01
 
+	tracesPerSecond        = "sampling.budget.traces.per.second"
+	DefaultTracesPerSecond = 0.0
+
+	strategyStorePath               = "sampling.strategy.store.path"
+	strategySnapshotInterval        = "sampling.strategy.snapshot.interval"
+	DefaultStrategyStorePath        = ""
+	DefaultStrategySnapshotInterval = time.Minute * 5
+
 	randomPick               = "gossip.random.pick"
 	probToR                  = "gossip.prob.to.r"
 	heartbeatInterval        = "gossip.refresh.interval"
@@ -46,8 +56,12 @@ type Flags struct {
 	GrpcListenPort    int
 	HttpListenPort    int
 	OperationExpire   time.Duration
+	RelationExpire    time.Duration
 	ScaleFactor       float64
 	MinSamplingRate   float64
+	TracesPerSecond   float64
+	StoragePath       string
+	SnapshotInterval  time.Duration
 	RandomPick        int
 	ProbToR           float64
 	HeartbeatInterval time.Duration
@@ -61,10 +75,19 @@ func AddFlags(flags *flag.FlagSet) {
 
 	flags.Duration(operationExpire, DefaultOperationExpire,
 		"[Sampling] Interval for removing expired operations.")
+	flags.Duration(relationExpire, DefaultRelationExpire,
+		"[Sampling] Time to live of relations between operations which are not observed by collectors.")
 	flags.Float64(scaleFactor, DefaultScaleFactor,
 		"[Sampling] Factor used to scale sampling rates for dynamic and adaptive sampling.")
 	flags.Float64(minSamplingRate, DefaultMinSamplingRate,
 		"[Sampling] Minimum sampling rate for dynamic and adaptive sampling.")
+	flags.Float64(tracesPerSecond, DefaultTracesPerSecond,
+		"[Sampling] Global budget of traces per second produced by all ingress operations with dynamic sampling. "+
+			"Sampling rates are solved from the budget instead of being scaled by scale factor if it is greater than 0.")
+	flags.String(strategyStorePath, DefaultStrategyStorePath,
+		"[Sampling] Directory to persist sampling strategies. Strategies are only kept in memory if it is empty.")
+	flags.Duration(strategySnapshotInterval, DefaultStrategySnapshotInterval,
+		"[Sampling] Interval for taking snapshots of persisted sampling strategies.")
 
 	flags.Int(randomPick, DefaultRandomPick,
 		"[Gossip] Number of peers a seed node send messages to when it received a message in single cycle.")
@@ -79,8 +102,12 @@ func (f *Flags) InitFromViper(v *viper.Viper) *Flags {
 	f.HttpListenPort = v.GetInt(httpListenPort)
 
 	f.OperationExpire = v.GetDuration(operationExpire)
+	f.RelationExpire = v.GetDuration(relationExpire)
 	f.ScaleFactor = v.GetFloat64(scaleFactor)
 	f.MinSamplingRate = v.GetFloat64(minSamplingRate)
+	f.TracesPerSecond = v.GetFloat64(tracesPerSecond)
+	f.StoragePath = v.GetString(strategyStorePath)
+	f.SnapshotInterval = v.GetDuration(strategySnapshotInterval)
 
 	f.RandomPick = v.GetInt(randomPick)
 	f.ProbToR = v.GetFloat64(probToR)
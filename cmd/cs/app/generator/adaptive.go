@@ -0,0 +1,57 @@
+// Copyright (c) 2021 The Houyi Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"github.com/golang/protobuf/proto"
+	"github.com/houyi-tracing/houyi/cmd/cs/app/store"
+	"github.com/houyi-tracing/houyi/idl/api_v1"
+	"go.uber.org/zap"
+	"math"
+)
+
+// adaptiveGenerator generates sampling rates only from QPS of ingress operations.
+type adaptiveGenerator struct {
+	logger          *zap.Logger
+	opStore         store.OperationStore
+	scaleFactor     float64
+	minSamplingRate float64
+}
+
+func NewAdaptiveGenerator(logger *zap.Logger,
+	opStore store.OperationStore,
+	scaleFactor float64,
+	minSamplingRate float64) RateGenerator {
+	return &adaptiveGenerator{
+		logger:          logger,
+		opStore:         opStore,
+		scaleFactor:     scaleFactor,
+		minSamplingRate: minSamplingRate,
+	}
+}
+
+func (g *adaptiveGenerator) Generate(op *api_v1.Operation, strategy *api_v1.PerOperationStrategy) (*api_v1.PerOperationStrategy, error) {
+	qpsWeight := g.opStore.QpsWeight(op)
+	ret := proto.Clone(strategy).(*api_v1.PerOperationStrategy)
+	ret.Strategy = &api_v1.PerOperationStrategy_Adaptive{
+		Adaptive: &api_v1.AdaptiveSampling{
+			SamplingRate: math.Min(math.Max(qpsWeight*g.scaleFactor, g.minSamplingRate), 1.0),
+		}}
+	g.logger.Debug("Generated adaptive strategy",
+		zap.String("service", op.GetService()),
+		zap.String("operation", op.GetOperation()),
+		zap.Float64("QPS weight", qpsWeight))
+	return ret, nil
+}
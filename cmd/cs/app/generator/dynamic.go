@@ -0,0 +1,86 @@
+// Copyright (c) 2021 The Houyi Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"github.com/golang/protobuf/proto"
+	"github.com/houyi-tracing/houyi/cmd/cs/app/store"
+	"github.com/houyi-tracing/houyi/idl/api_v1"
+	"github.com/houyi-tracing/houyi/pkg/sst"
+	"go.uber.org/zap"
+	"math"
+)
+
+// dynamicGenerator generates sampling rates from sampling strategy tree. The rate from tree is weighted by QPS and
+// scaled by scale factor, or it is solved from global budget if budget is set.
+type dynamicGenerator struct {
+	logger          *zap.Logger
+	sst             sst.SamplingStrategyTree
+	opStore         store.OperationStore
+	budget          store.Budget
+	scaleFactor     float64
+	minSamplingRate float64
+}
+
+// NewDynamicGenerator returns a rate generator for dynamic sampling. Budget could be nil.
+func NewDynamicGenerator(logger *zap.Logger,
+	sst sst.SamplingStrategyTree,
+	opStore store.OperationStore,
+	budget store.Budget,
+	scaleFactor float64,
+	minSamplingRate float64) RateGenerator {
+	return &dynamicGenerator{
+		logger:          logger,
+		sst:             sst,
+		opStore:         opStore,
+		budget:          budget,
+		scaleFactor:     scaleFactor,
+		minSamplingRate: minSamplingRate,
+	}
+}
+
+func (g *dynamicGenerator) Generate(op *api_v1.Operation, strategy *api_v1.PerOperationStrategy) (*api_v1.PerOperationStrategy, error) {
+	if !g.sst.Has(op) {
+		_ = g.sst.Add(op)
+	}
+	sr, err := g.sst.Generate(op)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := proto.Clone(strategy).(*api_v1.PerOperationStrategy)
+	if g.budget != nil {
+		ret.Strategy = &api_v1.PerOperationStrategy_Dynamic{
+			Dynamic: &api_v1.DynamicSampling{
+				SamplingRate: g.budget.SamplingRate(op),
+			}}
+		g.logger.Debug("Generated dynamic strategy from budget",
+			zap.String("service", op.GetService()),
+			zap.String("operation", op.GetOperation()),
+			zap.Float64("SST", sr))
+	} else {
+		qpsWeight := g.opStore.QpsWeight(op)
+		ret.Strategy = &api_v1.PerOperationStrategy_Dynamic{
+			Dynamic: &api_v1.DynamicSampling{
+				SamplingRate: math.Min(math.Max(sr*qpsWeight*g.scaleFactor, g.minSamplingRate), 1.0),
+			}}
+		g.logger.Debug("Generated dynamic strategy",
+			zap.String("service", op.GetService()),
+			zap.String("operation", op.GetOperation()),
+			zap.Float64("SST", sr),
+			zap.Float64("QPS weight", qpsWeight))
+	}
+	return ret, nil
+}
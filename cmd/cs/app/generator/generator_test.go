@@ -0,0 +1,64 @@
+// Copyright (c) 2021 The Houyi Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"github.com/houyi-tracing/houyi/cmd/cs/app/store"
+	"github.com/houyi-tracing/houyi/idl/api_v1"
+	"github.com/houyi-tracing/houyi/pkg/sst"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"testing"
+)
+
+type fixedQpsWeight struct {
+	store.OperationStore
+	weight float64
+}
+
+func (s *fixedQpsWeight) QpsWeight(_ *api_v1.Operation) float64 {
+	return s.weight
+}
+
+func TestGeneratorsMustBeSelectedByType(t *testing.T) {
+	logger := zap.NewNop()
+	opStore := &fixedQpsWeight{weight: 0.5}
+	tree := sst.NewSamplingStrategyTree(sst.DefaultOrder)
+
+	r := NewRegistry()
+	r.Register(api_v1.Type_DYNAMIC, NewDynamicGenerator(logger, tree, opStore, nil, 1.0, 0.01))
+	r.Register(api_v1.Type_ADAPTIVE, NewAdaptiveGenerator(logger, opStore, 1.0, 0.01))
+
+	_, has := r.Get(api_v1.Type_CONST)
+	assert.False(t, has)
+
+	op := &api_v1.Operation{Service: "svc", Operation: "op"}
+	strategy := &api_v1.PerOperationStrategy{Type: api_v1.Type_DYNAMIC}
+	g, has := r.Get(strategy.GetType())
+	assert.True(t, has)
+	generated, err := g.Generate(op, strategy)
+	assert.Nil(t, err)
+	assert.True(t, tree.Has(op))
+	assert.Equal(t, 0.5, generated.GetDynamic().GetSamplingRate())
+	assert.Nil(t, strategy.GetStrategy(), "strategy passed in must not be modified")
+
+	strategy = &api_v1.PerOperationStrategy{Type: api_v1.Type_ADAPTIVE}
+	g, has = r.Get(strategy.GetType())
+	assert.True(t, has)
+	generated, err = g.Generate(op, strategy)
+	assert.Nil(t, err)
+	assert.Equal(t, 0.5, generated.GetAdaptive().GetSamplingRate())
+	assert.Nil(t, strategy.GetStrategy(), "strategy passed in must not be modified")
+}
@@ -0,0 +1,63 @@
+// Copyright (c) 2021 The Houyi Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"github.com/houyi-tracing/houyi/idl/api_v1"
+	"sync"
+)
+
+// RateGenerator generates sampling rates for ingress operations whose strategies have the type it is registered with.
+type RateGenerator interface {
+	// Generate returns a copy of strategy with the generated sampling rate of inputted ingress operation. It must not
+	// modify strategy, which may be shared by strategy store.
+	Generate(op *api_v1.Operation, strategy *api_v1.PerOperationStrategy) (*api_v1.PerOperationStrategy, error)
+}
+
+// Registry stores rate generators by types of strategies.
+type Registry interface {
+	// Register registers a rate generator for inputted type and replaces the one registered before.
+	Register(t api_v1.Type, g RateGenerator)
+
+	// Get returns the rate generator registered for inputted type.
+	Get(t api_v1.Type) (RateGenerator, bool)
+}
+
+type registry struct {
+	sync.RWMutex
+
+	generators map[api_v1.Type]RateGenerator
+}
+
+func NewRegistry() Registry {
+	return &registry{
+		generators: make(map[api_v1.Type]RateGenerator),
+	}
+}
+
+func (r *registry) Register(t api_v1.Type, g RateGenerator) {
+	r.Lock()
+	defer r.Unlock()
+
+	r.generators[t] = g
+}
+
+func (r *registry) Get(t api_v1.Type) (RateGenerator, bool) {
+	r.RLock()
+	defer r.RUnlock()
+
+	g, has := r.generators[t]
+	return g, has
+}
@@ -16,6 +16,8 @@ package grpc
 
 import (
 	"context"
+	"github.com/golang/protobuf/proto"
+	"github.com/houyi-tracing/houyi/cmd/cs/app/generator"
 	"github.com/houyi-tracing/houyi/cmd/cs/app/store"
 	"github.com/houyi-tracing/houyi/idl/api_v1"
 	"github.com/houyi-tracing/houyi/pkg/evaluator"
@@ -23,21 +25,19 @@ import (
 	"github.com/houyi-tracing/houyi/pkg/sst"
 	"github.com/houyi-tracing/houyi/pkg/tg"
 	"go.uber.org/zap"
-	"math"
 )
 
 type StrategyManagerGrpcHandler struct {
 	api_v1.UnimplementedStrategyManagerServer
 
-	logger          *zap.Logger
-	scaleFactor     float64
-	sst             sst.SamplingStrategyTree
-	tg              tg.TraceGraph
-	operationStore  store.OperationStore
-	strategyStore   store.StrategyStore
-	eval            evaluator.Evaluator
-	gossipSeed      gossip.Seed
-	minSamplingRate float64
+	logger         *zap.Logger
+	sst            sst.SamplingStrategyTree
+	tg             tg.TraceGraph
+	operationStore store.OperationStore
+	strategyStore  store.StrategyStore
+	eval           evaluator.Evaluator
+	gossipSeed     gossip.Seed
+	generators     generator.Registry
 }
 
 func NewStrategyManagerGrpcHandler(logger *zap.Logger,
@@ -45,39 +45,43 @@ func NewStrategyManagerGrpcHandler(logger *zap.Logger,
 	tg tg.TraceGraph,
 	opStore store.OperationStore,
 	eval evaluator.Evaluator,
-	scaleFactor float64,
 	strategyStore store.StrategyStore,
-	minSamplingRate float64,
-	seed gossip.Seed) *StrategyManagerGrpcHandler {
+	seed gossip.Seed,
+	generators generator.Registry) *StrategyManagerGrpcHandler {
 	return &StrategyManagerGrpcHandler{
-		logger:          logger,
-		sst:             sst,
-		tg:              tg,
-		operationStore:  opStore,
-		eval:            eval,
-		scaleFactor:     scaleFactor,
-		minSamplingRate: minSamplingRate,
-		strategyStore:   strategyStore,
-		gossipSeed:      seed,
+		logger:         logger,
+		sst:            sst,
+		tg:             tg,
+		operationStore: opStore,
+		eval:           eval,
+		strategyStore:  strategyStore,
+		gossipSeed:     seed,
+		generators:     generators,
 	}
 }
 
-func (h *StrategyManagerGrpcHandler) Promote(_ context.Context, request *api_v1.Operation) (*api_v1.NullRely, error) {
+func (h *StrategyManagerGrpcHandler) Promote(_ context.Context, request *api_v1.PromoteRequest) (*api_v1.NullRely, error) {
 	h.logger.Debug("Received request to Promote", zap.String("request", request.String()))
 
 	reply := &api_v1.NullRely{}
-	if h.tg.IsIngress(request) {
-		err := h.sst.Promote(request)
+	op := &api_v1.Operation{
+		Service:   request.GetService(),
+		Operation: request.GetOperation(),
+	}
+	weight := int(request.GetWeight())
+	if h.tg.IsIngress(op) {
+		err := h.sst.Promote(op, weight)
 		return reply, err
 	} else {
-		if ingress, err := h.tg.GetIngresses(request); err != nil {
+		if ingress, err := h.tg.GetIngresses(op); err != nil {
 			return reply, err
 		} else {
 			for _, i := range ingress {
 				h.logger.Debug("Promoted operation",
 					zap.String("service", i.GetService()),
-					zap.String("operation", i.GetOperation()))
-				err = h.sst.Promote(i)
+					zap.String("operation", i.GetOperation()),
+					zap.Int("weight", weight))
+				err = h.sst.Promote(i, weight)
 			}
 			return reply, err
 		}
@@ -104,8 +108,8 @@ func (h *StrategyManagerGrpcHandler) GetStrategies(_ context.Context, request *a
 
 func (h *StrategyManagerGrpcHandler) perOperationStrategy(opModel *api_v1.Operation, isIngress bool) *api_v1.PerOperationStrategy {
 	if !h.tg.Has(opModel) {
-		h.gossipSeed.MongerNewOperation(opModel)
 		_ = h.tg.Add(opModel)
+		h.gossipSeed.MongerGraphDelta(h.tg.FlushDelta())
 	}
 
 	var ret *api_v1.PerOperationStrategy
@@ -137,31 +141,22 @@ func (h *StrategyManagerGrpcHandler) perOperationStrategy(opModel *api_v1.Operat
 			zap.String("service", svc), zap.String("operation", op), zap.Bool("isIngress", isIngress))
 	}
 
-	if ret.GetType() == api_v1.Type_DYNAMIC && isIngress {
-		if !h.sst.Has(opModel) {
-			_ = h.sst.Add(opModel)
+	generated := false
+	if isIngress {
+		if g, has := h.generators.Get(ret.GetType()); has {
+			if s, err := g.Generate(opModel, ret); err != nil {
+				h.logger.Error("failed to generate sampling rate",
+					zap.String("service", svc),
+					zap.String("operation", op),
+					zap.Error(err))
+			} else {
+				ret, generated = s, true
+			}
 		}
-		sr, _ := h.sst.Generate(opModel)
-		qpsWeight := h.operationStore.QpsWeight(opModel)
-		ret.Strategy = &api_v1.PerOperationStrategy_Dynamic{
-			Dynamic: &api_v1.DynamicSampling{
-				SamplingRate: math.Min(math.Max(sr*qpsWeight*h.scaleFactor, h.minSamplingRate), 1.0),
-			}}
-		h.logger.Debug("Generated dynamic strategy",
-			zap.String("service", svc),
-			zap.String("operation", op),
-			zap.Float64("SST", sr),
-			zap.Float64("QPS weight", qpsWeight))
-	} else if ret.GetType() == api_v1.Type_ADAPTIVE && isIngress {
-		qpsWeight := h.operationStore.QpsWeight(opModel)
-		ret.Strategy = &api_v1.PerOperationStrategy_Adaptive{
-			Adaptive: &api_v1.AdaptiveSampling{
-				SamplingRate: math.Min(math.Max(qpsWeight*h.scaleFactor, h.minSamplingRate), 1.0),
-			}}
-		h.logger.Debug("Generated adaptive strategy",
-			zap.String("service", svc),
-			zap.String("operation", op),
-			zap.Float64("QPS weight", qpsWeight))
+	}
+	if !generated {
+		// ret is shared by strategy store and other requests, so it must be copied before being modified.
+		ret = proto.Clone(ret).(*api_v1.PerOperationStrategy)
 	}
 
 	h.logger.Debug("Generated Strategy",
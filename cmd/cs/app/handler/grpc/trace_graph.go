@@ -0,0 +1,73 @@
+// Copyright (c) 2021 The Houyi Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpc
+
+import (
+	"github.com/houyi-tracing/houyi/idl/api_v1"
+	"github.com/houyi-tracing/houyi/pkg/tg"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// watchBufferSize is the number of events buffered for each watcher.
+	watchBufferSize = 1024
+)
+
+type TraceGraphGrpcHandler struct {
+	api_v1.UnimplementedTraceGraphManagerServer
+
+	logger *zap.Logger
+	tg     tg.TraceGraph
+}
+
+func NewTraceGraphGrpcHandler(logger *zap.Logger, traceGraph tg.TraceGraph) api_v1.TraceGraphManagerServer {
+	return &TraceGraphGrpcHandler{
+		logger: logger,
+		tg:     traceGraph,
+	}
+}
+
+// Watch streams changes of trace graph until the client cancels the request. The stream fails with DataLoss if events
+// are dropped because the client could not keep up, after which the client should get trace graph again and watch it
+// again.
+func (h *TraceGraphGrpcHandler) Watch(req *api_v1.WatchRequest, stream api_v1.TraceGraphManager_WatchServer) error {
+	sub := h.tg.Subscribe(watchBufferSize)
+	defer sub.Close()
+
+	svc := req.GetService()
+	h.logger.Debug("started watching trace graph", zap.String("service", svc))
+	for {
+		select {
+		case e, ok := <-sub.Events():
+			if !ok {
+				h.logger.Warn("stopped watching trace graph because events were dropped",
+					zap.String("service", svc), zap.Uint64("dropped events", sub.Dropped()))
+				return status.Error(codes.DataLoss, "events of trace graph were dropped, resync and watch again")
+			}
+			if svc != "" && !tg.EventRelatesTo(e, svc) {
+				continue
+			}
+			if err := stream.Send(e); err != nil {
+				h.logger.Debug("stopped watching trace graph", zap.Error(err))
+				return err
+			}
+		case <-stream.Context().Done():
+			h.logger.Debug("stopped watching trace graph", zap.String("service", svc))
+			return nil
+		}
+	}
+}
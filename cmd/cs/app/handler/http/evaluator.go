@@ -27,6 +27,7 @@ import (
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
 	"net/http"
+	"sort"
 )
 
 const (
@@ -61,6 +62,9 @@ func NewEvaluatorHttpHandler(params *EvaluatorHttpHandlerParams) *EvaluatorHttpH
 func (h *EvaluatorHttpHandler) RegisterRoutes(e *gin.Engine) {
 	e.GET(route.GetEvaluatorTagsRoute, h.getEvaluatorTags)
 	e.POST(route.UpdateEvaluatorTagsRoute, h.updateEvaluatorTags)
+	e.GET(route.GetEvaluatorRulesRoute, h.getEvaluatorRules)
+	e.POST(route.UpdateEvaluatorRulesRoute, h.updateEvaluatorRules)
+	e.GET(route.GetEvaluatorMatchesRoute, h.getEvaluatorMatches)
 }
 
 func (h *EvaluatorHttpHandler) getEvaluatorTags(c *gin.Context) {
@@ -78,14 +82,10 @@ func (h *EvaluatorHttpHandler) updateEvaluatorTags(c *gin.Context) {
 	tags := make([]model.Tag, 0)
 	err := c.BindJSON(&tags)
 	if err == nil {
-		convertedTags := convertToTags(tags)
-		peers := h.registry.AllSeeds()
-		h.eval.Update(&api_v1.EvaluatingTags{
-			Tags: convertedTags,
+		h.update(&api_v1.EvaluatingTags{
+			Tags:  convertToTags(tags),
+			Rules: h.eval.Get().GetRules(),
 		})
-		for _, p := range peers {
-			h.doUpdate(p.GetIp(), convertedTags)
-		}
 		c.JSON(http.StatusOK, gin.H{
 			"result": "OK",
 		})
@@ -97,24 +97,119 @@ func (h *EvaluatorHttpHandler) updateEvaluatorTags(c *gin.Context) {
 	}
 }
 
-func (h *EvaluatorHttpHandler) doUpdate(ip string, tags []*api_v1.EvaluatingTag) {
+func (h *EvaluatorHttpHandler) getEvaluatorRules(c *gin.Context) {
+	c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
+
+	rules := h.eval.Get()
+	c.JSON(http.StatusOK, gin.H{
+		"result": convertToJsonRules(rules.GetRules()),
+	})
+}
+
+func (h *EvaluatorHttpHandler) updateEvaluatorRules(c *gin.Context) {
+	c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
+
+	rules := make([]model.Rule, 0)
+	if err := c.BindJSON(&rules); err != nil {
+		h.logger.Error("failed to parse JSON from request's body", zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"result": err.Error(),
+		})
+		return
+	}
+	for _, r := range rules {
+		if _, err := evaluator.Compile(r.Expression); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"result": fmt.Sprintf("invalid expression of rule %q: %v", r.Name, err),
+			})
+			return
+		}
+	}
+
+	h.update(&api_v1.EvaluatingTags{
+		Tags:  h.eval.Get().GetTags(),
+		Rules: convertToRules(rules),
+	})
+	c.JSON(http.StatusOK, gin.H{
+		"result": "OK",
+	})
+}
+
+// getEvaluatorMatches replies the numbers of spans matching each evaluating tag and rule summed over all collectors.
+func (h *EvaluatorHttpHandler) getEvaluatorMatches(c *gin.Context) {
+	c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
+
+	counts := make(map[model.Match]int64)
+	for _, p := range h.registry.AllSeeds() {
+		for _, m := range h.doGetMatches(p.GetIp()) {
+			counts[model.Match{Name: m.GetName(), DryRun: m.GetDryRun()}] += m.GetCount()
+		}
+	}
+
+	matches := make([]model.Match, 0, len(counts))
+	for m, count := range counts {
+		m.Count = count
+		matches = append(matches, m)
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].Name < matches[j].Name ||
+			(matches[i].Name == matches[j].Name && !matches[i].DryRun && matches[j].DryRun)
+	})
+	c.JSON(http.StatusOK, gin.H{
+		"result": matches,
+	})
+}
+
+// update updates the local evaluator and the evaluators of all collectors.
+func (h *EvaluatorHttpHandler) update(tags *api_v1.EvaluatingTags) {
+	h.eval.Update(tags)
+	for _, p := range h.registry.AllSeeds() {
+		h.doUpdate(p.GetIp(), tags)
+	}
+}
+
+func (h *EvaluatorHttpHandler) doUpdate(ip string, tags *api_v1.EvaluatingTags) {
 	conn, err := grpc.Dial(fmt.Sprintf("%s:%d", ip, ports.CollectorGrpcListenPort), grpc.WithInsecure())
 	if err != nil {
 		h.logger.Debug("failed to dail collector", zap.String("ip", ip))
 		return
 	}
 	c := api_v1.NewEvaluatorManagerClient(conn)
-	_, err = c.UpdateTags(context.TODO(), &api_v1.UpdateTagsRequest{Tags: tags})
+	_, err = c.UpdateTags(context.TODO(), &api_v1.UpdateTagsRequest{
+		Tags:  tags.GetTags(),
+		Rules: tags.GetRules(),
+	})
 	if err != nil {
 		h.logger.Error("failed to send request for updating tags", zap.Error(err))
 	}
 }
 
+func (h *EvaluatorHttpHandler) doGetMatches(ip string) []*api_v1.EvaluatorMatch {
+	conn, err := grpc.Dial(fmt.Sprintf("%s:%d", ip, ports.CollectorGrpcListenPort), grpc.WithInsecure())
+	if err != nil {
+		h.logger.Debug("failed to dail collector", zap.String("ip", ip))
+		return nil
+	}
+	defer conn.Close()
+
+	c := api_v1.NewEvaluatorManagerClient(conn)
+	resp, err := c.GetMatches(context.TODO(), &api_v1.GetMatchesRequest{})
+	if err != nil {
+		h.logger.Error("failed to get matches of evaluator", zap.String("ip", ip), zap.Error(err))
+		return nil
+	}
+	return resp.GetMatches()
+}
+
 func convertToJsonTags(tags []*api_v1.EvaluatingTag) []model.Tag {
 	ret := make([]model.Tag, 0)
 	for _, t := range tags {
 		newTag := model.Tag{}
 		newTag.Name = t.TagName
+		newTag.Weight = int(t.Weight)
+		newTag.Service = t.Service
+		newTag.Operation = t.Operation
+		newTag.DryRun = t.DryRun
 
 		switch t.OperationType {
 		case api_v1.EvaluatingTag_EQUAL_TO:
@@ -152,6 +247,10 @@ func convertToTags(tags []model.Tag) []*api_v1.EvaluatingTag {
 	for _, t := range tags {
 		newTag := &api_v1.EvaluatingTag{}
 		newTag.TagName = t.Name
+		newTag.Weight = int32(t.Weight)
+		newTag.Service = t.Service
+		newTag.Operation = t.Operation
+		newTag.DryRun = t.DryRun
 
 		switch t.Operator {
 		case EqualTo:
@@ -228,6 +327,36 @@ func convertToTags(tags []model.Tag) []*api_v1.EvaluatingTag {
 	return ret
 }
 
+func convertToJsonRules(rules []*api_v1.EvaluatingRule) []model.Rule {
+	ret := make([]model.Rule, 0, len(rules))
+	for _, r := range rules {
+		ret = append(ret, model.Rule{
+			Name:       r.GetName(),
+			Expression: r.GetExpression(),
+			Weight:     int(r.GetWeight()),
+			Service:    r.GetService(),
+			Operation:  r.GetOperation(),
+			DryRun:     r.GetDryRun(),
+		})
+	}
+	return ret
+}
+
+func convertToRules(rules []model.Rule) []*api_v1.EvaluatingRule {
+	ret := make([]*api_v1.EvaluatingRule, 0, len(rules))
+	for _, r := range rules {
+		ret = append(ret, &api_v1.EvaluatingRule{
+			Name:       r.Name,
+			Expression: r.Expression,
+			Weight:     int32(r.Weight),
+			Service:    r.Service,
+			Operation:  r.Operation,
+			DryRun:     r.DryRun,
+		})
+	}
+	return ret
+}
+
 func IsInteger(n float64) bool {
 	return n-float64(int(n)) == 0
 }
@@ -18,4 +18,26 @@ type Tag struct {
 	Name     string      `json:"name"`
 	Operator string      `json:"operator"`
 	Value    interface{} `json:"value"`
+	Weight   int         `json:"weight,omitempty"`
+	// Service and Operation are glob patterns of services and operations of spans this tag applies to.
+	Service   string `json:"service,omitempty"`
+	Operation string `json:"operation,omitempty"`
+	// DryRun counts spans matching this tag without promoting their operations.
+	DryRun bool `json:"dryRun,omitempty"`
+}
+
+type Rule struct {
+	Name       string `json:"name"`
+	Expression string `json:"expression"`
+	Weight     int    `json:"weight,omitempty"`
+	Service    string `json:"service,omitempty"`
+	Operation  string `json:"operation,omitempty"`
+	DryRun     bool   `json:"dryRun,omitempty"`
+}
+
+// Match is the number of spans matching an evaluating tag or rule.
+type Match struct {
+	Name   string `json:"name"`
+	DryRun bool   `json:"dryRun,omitempty"`
+	Count  int64  `json:"count"`
 }
@@ -0,0 +1,69 @@
+// Copyright (c) 2021 The Houyi Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/houyi-tracing/houyi/pkg/sst"
+	"github.com/houyi-tracing/houyi/route"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+const (
+	FormatJson = "json"
+	FormatDot  = "dot"
+)
+
+type SamplingStrategyTreeHttpHandlerParams struct {
+	Logger *zap.Logger
+	SST    sst.SamplingStrategyTree
+}
+
+type SamplingStrategyTreeHttpHandler struct {
+	logger *zap.Logger
+	sst    sst.SamplingStrategyTree
+}
+
+func NewSamplingStrategyTreeHttpHandler(params *SamplingStrategyTreeHttpHandlerParams) *SamplingStrategyTreeHttpHandler {
+	return &SamplingStrategyTreeHttpHandler{
+		logger: params.Logger,
+		sst:    params.SST,
+	}
+}
+
+func (h *SamplingStrategyTreeHttpHandler) RegisterRoutes(e *gin.Engine) {
+	e.GET(route.GetSamplingStrategyTreeRoute, h.getSamplingStrategyTree)
+}
+
+func (h *SamplingStrategyTreeHttpHandler) getSamplingStrategyTree(c *gin.Context) {
+	c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
+
+	format := c.DefaultQuery("format", FormatJson)
+	h.logger.Debug("getSamplingStrategyTree", zap.String("format", format))
+
+	switch format {
+	case FormatJson:
+		c.JSON(http.StatusOK, gin.H{
+			"result": h.sst.Dump(),
+		})
+	case FormatDot:
+		c.String(http.StatusOK, sst.Dot(h.sst.Dump()))
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{
+			"result": "parameter format must be json or dot",
+		})
+	}
+}
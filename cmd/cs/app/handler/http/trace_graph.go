@@ -15,12 +15,24 @@
 package http
 
 import (
+	"encoding/json"
+	"fmt"
 	"github.com/gin-gonic/gin"
 	"github.com/houyi-tracing/houyi/idl/api_v1"
 	"github.com/houyi-tracing/houyi/pkg/tg"
 	"github.com/houyi-tracing/houyi/route"
 	"go.uber.org/zap"
+	"io"
 	"net/http"
+	"strconv"
+)
+
+const (
+	// watchBufferSize is the number of events buffered for each watcher.
+	watchBufferSize = 1024
+
+	// eventsDroppedEvent is the name of the last server-sent event of a watcher which could not keep up.
+	eventsDroppedEvent = "EVENTS_DROPPED"
 )
 
 type TraceGraphHttpHandlerParams struct {
@@ -44,7 +56,16 @@ func (h *TraceGraphHttpHandler) RegisterRoutes(e *gin.Engine) {
 	e.GET(route.GetServicesRoute, h.getServices)
 	e.GET(route.GetOperationsRoute, h.getOperations)
 	e.GET(route.GetCausalDependenciesRoute, h.getCausalDependencies)
+	e.GET(route.GetUpstreamRoute, h.getUpstream)
+	e.GET(route.GetDownstreamRoute, h.getDownstream)
+	e.GET(route.GetCallPathRoute, h.getCallPath)
+	e.GET(route.GetDependentServicesRoute, h.getDependentServices)
 	e.GET(route.GetIngressServicesRoute, h.getIngressServices)
+	e.GET(route.GetTraceGraphRoute, h.getTraceGraph)
+	e.GET(route.GetDependencyLinksRoute, h.getDependencyLinks)
+	e.GET(route.WatchTraceGraphRoute, h.watchTraceGraph)
+	e.POST(route.DiffTraceGraphRoute, h.diffTraceGraph)
+	e.GET(route.GetTraceGraphSnapshotRoute, h.getTraceGraphSnapshot)
 }
 
 func (h *TraceGraphHttpHandler) getServices(c *gin.Context) {
@@ -89,8 +110,10 @@ func (h *TraceGraphHttpHandler) getCausalDependencies(c *gin.Context) {
 
 	svc := c.Query("service")
 	op := c.Query("operation")
+	format := c.DefaultQuery("format", FormatTree)
 
-	h.logger.Debug("getCausalDependencies", zap.String("service", svc), zap.String("operation", op))
+	h.logger.Debug("getCausalDependencies",
+		zap.String("service", svc), zap.String("operation", op), zap.String("format", format))
 
 	if svc == "" {
 		c.JSON(http.StatusBadRequest, gin.H{
@@ -104,11 +127,30 @@ func (h *TraceGraphHttpHandler) getCausalDependencies(c *gin.Context) {
 		})
 		return
 	}
+	maxDepth, err := strconv.Atoi(c.DefaultQuery("maxDepth", "0"))
+	if err != nil || maxDepth < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"result": "parameter maxDepth must be a non-negative integer",
+		})
+		return
+	}
 
-	dependencies, err := h.tg.Dependencies(&api_v1.Operation{
+	operation := &api_v1.Operation{
 		Service:   svc,
 		Operation: op,
-	})
+	}
+	var dependencies interface{}
+	switch format {
+	case FormatTree:
+		dependencies, err = h.tg.Dependencies(operation, maxDepth)
+	case FormatDag:
+		dependencies, err = h.tg.DependencyGraph(operation, maxDepth)
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{
+			"result": "parameter format must be tree or dag",
+		})
+		return
+	}
 	if err != nil {
 		h.logger.Error("failed to get dependencies", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -120,3 +162,259 @@ func (h *TraceGraphHttpHandler) getCausalDependencies(c *gin.Context) {
 		})
 	}
 }
+
+func (h *TraceGraphHttpHandler) getUpstream(c *gin.Context) {
+	c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
+
+	if op, ok := queryOperation(c, "service", "operation"); ok {
+		upstream, err := h.tg.Upstream(op)
+		h.reply(c, "failed to get upstream operations", upstream, err)
+	}
+}
+
+func (h *TraceGraphHttpHandler) getDownstream(c *gin.Context) {
+	c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
+
+	if op, ok := queryOperation(c, "service", "operation"); ok {
+		downstream, err := h.tg.Downstream(op)
+		h.reply(c, "failed to get downstream operations", downstream, err)
+	}
+}
+
+func (h *TraceGraphHttpHandler) getCallPath(c *gin.Context) {
+	c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
+
+	from, ok := queryOperation(c, "fromService", "fromOperation")
+	if !ok {
+		return
+	}
+	to, ok := queryOperation(c, "toService", "toOperation")
+	if !ok {
+		return
+	}
+	path, err := h.tg.ShortestPath(from, to)
+	h.reply(c, "failed to get call path", path, err)
+}
+
+func (h *TraceGraphHttpHandler) getDependentServices(c *gin.Context) {
+	c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
+
+	if op, ok := queryOperation(c, "service", "operation"); ok {
+		services, err := h.tg.DependentServices(op)
+		h.reply(c, "failed to get dependent services", services, err)
+	}
+}
+
+func (h *TraceGraphHttpHandler) reply(c *gin.Context, errMsg string, result interface{}, err error) {
+	if err != nil {
+		h.logger.Error(errMsg, zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"result": err.Error(),
+		})
+	} else {
+		c.JSON(http.StatusOK, gin.H{
+			"result": result,
+		})
+	}
+}
+
+// queryOperation returns the operation whose service and operation are in query parameters of inputted keys. It
+// writes an error response and returns false if any of them is not set.
+func queryOperation(c *gin.Context, svcKey, opKey string) (*api_v1.Operation, bool) {
+	svc, op := c.Query(svcKey), c.Query(opKey)
+	if svc == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"result": fmt.Sprintf("parameter %s must be set", svcKey),
+		})
+		return nil, false
+	}
+	if op == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"result": fmt.Sprintf("parameter %s must be set", opKey),
+		})
+		return nil, false
+	}
+	return &api_v1.Operation{
+		Service:   svc,
+		Operation: op,
+	}, true
+}
+
+// formats of trace graph in addition to FormatJson and FormatDot.
+const (
+	FormatGraphML = "graphml"
+	FormatTree    = "tree"
+	FormatDag     = "dag"
+)
+
+func (h *TraceGraphHttpHandler) getTraceGraph(c *gin.Context) {
+	c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
+
+	format := c.DefaultQuery("format", FormatJson)
+	h.logger.Debug("getTraceGraph", zap.String("format", format))
+
+	if format != FormatJson && format != FormatDot && format != FormatGraphML {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"result": "parameter format must be json, dot or graphml",
+		})
+		return
+	}
+
+	g, ok := h.graph(c)
+	if !ok {
+		return
+	}
+	switch format {
+	case FormatJson:
+		c.JSON(http.StatusOK, gin.H{
+			"result": g,
+		})
+	case FormatDot:
+		c.String(http.StatusOK, tg.Dot(g))
+	case FormatGraphML:
+		if data, err := tg.GraphML(g); err != nil {
+			h.logger.Error("failed to export trace graph as GraphML", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"result": err.Error(),
+			})
+		} else {
+			c.Data(http.StatusOK, "application/xml; charset=utf-8", data)
+		}
+	}
+}
+
+func (h *TraceGraphHttpHandler) getDependencyLinks(c *gin.Context) {
+	c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
+
+	if g, ok := h.graph(c); ok {
+		c.JSON(http.StatusOK, gin.H{
+			"result": tg.DependencyLinks(g),
+		})
+	}
+}
+
+// graph returns the subgraph reachable from the operation in query parameters, or the whole trace graph if neither
+// service nor operation is set. It writes an error response and returns false if it failed.
+func (h *TraceGraphHttpHandler) graph(c *gin.Context) (*tg.Graph, bool) {
+	svc := c.Query("service")
+	op := c.Query("operation")
+
+	var from *api_v1.Operation
+	if svc != "" || op != "" {
+		if svc == "" || op == "" {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"result": "parameters service and operation must be set together",
+			})
+			return nil, false
+		}
+		from = &api_v1.Operation{
+			Service:   svc,
+			Operation: op,
+		}
+	}
+
+	g, err := h.tg.Graph(from)
+	if err != nil {
+		h.logger.Error("failed to get trace graph", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"result": err.Error(),
+		})
+		return nil, false
+	}
+	return g, true
+}
+
+// watchTraceGraph streams changes of trace graph as server-sent events until the client disconnects. The name of
+// every event is its type. Only changes relate to parameter service are sent if it is set. If events are dropped
+// because the client could not keep up, an EVENTS_DROPPED event is sent and then the stream is closed, after which
+// the client should diff trace graph against its last snapshot and watch again.
+func (h *TraceGraphHttpHandler) watchTraceGraph(c *gin.Context) {
+	c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
+
+	svc := c.Query("service")
+	sub := h.tg.Subscribe(watchBufferSize)
+	defer sub.Close()
+
+	h.logger.Debug("watchTraceGraph", zap.String("service", svc))
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case e, ok := <-sub.Events():
+			if !ok {
+				h.logger.Warn("stopped watching trace graph because events were dropped",
+					zap.String("service", svc), zap.Uint64("dropped events", sub.Dropped()))
+				c.SSEvent(eventsDroppedEvent, gin.H{
+					"dropped": sub.Dropped(),
+				})
+				return false
+			}
+			if svc == "" || tg.EventRelatesTo(e, svc) {
+				c.SSEvent(e.GetType().String(), e)
+			}
+			return true
+		case <-c.Request.Context().Done():
+			return false
+		}
+	})
+}
+
+// getTraceGraphSnapshot returns a snapshot of the current trace graph, which could be sent back later as snapshot
+// from of diffTraceGraph to get changes since it was taken. The snapshot is not wrapped in result.
+func (h *TraceGraphHttpHandler) getTraceGraphSnapshot(c *gin.Context) {
+	c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
+
+	data, err := h.tg.Snapshot()
+	if err != nil {
+		h.logger.Error("failed to take snapshot of trace graph", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"result": err.Error(),
+		})
+		return
+	}
+	c.Data(http.StatusOK, "application/json; charset=utf-8", data)
+}
+
+type diffRequest struct {
+	From json.RawMessage `json:"from"`
+	To   json.RawMessage `json:"to"`
+}
+
+// diffTraceGraph returns changes from snapshot from to snapshot to in request body, both of which are returned by
+// getTraceGraphSnapshot. Snapshot to defaults to the current trace graph.
+func (h *TraceGraphHttpHandler) diffTraceGraph(c *gin.Context) {
+	c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
+
+	req := &diffRequest{}
+	if err := c.BindJSON(req); err != nil {
+		h.logger.Error("failed to parse JSON from request's body", zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"result": err.Error(),
+		})
+		return
+	}
+	if len(req.From) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"result": "snapshot from must be set",
+		})
+		return
+	}
+
+	from, err := tg.ParseSnapshot(req.From)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"result": err.Error(),
+		})
+		return
+	}
+	to := h.tg.State()
+	if len(req.To) != 0 && string(req.To) != "null" {
+		if to, err = tg.ParseSnapshot(req.To); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"result": err.Error(),
+			})
+			return
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"result": tg.Diff(from, to),
+	})
+}
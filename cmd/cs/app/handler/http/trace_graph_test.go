@@ -0,0 +1,107 @@
+// Copyright (c) 2021 The Houyi Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/houyi-tracing/houyi/idl/api_v1"
+	"github.com/houyi-tracing/houyi/pkg/tg"
+	"github.com/houyi-tracing/houyi/route"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// slowGraph returns subscriptions which already dropped events, as if watchers could not keep up.
+type slowGraph struct {
+	tg.TraceGraph
+}
+
+func (g *slowGraph) Subscribe(int) *tg.Subscription {
+	sub := g.TraceGraph.Subscribe(0)
+	_ = g.TraceGraph.Add(&api_v1.Operation{Service: "svc", Operation: "op"})
+	return sub
+}
+
+func TestDiffSinceTraceGraphSnapshot(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	graph := tg.NewTraceGraph(zap.NewNop())
+	e := gin.New()
+	NewTraceGraphHttpHandler(&TraceGraphHttpHandlerParams{
+		Logger:     zap.NewNop(),
+		TraceGraph: graph,
+	}).RegisterRoutes(e)
+
+	op0 := &api_v1.Operation{Service: "svc0", Operation: "op0"}
+	op1 := &api_v1.Operation{Service: "svc1", Operation: "op1"}
+	op2 := &api_v1.Operation{Service: "svc2", Operation: "op2"}
+	for _, op := range []*api_v1.Operation{op0, op1} {
+		assert.Nil(t, graph.Add(op))
+	}
+	assert.Nil(t, graph.AddRelation(&api_v1.Relation{From: op0, To: op1}))
+
+	w := httptest.NewRecorder()
+	e.ServeHTTP(w, httptest.NewRequest(http.MethodGet, route.GetTraceGraphSnapshotRoute, nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	snapshot := w.Body.Bytes()
+
+	assert.Nil(t, graph.Add(op2))
+	assert.Nil(t, graph.AddRelation(&api_v1.Relation{From: op1, To: op2}))
+
+	body, _ := json.Marshal(&diffRequest{From: snapshot})
+	w = httptest.NewRecorder()
+	e.ServeHTTP(w, httptest.NewRequest(http.MethodPost, route.DiffTraceGraphRoute, bytes.NewReader(body)))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	resp := struct {
+		Result *tg.GraphDiff `json:"result"`
+	}{}
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Len(t, resp.Result.AddedOperations, 1)
+	assert.Equal(t, "op2", resp.Result.AddedOperations[0].GetOperation())
+	assert.Len(t, resp.Result.AddedRelations, 1)
+	assert.Equal(t, "op1", resp.Result.AddedRelations[0].GetFrom().GetOperation())
+	assert.Equal(t, "op2", resp.Result.AddedRelations[0].GetTo().GetOperation())
+	assert.Empty(t, resp.Result.RemovedOperations)
+	assert.Empty(t, resp.Result.RemovedRelations)
+}
+
+func TestWatchMustTellDroppedEvents(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	e := gin.New()
+	NewTraceGraphHttpHandler(&TraceGraphHttpHandlerParams{
+		Logger:     zap.NewNop(),
+		TraceGraph: &slowGraph{TraceGraph: tg.NewTraceGraph(zap.NewNop())},
+	}).RegisterRoutes(e)
+
+	server := httptest.NewServer(e)
+	defer server.Close()
+
+	// the stream must end instead of blocking after events are dropped.
+	resp, err := http.Get(server.URL + route.WatchTraceGraphRoute)
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	assert.Nil(t, err)
+	assert.True(t, strings.Contains(string(body), "event:"+eventsDroppedEvent), string(body))
+}
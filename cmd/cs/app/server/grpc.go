@@ -16,6 +16,7 @@ package server
 
 import (
 	"fmt"
+	"github.com/houyi-tracing/houyi/cmd/cs/app/generator"
 	grpc2 "github.com/houyi-tracing/houyi/cmd/cs/app/handler/grpc"
 	store2 "github.com/houyi-tracing/houyi/cmd/cs/app/store"
 	"github.com/houyi-tracing/houyi/idl/api_v1"
@@ -37,8 +38,6 @@ type GrpcServerParams struct {
 
 	GossipSeed gossip.Seed
 
-	ScaleFactor float64
-
 	SST sst.SamplingStrategyTree
 
 	TraceGraph tg.TraceGraph
@@ -49,7 +48,7 @@ type GrpcServerParams struct {
 
 	StrategyStore store2.StrategyStore
 
-	MinSamplingRate float64
+	RateGenerators generator.Registry
 }
 
 func StartGrpcServer(params *GrpcServerParams) (*grpc.Server, error) {
@@ -76,12 +75,14 @@ func serverGrpc(s *grpc.Server, lis net.Listener, params *GrpcServerParams) erro
 		params.TraceGraph,
 		params.OperationStore,
 		params.Evaluator,
-		params.ScaleFactor,
 		params.StrategyStore,
-		params.MinSamplingRate,
-		params.GossipSeed)
+		params.GossipSeed,
+		params.RateGenerators)
 	api_v1.RegisterStrategyManagerServer(s, smGrpcHandler)
 
+	tgGrpcHandler := grpc2.NewTraceGraphGrpcHandler(params.Logger, params.TraceGraph)
+	api_v1.RegisterTraceGraphManagerServer(s, tgGrpcHandler)
+
 	params.Logger.Info("Starting gRPC server", zap.Int("port", params.ListenPort))
 	go func() {
 		if err := s.Serve(lis); err != nil {
@@ -21,6 +21,7 @@ import (
 	"github.com/houyi-tracing/houyi/cmd/cs/app/store"
 	"github.com/houyi-tracing/houyi/pkg/evaluator"
 	"github.com/houyi-tracing/houyi/pkg/gossip"
+	"github.com/houyi-tracing/houyi/pkg/sst"
 	"github.com/houyi-tracing/houyi/pkg/tg"
 	"go.uber.org/zap"
 )
@@ -37,6 +38,8 @@ type HttpServerParams struct {
 	Evaluator evaluator.Evaluator
 
 	GossipRegistry gossip.Registry
+
+	SST sst.SamplingStrategyTree
 }
 
 func StartHttpServer(params *HttpServerParams) error {
@@ -61,6 +64,12 @@ func StartHttpServer(params *HttpServerParams) error {
 	})
 	eHandler.RegisterRoutes(c)
 
+	sstHandler := handler.NewSamplingStrategyTreeHttpHandler(&handler.SamplingStrategyTreeHttpHandlerParams{
+		Logger: params.Logger,
+		SST:    params.SST,
+	})
+	sstHandler.RegisterRoutes(c)
+
 	go func() {
 		err := c.Run(fmt.Sprintf(":%d", params.ListenPort))
 		if err != nil {
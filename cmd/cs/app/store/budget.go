@@ -0,0 +1,123 @@
+// Copyright (c) 2021 The Houyi Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package store
+
+import (
+	"github.com/houyi-tracing/houyi/idl/api_v1"
+	"github.com/houyi-tracing/houyi/pkg/sst"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// budgetRefreshInterval is the minimum interval between two times of solving sampling rates.
+	budgetRefreshInterval = time.Second
+)
+
+// Budget allocates a global budget of traces per second to ingress operations. The sampling rate of an ingress
+// operation is proportional to its weight in sampling strategy tree until it reaches 1, and the total number of
+// traces per second produced by all ingress operations is equal to the budget.
+type Budget interface {
+	// SamplingRate returns the sampling rate of inputted ingress operation.
+	SamplingRate(op *api_v1.Operation) float64
+}
+
+type demand struct {
+	qps    float64
+	weight float64
+}
+
+type budget struct {
+	sync.Mutex
+
+	tracesPerSecond float64
+	minSamplingRate float64
+	sst             sst.SamplingStrategyTree
+	opStore         OperationStore
+
+	scale    float64
+	solvedAt time.Time
+}
+
+func NewBudget(tracesPerSecond, minSamplingRate float64, tree sst.SamplingStrategyTree, opStore OperationStore) Budget {
+	return &budget{
+		tracesPerSecond: tracesPerSecond,
+		minSamplingRate: minSamplingRate,
+		sst:             tree,
+		opStore:         opStore,
+		scale:           math.Inf(1),
+	}
+}
+
+func (b *budget) SamplingRate(op *api_v1.Operation) float64 {
+	b.Lock()
+	if time.Since(b.solvedAt) >= budgetRefreshInterval {
+		b.scale = b.solve()
+		b.solvedAt = time.Now()
+	}
+	scale := b.scale
+	b.Unlock()
+
+	weight, err := b.sst.Generate(op)
+	if err != nil {
+		return 1.0
+	}
+	return math.Min(math.Max(scale*weight, b.minSamplingRate), 1.0)
+}
+
+func (b *budget) solve() float64 {
+	demands := make([]*demand, 0)
+	for _, item := range b.opStore.IngressQps() {
+		if item.Qps <= 0 {
+			continue
+		}
+		if weight, err := b.sst.Generate(item.Op); err == nil && weight > 0 {
+			demands = append(demands, &demand{qps: item.Qps, weight: weight})
+		}
+	}
+	return solveScale(b.tracesPerSecond, demands)
+}
+
+// solveScale solves the scale c which makes sum(qps * min(c * weight, 1)) equal to budget by water-filling.
+// Operations with greater weights are saturated (sampling rate reaches 1) earlier. It returns +Inf if the budget
+// is enough for sampling all traces.
+func solveScale(budget float64, demands []*demand) float64 {
+	totalQps, weightedQps := 0.0, 0.0
+	for _, d := range demands {
+		totalQps += d.qps
+		weightedQps += d.qps * d.weight
+	}
+	if totalQps <= budget {
+		return math.Inf(1)
+	}
+
+	sort.Slice(demands, func(i, j int) bool {
+		return demands[i].weight > demands[j].weight
+	})
+
+	saturatedQps := 0.0
+	for _, d := range demands {
+		// Assume that all operations before d are saturated and the others are not.
+		c := (budget - saturatedQps) / weightedQps
+		if c*d.weight <= 1 {
+			return c
+		}
+		saturatedQps += d.qps
+		weightedQps -= d.qps * d.weight
+	}
+	return math.Inf(1)
+}
@@ -0,0 +1,58 @@
+// Copyright (c) 2021 The Houyi Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package store
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+)
+
+func tracesPerSecond(scale float64, demands []*demand) float64 {
+	sum := 0.0
+	for _, d := range demands {
+		sum += d.qps * math.Min(scale*d.weight, 1.0)
+	}
+	return sum
+}
+
+func TestBudgetMustBeFullyUsed(t *testing.T) {
+	demands := []*demand{
+		{qps: 1000, weight: 0.25},
+		{qps: 10, weight: 0.5},
+		{qps: 500, weight: 0.125},
+		{qps: 2000, weight: 0.125},
+	}
+
+	for _, budget := range []float64{1, 50, 100, 1000, 3000} {
+		scale := solveScale(budget, demands)
+		assert.InDelta(t, budget, tracesPerSecond(scale, demands), 1e-6)
+	}
+
+	// operations with greater weights must be saturated at first.
+	scale := solveScale(3000, demands)
+	assert.Equal(t, 1.0, math.Min(scale*0.5, 1.0))
+	assert.Equal(t, 1.0, math.Min(scale*0.25, 1.0))
+	assert.Less(t, scale*0.125, 1.0)
+}
+
+func TestAllTracesMustBeSampledIfBudgetIsEnough(t *testing.T) {
+	demands := []*demand{
+		{qps: 10, weight: 0.5},
+		{qps: 20, weight: 0.5},
+	}
+	assert.True(t, math.IsInf(solveScale(30, demands), 1))
+	assert.True(t, math.IsInf(solveScale(100, nil), 1))
+}
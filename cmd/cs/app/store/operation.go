@@ -30,6 +30,14 @@ type OperationStore interface {
 
 	UpToDate(op *api_v1.Operation, isIngress bool, qps float64)
 	QpsWeight(op *api_v1.Operation) float64
+
+	// IngressQps returns the latest reported QPS of all ingress operations.
+	IngressQps() []*OperationQps
+}
+
+type OperationQps struct {
+	Op  *api_v1.Operation
+	Qps float64
 }
 
 type tItem struct {
@@ -45,6 +53,7 @@ type opStore struct {
 	logger          *zap.Logger
 	m               map[string]map[string]*tItem
 	refreshInterval time.Duration
+	relationTTL     time.Duration
 	sst             sst.SamplingStrategyTree
 	tg              tg.TraceGraph
 	seed            gossip.Seed
@@ -53,6 +62,7 @@ type opStore struct {
 
 func NewOperationStore(logger *zap.Logger,
 	interval time.Duration,
+	relationTTL time.Duration,
 	seed gossip.Seed,
 	sst sst.SamplingStrategyTree,
 	tg tg.TraceGraph) OperationStore {
@@ -60,6 +70,7 @@ func NewOperationStore(logger *zap.Logger,
 		logger:          logger,
 		m:               make(map[string]map[string]*tItem),
 		refreshInterval: interval,
+		relationTTL:     relationTTL,
 		seed:            seed,
 		sst:             sst,
 		tg:              tg,
@@ -123,6 +134,24 @@ func (t *opStore) QpsWeight(op *api_v1.Operation) float64 {
 	return 1.0
 }
 
+func (t *opStore) IngressQps() []*OperationQps {
+	t.RLock()
+	defer t.RUnlock()
+
+	ret := make([]*OperationQps, 0)
+	for _, opMap := range t.m {
+		for _, item := range opMap {
+			if item.isIngress {
+				ret = append(ret, &OperationQps{
+					Op:  item.op,
+					Qps: item.qps,
+				})
+			}
+		}
+	}
+	return ret
+}
+
 func (t *opStore) has(op *api_v1.Operation) bool {
 	if _, hasSvc := t.m[op.Service]; hasSvc {
 		if _, hasOp := t.m[op.Service][op.Operation]; hasOp {
@@ -150,16 +179,19 @@ func (t *opStore) removeExpired() {
 	t.Lock()
 	defer t.Unlock()
 
+	for _, rel := range t.tg.RemoveExpiredRelations(t.relationTTL) {
+		t.logger.Info("relation expired", zap.String("relation", rel.String()))
+	}
+
 	now := time.Now()
 	for svc, opMap := range t.m {
 		for op, item := range opMap {
 			if item.upSince.Add(t.refreshInterval).Before(now) {
-				t.logger.Info("monger expired operation",
+				t.logger.Info("operation expired",
 					zap.String("service", svc), zap.String("operation", op))
 
 				_ = t.sst.Prune(item.op)
 				_ = t.tg.Remove(item.op)
-				t.seed.MongerExpiredOperation(item.op)
 				delete(t.m[svc], op)
 				if len(t.m[svc]) == 0 {
 					delete(t.m, svc)
@@ -167,4 +199,8 @@ func (t *opStore) removeExpired() {
 			}
 		}
 	}
+
+	// removals above are sent along with their version metadata in one message, which replaces messages of expired
+	// relations. Peers must handle GRAPH_DELTA to learn about expired relations.
+	t.seed.MongerGraphDelta(t.tg.FlushDelta())
 }
@@ -0,0 +1,420 @@
+// Copyright (c) 2021 The Houyi Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package store
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"github.com/houyi-tracing/houyi/idl/api_v1"
+	"github.com/houyi-tracing/houyi/pkg/fileutil"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/encoding/protojson"
+	"hash/crc32"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	strategyLogFile      = "strategies.log"
+	strategySnapshotFile = "strategies.snapshot"
+
+	snapshotVersion = 1
+
+	// recordHeaderSize is the size of the header of every record in the log file. A header contains the length of
+	// the payload and its CRC32 checksum.
+	recordHeaderSize = 8
+
+	// maxRecordSize is used to detect corrupted headers and avoid allocating huge buffers for them.
+	maxRecordSize = 64 << 20
+)
+
+const (
+	opUpdate     = "update"
+	opOverride   = "override"
+	opDefault    = "default"
+	opRemove     = "remove"
+	opRemoveAll  = "remove-all"
+	opUpdateMany = "update-many"
+)
+
+// PersistentStrategyStore is a StrategyStore which writes every change of strategies to disk, so that all
+// strategies survive restarts of configuration server.
+type PersistentStrategyStore interface {
+	StrategyStore
+
+	// Start starts to take snapshots of strategies periodically.
+	Start()
+
+	// Stop takes the last snapshot and closes the log file.
+	Stop()
+}
+
+type logRecord struct {
+	Op         string            `json:"op"`
+	Service    string            `json:"service,omitempty"`
+	Operation  string            `json:"operation,omitempty"`
+	Strategies []json.RawMessage `json:"strategies,omitempty"`
+}
+
+type strategySnapshot struct {
+	Version    int               `json:"version"`
+	Default    json.RawMessage   `json:"default"`
+	Strategies []json.RawMessage `json:"strategies"`
+}
+
+// persistentStrategyStore keeps strategies in memory and records every change of them into an append-only log.
+// The log is compacted into a snapshot periodically. At startup, the snapshot is loaded at first and then the log
+// is replayed on it.
+type persistentStrategyStore struct {
+	*strategyStore
+
+	lock             sync.Mutex
+	logger           *zap.Logger
+	dir              string
+	logFile          *os.File
+	snapshotInterval time.Duration
+	stopChan         chan *sync.WaitGroup
+}
+
+func NewPersistentStrategyStore(logger *zap.Logger, dir string, snapshotInterval time.Duration) (PersistentStrategyStore, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create directory for strategy store: %w", err)
+	}
+
+	store := &persistentStrategyStore{
+		strategyStore:    NewStrategyStore().(*strategyStore),
+		logger:           logger,
+		dir:              dir,
+		snapshotInterval: snapshotInterval,
+		stopChan:         make(chan *sync.WaitGroup),
+	}
+
+	if err := store.loadSnapshot(); err != nil {
+		return nil, err
+	}
+	if err := store.replayLog(); err != nil {
+		return nil, err
+	}
+
+	logger.Info("Loaded sampling strategies from disk",
+		zap.String("directory", dir),
+		zap.Int("strategies", len(store.strategyStore.GetAll())))
+	return store, nil
+}
+
+func (store *persistentStrategyStore) Start() {
+	if store.snapshotInterval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(store.snapshotInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if err := store.snapshot(); err != nil {
+					store.logger.Error("failed to take snapshot of strategies", zap.Error(err))
+				}
+			case wg := <-store.stopChan:
+				wg.Done()
+				return
+			}
+		}
+	}()
+}
+
+func (store *persistentStrategyStore) Stop() {
+	if store.snapshotInterval > 0 {
+		wg := &sync.WaitGroup{}
+		wg.Add(1)
+		store.stopChan <- wg
+		wg.Wait()
+	}
+
+	if err := store.snapshot(); err != nil {
+		store.logger.Error("failed to take snapshot of strategies", zap.Error(err))
+	}
+
+	store.lock.Lock()
+	defer store.lock.Unlock()
+
+	if err := store.logFile.Close(); err != nil {
+		store.logger.Error("failed to close log file of strategies", zap.Error(err))
+	}
+}
+
+func (store *persistentStrategyStore) Add(svc, op string, strategy *api_v1.PerOperationStrategy) {
+	store.Update(svc, op, strategy)
+}
+
+func (store *persistentStrategyStore) Update(svc, op string, strategy *api_v1.PerOperationStrategy) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+
+	store.strategyStore.Update(svc, op, strategy)
+	store.append(opUpdate, svc, op, strategy)
+}
+
+func (store *persistentStrategyStore) UpdateAll(strategies []*api_v1.PerOperationStrategy) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+
+	store.strategyStore.UpdateAll(strategies)
+	store.append(opUpdateMany, "", "", strategies...)
+}
+
+func (store *persistentStrategyStore) Override(strategies []*api_v1.PerOperationStrategy) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+
+	store.strategyStore.Override(strategies)
+	store.append(opOverride, "", "", strategies...)
+}
+
+func (store *persistentStrategyStore) SetDefaultStrategy(strategy *api_v1.PerOperationStrategy) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+
+	store.strategyStore.SetDefaultStrategy(strategy)
+	store.append(opDefault, "", "", strategy)
+}
+
+func (store *persistentStrategyStore) Remove(svc, op string) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+
+	if err := store.strategyStore.Remove(svc, op); err != nil {
+		return err
+	}
+	store.append(opRemove, svc, op)
+	return nil
+}
+
+func (store *persistentStrategyStore) RemoveAll() {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+
+	store.strategyStore.RemoveAll()
+	store.append(opRemoveAll, "", "")
+}
+
+// append writes a record into the log file and flushes it to disk. Errors are only logged because all modifications
+// have already been applied to memory.
+func (store *persistentStrategyStore) append(op, svc, operation string, strategies ...*api_v1.PerOperationStrategy) {
+	rec := &logRecord{
+		Op:        op,
+		Service:   svc,
+		Operation: operation,
+	}
+	for _, s := range strategies {
+		data, err := protojson.Marshal(s)
+		if err != nil {
+			store.logger.Error("failed to marshal strategy", zap.Error(err))
+			return
+		}
+		rec.Strategies = append(rec.Strategies, data)
+	}
+
+	payload, err := json.Marshal(rec)
+	if err != nil {
+		store.logger.Error("failed to marshal log record of strategies", zap.Error(err))
+		return
+	}
+
+	buf := make([]byte, recordHeaderSize+len(payload))
+	binary.BigEndian.PutUint32(buf[0:4], uint32(len(payload)))
+	binary.BigEndian.PutUint32(buf[4:8], crc32.ChecksumIEEE(payload))
+	copy(buf[recordHeaderSize:], payload)
+
+	if _, err = store.logFile.Write(buf); err != nil {
+		store.logger.Error("failed to write log record of strategies", zap.Error(err))
+		return
+	}
+	if err = store.logFile.Sync(); err != nil {
+		store.logger.Error("failed to sync log file of strategies", zap.Error(err))
+	}
+}
+
+// apply applies a record read from the log file to the in-memory store.
+func (store *persistentStrategyStore) apply(rec *logRecord) error {
+	strategies := make([]*api_v1.PerOperationStrategy, 0, len(rec.Strategies))
+	for _, data := range rec.Strategies {
+		s := &api_v1.PerOperationStrategy{}
+		if err := protojson.Unmarshal(data, s); err != nil {
+			return err
+		}
+		strategies = append(strategies, s)
+	}
+
+	switch rec.Op {
+	case opUpdate:
+		if len(strategies) != 1 {
+			return fmt.Errorf("invalid log record: %s", rec.Op)
+		}
+		store.strategyStore.Update(rec.Service, rec.Operation, strategies[0])
+	case opUpdateMany:
+		store.strategyStore.UpdateAll(strategies)
+	case opOverride:
+		store.strategyStore.Override(strategies)
+	case opDefault:
+		if len(strategies) != 1 {
+			return fmt.Errorf("invalid log record: %s", rec.Op)
+		}
+		store.strategyStore.SetDefaultStrategy(strategies[0])
+	case opRemove:
+		_ = store.strategyStore.Remove(rec.Service, rec.Operation)
+	case opRemoveAll:
+		store.strategyStore.RemoveAll()
+	default:
+		return fmt.Errorf("unknown operation of log record: %s", rec.Op)
+	}
+	return nil
+}
+
+// replayLog applies all complete records in the log file. A torn or corrupted record, which is left by a crash
+// while writing it, and all data following it are truncated from the log file. It returns an error without
+// truncating the log file if a complete record could not be applied.
+func (store *persistentStrategyStore) replayLog() error {
+	f, err := os.OpenFile(filepath.Join(store.dir, strategyLogFile), os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open log file of strategies: %w", err)
+	}
+
+	var offset int64
+	r := bufio.NewReader(f)
+	header := make([]byte, recordHeaderSize)
+	for {
+		if _, err = io.ReadFull(r, header); err != nil {
+			break
+		}
+		size := binary.BigEndian.Uint32(header[0:4])
+		if size > maxRecordSize {
+			err = fmt.Errorf("record size too large: %d", size)
+			break
+		}
+		payload := make([]byte, size)
+		if _, err = io.ReadFull(r, payload); err != nil {
+			break
+		}
+		if crc32.ChecksumIEEE(payload) != binary.BigEndian.Uint32(header[4:8]) {
+			err = fmt.Errorf("checksum mismatch")
+			break
+		}
+		rec := &logRecord{}
+		if err = json.Unmarshal(payload, rec); err != nil {
+			break
+		}
+		if err = store.apply(rec); err != nil {
+			// the record is complete, such as one written by a newer version, so it must not be truncated.
+			_ = f.Close()
+			return fmt.Errorf("failed to apply log record of strategies at offset %d: %w", offset, err)
+		}
+		offset += int64(recordHeaderSize + len(payload))
+	}
+
+	if err != io.EOF {
+		store.logger.Warn("truncated broken tail of log file of strategies",
+			zap.Int64("offset", offset), zap.Error(err))
+	}
+	if err = f.Truncate(offset); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to truncate log file of strategies: %w", err)
+	}
+	if _, err = f.Seek(offset, io.SeekStart); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to seek log file of strategies: %w", err)
+	}
+	store.logFile = f
+	return nil
+}
+
+func (store *persistentStrategyStore) loadSnapshot() error {
+	data, err := ioutil.ReadFile(filepath.Join(store.dir, strategySnapshotFile))
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("failed to read snapshot of strategies: %w", err)
+	}
+
+	snapshot := &strategySnapshot{}
+	if err = json.Unmarshal(data, snapshot); err != nil {
+		return fmt.Errorf("failed to parse snapshot of strategies: %w", err)
+	}
+	if snapshot.Version != snapshotVersion {
+		return fmt.Errorf("unsupported version of snapshot of strategies: %d", snapshot.Version)
+	}
+
+	defaultStrategy := &api_v1.PerOperationStrategy{}
+	if err = protojson.Unmarshal(snapshot.Default, defaultStrategy); err != nil {
+		return fmt.Errorf("failed to parse default strategy in snapshot: %w", err)
+	}
+	strategies := make([]*api_v1.PerOperationStrategy, 0, len(snapshot.Strategies))
+	for _, s := range snapshot.Strategies {
+		strategy := &api_v1.PerOperationStrategy{}
+		if err = protojson.Unmarshal(s, strategy); err != nil {
+			return fmt.Errorf("failed to parse strategy in snapshot: %w", err)
+		}
+		strategies = append(strategies, strategy)
+	}
+
+	store.strategyStore.SetDefaultStrategy(defaultStrategy)
+	store.strategyStore.Override(strategies)
+	return nil
+}
+
+// snapshot writes all strategies into a new snapshot file atomically and then empties the log file.
+func (store *persistentStrategyStore) snapshot() error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+
+	snapshot := &strategySnapshot{
+		Version:    snapshotVersion,
+		Strategies: make([]json.RawMessage, 0),
+	}
+	var err error
+	if snapshot.Default, err = protojson.Marshal(store.strategyStore.GetDefaultStrategy()); err != nil {
+		return err
+	}
+	for _, s := range store.strategyStore.GetAll() {
+		data, err := protojson.Marshal(s)
+		if err != nil {
+			return err
+		}
+		snapshot.Strategies = append(snapshot.Strategies, data)
+	}
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+
+	if err = fileutil.WriteFileAtomically(filepath.Join(store.dir, strategySnapshotFile), data); err != nil {
+		return err
+	}
+
+	// All records in log file have been contained by the new snapshot.
+	if err = store.logFile.Truncate(0); err != nil {
+		return err
+	}
+	if _, err = store.logFile.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	return store.logFile.Sync()
+}
@@ -0,0 +1,136 @@
+// Copyright (c) 2021 The Houyi Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package store
+
+import (
+	"github.com/houyi-tracing/houyi/idl/api_v1"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func probabilityStrategy(svc, op string, rate float64) *api_v1.PerOperationStrategy {
+	return &api_v1.PerOperationStrategy{
+		Service:   svc,
+		Operation: op,
+		Type:      api_v1.Type_PROBABILITY,
+		Strategy: &api_v1.PerOperationStrategy_Probability{
+			Probability: &api_v1.ProbabilitySampling{
+				SamplingRate: rate,
+			},
+		},
+	}
+}
+
+func TestStrategiesMustBeRecoveredFromLog(t *testing.T) {
+	logger := zap.NewNop()
+	dir := t.TempDir()
+
+	s, err := NewPersistentStrategyStore(logger, dir, 0)
+	assert.Nil(t, err)
+	s.Add("svc", "op1", probabilityStrategy("svc", "op1", 0.1))
+	s.Add("svc", "op2", probabilityStrategy("svc", "op2", 0.2))
+	s.Update("svc", "op1", probabilityStrategy("svc", "op1", 0.5))
+	assert.Nil(t, s.Remove("svc", "op2"))
+	s.SetDefaultStrategy(probabilityStrategy("", "", 0.3))
+
+	// simulate a crash: the log file is not closed and no snapshot is taken.
+	recovered, err := NewPersistentStrategyStore(logger, dir, 0)
+	assert.Nil(t, err)
+	assert.Len(t, recovered.GetAll(), 1)
+	assert.False(t, recovered.Has("svc", "op2"))
+	got, err := recovered.Get("svc", "op1")
+	assert.Nil(t, err)
+	assert.Equal(t, 0.5, got.GetProbability().GetSamplingRate())
+	assert.Equal(t, 0.3, recovered.GetDefaultStrategy().GetProbability().GetSamplingRate())
+}
+
+func TestStrategiesMustBeRecoveredFromSnapshotAndLog(t *testing.T) {
+	logger := zap.NewNop()
+	dir := t.TempDir()
+
+	s, err := NewPersistentStrategyStore(logger, dir, 0)
+	assert.Nil(t, err)
+	s.Override([]*api_v1.PerOperationStrategy{
+		probabilityStrategy("svc", "op1", 0.1),
+		probabilityStrategy("svc", "op2", 0.2),
+	})
+	s.Stop()
+
+	info, err := os.Stat(filepath.Join(dir, strategyLogFile))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), info.Size())
+
+	s, err = NewPersistentStrategyStore(logger, dir, 0)
+	assert.Nil(t, err)
+	assert.Len(t, s.GetAll(), 2)
+	s.UpdateAll([]*api_v1.PerOperationStrategy{probabilityStrategy("svc", "op3", 0.3)})
+
+	recovered, err := NewPersistentStrategyStore(logger, dir, 0)
+	assert.Nil(t, err)
+	assert.Len(t, recovered.GetAll(), 3)
+}
+
+func TestTornRecordMustBeDiscarded(t *testing.T) {
+	logger := zap.NewNop()
+	dir := t.TempDir()
+
+	s, err := NewPersistentStrategyStore(logger, dir, 0)
+	assert.Nil(t, err)
+	s.Add("svc", "op1", probabilityStrategy("svc", "op1", 0.1))
+	s.Add("svc", "op2", probabilityStrategy("svc", "op2", 0.2))
+
+	logPath := filepath.Join(dir, strategyLogFile)
+	info, err := os.Stat(logPath)
+	assert.Nil(t, err)
+	assert.Nil(t, os.Truncate(logPath, info.Size()-3))
+
+	recovered, err := NewPersistentStrategyStore(logger, dir, 0)
+	assert.Nil(t, err)
+	assert.True(t, recovered.Has("svc", "op1"))
+	assert.False(t, recovered.Has("svc", "op2"))
+
+	// new records must be appended right after the last complete record.
+	recovered.Add("svc", "op3", probabilityStrategy("svc", "op3", 0.3))
+	recovered, err = NewPersistentStrategyStore(logger, dir, 0)
+	assert.Nil(t, err)
+	assert.True(t, recovered.Has("svc", "op1"))
+	assert.True(t, recovered.Has("svc", "op3"))
+}
+
+func TestInapplicableRecordMustNotBeDiscarded(t *testing.T) {
+	logger := zap.NewNop()
+	dir := t.TempDir()
+
+	s, err := NewPersistentStrategyStore(logger, dir, 0)
+	assert.Nil(t, err)
+	s.Add("svc", "op1", probabilityStrategy("svc", "op1", 0.1))
+	// a record of an operation unknown to this version, such as one written by a newer version.
+	s.(*persistentStrategyStore).append("unknown", "svc", "op2")
+	s.Add("svc", "op3", probabilityStrategy("svc", "op3", 0.3))
+
+	logPath := filepath.Join(dir, strategyLogFile)
+	before, err := os.Stat(logPath)
+	assert.Nil(t, err)
+
+	_, err = NewPersistentStrategyStore(logger, dir, 0)
+	assert.NotNil(t, err)
+
+	after, err := os.Stat(logPath)
+	assert.Nil(t, err)
+	assert.Equal(t, before.Size(), after.Size())
+}
@@ -0,0 +1,79 @@
+// Copyright (c) 2021 The Houyi Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package store
+
+import (
+	"github.com/houyi-tracing/houyi/pkg/sst"
+	"sync"
+	"time"
+)
+
+const (
+	// decayChecksPerHalfLife is the number of times to check for idle operations during a half-life.
+	decayChecksPerHalfLife = 4
+)
+
+// TreeDecayer demotes operations in sampling strategy tree which have not been promoted recently, so that the
+// increase of sampling rate caused by promotion is temporary.
+type TreeDecayer interface {
+	Start()
+	Stop()
+}
+
+type treeDecayer struct {
+	tree     sst.SamplingStrategyTree
+	halfLife time.Duration
+	stopChan chan *sync.WaitGroup
+}
+
+func NewTreeDecayer(tree sst.SamplingStrategyTree, halfLife time.Duration) TreeDecayer {
+	return &treeDecayer{
+		tree:     tree,
+		halfLife: halfLife,
+		stopChan: make(chan *sync.WaitGroup),
+	}
+}
+
+func (d *treeDecayer) Start() {
+	go func() {
+		ticker := time.NewTicker(d.checkInterval())
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				d.tree.Decay(d.halfLife)
+			case wg := <-d.stopChan:
+				wg.Done()
+				return
+			}
+		}
+	}()
+}
+
+// checkInterval returns the interval of checking for idle operations, which is at least 1ns so that half-lives
+// shorter than decayChecksPerHalfLife nanoseconds do not make the ticker panic.
+func (d *treeDecayer) checkInterval() time.Duration {
+	if interval := d.halfLife / decayChecksPerHalfLife; interval > 0 {
+		return interval
+	}
+	return time.Nanosecond
+}
+
+func (d *treeDecayer) Stop() {
+	wg := &sync.WaitGroup{}
+	wg.Add(1)
+	d.stopChan <- wg
+	wg.Wait()
+}
@@ -0,0 +1,36 @@
+// Copyright (c) 2021 The Houyi Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package store
+
+import (
+	"github.com/houyi-tracing/houyi/pkg/sst"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestDecayerWithShortHalfLife(t *testing.T) {
+	for _, halfLife := range []time.Duration{time.Nanosecond, decayChecksPerHalfLife - 1} {
+		d := NewTreeDecayer(sst.NewSamplingStrategyTree(4), halfLife)
+		assert.Equal(t, time.Nanosecond, d.(*treeDecayer).checkInterval())
+		assert.NotPanics(t, func() {
+			d.Start()
+			time.Sleep(10 * time.Millisecond)
+			d.Stop()
+		})
+	}
+	d := NewTreeDecayer(sst.NewSamplingStrategyTree(4), time.Minute)
+	assert.Equal(t, time.Minute/decayChecksPerHalfLife, d.(*treeDecayer).checkInterval())
+}
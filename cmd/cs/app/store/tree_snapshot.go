@@ -0,0 +1,92 @@
+// Copyright (c) 2021 The Houyi Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package store
+
+import (
+	"fmt"
+	"github.com/houyi-tracing/houyi/pkg/fileutil"
+	"github.com/houyi-tracing/houyi/pkg/sst"
+	"github.com/houyi-tracing/houyi/pkg/tg"
+	"go.uber.org/zap"
+	"time"
+)
+
+// TreeSnapshotter saves snapshots of sampling strategy tree into a file periodically.
+type TreeSnapshotter interface {
+	// Load restores sampling strategy tree from the last snapshot if it exists. Trace graph should be loaded before it
+	// to tell which restored operations are ingress operations.
+	Load() error
+
+	// Start takes snapshots periodically. Only the last snapshot is saved when it stops if interval is not positive.
+	Start()
+
+	// Stop saves the last snapshot and stops taking snapshots.
+	Stop()
+}
+
+type treeSnapshotter struct {
+	*fileutil.PeriodicSnapshot
+
+	logger  *zap.Logger
+	tree    sst.SamplingStrategyTree
+	opStore OperationStore
+	tg      tg.TraceGraph
+	path    string
+}
+
+func NewTreeSnapshotter(logger *zap.Logger,
+	path string,
+	interval time.Duration,
+	tree sst.SamplingStrategyTree,
+	opStore OperationStore,
+	tg tg.TraceGraph) TreeSnapshotter {
+	return &treeSnapshotter{
+		PeriodicSnapshot: fileutil.NewPeriodicSnapshot(logger, "sampling strategy tree", path, interval, tree.Snapshot),
+		logger:           logger,
+		tree:             tree,
+		opStore:          opStore,
+		tg:               tg,
+		path:             path,
+	}
+}
+
+func (s *treeSnapshotter) Load() error {
+	data, err := s.Read()
+	if err != nil {
+		return fmt.Errorf("failed to read snapshot of sampling strategy tree: %w", err)
+	} else if data == nil {
+		return nil
+	}
+
+	if err = s.tree.Restore(data); err != nil {
+		return fmt.Errorf("failed to restore sampling strategy tree: %w", err)
+	}
+
+	// Restored operations must be expired like other operations if they would never be seen again.
+	ingresses := make(map[string]map[string]bool)
+	for _, op := range s.tg.AllIngresses() {
+		if _, has := ingresses[op.GetService()]; !has {
+			ingresses[op.GetService()] = make(map[string]bool)
+		}
+		ingresses[op.GetService()][op.GetOperation()] = true
+	}
+	ops := s.tree.Operations()
+	for _, op := range ops {
+		s.opStore.UpToDate(op, ingresses[op.GetService()][op.GetOperation()], 0)
+	}
+	s.logger.Info("Restored sampling strategy tree from snapshot",
+		zap.String("path", s.path), zap.Int("operations", len(ops)))
+	return nil
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2021 The Houyi Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package store
+
+import (
+	"github.com/houyi-tracing/houyi/idl/api_v1"
+	"github.com/houyi-tracing/houyi/pkg/sst"
+	"github.com/houyi-tracing/houyi/pkg/tg"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoadMustRestoreIngressOperations(t *testing.T) {
+	logger := zap.NewNop()
+	ingress := &api_v1.Operation{Service: "svcA", Operation: "op1"}
+	internal := &api_v1.Operation{Service: "svcB", Operation: "op2"}
+
+	tree := sst.NewSamplingStrategyTree(4)
+	assert.Nil(t, tree.Add(ingress))
+	assert.Nil(t, tree.Add(internal))
+	path := filepath.Join(t.TempDir(), "sst.json")
+	NewTreeSnapshotter(logger, path, time.Minute, tree, nil, nil).(*treeSnapshotter).Save()
+
+	graph := tg.NewTraceGraph(logger)
+	assert.Nil(t, graph.Add(ingress))
+	assert.Nil(t, graph.Add(internal))
+	assert.Nil(t, graph.AddRelation(&api_v1.Relation{From: ingress, To: internal}))
+
+	restored := sst.NewSamplingStrategyTree(4)
+	opStore := NewOperationStore(logger, time.Minute, time.Minute, nil, restored, graph)
+	assert.Nil(t, NewTreeSnapshotter(logger, path, time.Minute, restored, opStore, graph).Load())
+
+	qps := opStore.IngressQps()
+	assert.Equal(t, 1, len(qps))
+	assert.Equal(t, ingress.String(), qps[0].Op.String())
+}
+
+func TestSnapshotterWithoutInterval(t *testing.T) {
+	logger := zap.NewNop()
+	op := &api_v1.Operation{Service: "svcA", Operation: "op1"}
+
+	tree := sst.NewSamplingStrategyTree(4)
+	assert.Nil(t, tree.Add(op))
+	path := filepath.Join(t.TempDir(), "sst.json")
+	s := NewTreeSnapshotter(logger, path, 0, tree, nil, nil)
+	s.Start()
+	s.Stop()
+
+	graph := tg.NewTraceGraph(logger)
+	restored := sst.NewSamplingStrategyTree(4)
+	opStore := NewOperationStore(logger, time.Minute, time.Minute, nil, restored, graph)
+	assert.Nil(t, NewTreeSnapshotter(logger, path, 0, restored, opStore, graph).Load())
+	assert.True(t, restored.Has(op))
+}
@@ -17,8 +17,10 @@ package main
 import (
 	"fmt"
 	"github.com/houyi-tracing/houyi/cmd/cs/app"
+	"github.com/houyi-tracing/houyi/cmd/cs/app/generator"
 	"github.com/houyi-tracing/houyi/cmd/cs/app/registry"
 	"github.com/houyi-tracing/houyi/cmd/cs/app/store"
+	"github.com/houyi-tracing/houyi/idl/api_v1"
 	"github.com/houyi-tracing/houyi/pkg/config"
 	"github.com/houyi-tracing/houyi/pkg/evaluator"
 	"github.com/houyi-tracing/houyi/pkg/gossip/seed"
@@ -62,24 +64,46 @@ func main() {
 
 			// Sampling Strategy Tree
 			sstOpts := new(sst.Flags).InitFromViper(v)
-			ssTree := sst.NewSamplingStrategyTree(sstOpts.Order)
+			ssTree, err := sst.NewTree(sstOpts.Mode, sstOpts.Order)
+			if err != nil {
+				return err
+			}
 
 			// Trace Graph
 			traceGraph := tg.NewTraceGraph(logger)
+			tgOpts := new(tg.Flags).InitFromViper(v)
+			var tgSnapshotter tg.Snapshotter
+			if tgOpts.SnapshotPath != "" {
+				tgSnapshotter = tg.NewSnapshotter(logger, tgOpts.SnapshotPath, tgOpts.SnapshotInterval, traceGraph)
+				if err = tgSnapshotter.Load(); err != nil {
+					logger.Error("failed to load snapshot of trace graph", zap.Error(err))
+				}
+			}
 
 			// evaluator
-			eval := evaluator.NewEvaluator(logger)
+			eval := evaluator.NewEvaluator(logger, nil)
 
 			csOpts := new(app.Flags).InitFromViper(v)
 			gossipRegistry := registry.NewRegistry(logger,
 				csOpts.RandomPick,
 				csOpts.ProbToR,
 				csOpts.HeartbeatInterval)
-			if err := gossipRegistry.Start(); err != nil {
+			if err = gossipRegistry.Start(); err != nil {
 				logger.Fatal("failed to start registry", zap.Error(err))
 			}
 
-			strategyStore := store.NewStrategyStore()
+			var strategyStore store.StrategyStore
+			var persistentStore store.PersistentStrategyStore
+			if csOpts.StoragePath != "" {
+				if persistentStore, err = store.NewPersistentStrategyStore(logger,
+					csOpts.StoragePath, csOpts.SnapshotInterval); err != nil {
+					return err
+				}
+				persistentStore.Start()
+				strategyStore = persistentStore
+			} else {
+				strategyStore = store.NewStrategyStore()
+			}
 
 			// Gossip Seed
 			seedOpts := new(seed.Flags).InitFromViper(v)
@@ -91,32 +115,78 @@ func main() {
 					Addr: "localhost",
 					Port: seedOpts.ConfigServerGrpcPort,
 				},
-				TraceGraph: traceGraph,
+				TraceGraph:          traceGraph,
+				AntiEntropyInterval: seedOpts.AntiEntropyInterval,
 			})
 			if err != nil {
 				return err
 			}
 
-			operationStore := store.NewOperationStore(logger, csOpts.OperationExpire, gossipSeed, ssTree, traceGraph)
+			operationStore := store.NewOperationStore(logger,
+				csOpts.OperationExpire,
+				csOpts.RelationExpire,
+				gossipSeed,
+				ssTree,
+				traceGraph)
+
+			var treeSnapshotter store.TreeSnapshotter
+			if sstOpts.SnapshotPath != "" {
+				treeSnapshotter = store.NewTreeSnapshotter(logger,
+					sstOpts.SnapshotPath,
+					sstOpts.SnapshotInterval,
+					ssTree,
+					operationStore,
+					traceGraph)
+				if err = treeSnapshotter.Load(); err != nil {
+					logger.Error("failed to load snapshot of sampling strategy tree", zap.Error(err))
+				}
+			}
+
+			var budget store.Budget
+			if csOpts.TracesPerSecond > 0 {
+				budget = store.NewBudget(csOpts.TracesPerSecond, csOpts.MinSamplingRate, ssTree, operationStore)
+			}
+
+			rateGenerators := generator.NewRegistry()
+			rateGenerators.Register(api_v1.Type_DYNAMIC, generator.NewDynamicGenerator(logger,
+				ssTree,
+				operationStore,
+				budget,
+				csOpts.ScaleFactor,
+				csOpts.MinSamplingRate))
+			rateGenerators.Register(api_v1.Type_ADAPTIVE, generator.NewAdaptiveGenerator(logger,
+				operationStore,
+				csOpts.ScaleFactor,
+				csOpts.MinSamplingRate))
 
 			cs := app.NewConfigServer(&app.ConfigurationServerParams{
-				Logger:          logger,
-				GrpcListenPort:  csOpts.GrpcListenPort,
-				HttpListenPort:  csOpts.HttpListenPort,
-				GossipSeed:      gossipSeed,
-				GossipRegistry:  gossipRegistry,
-				TraceGraph:      traceGraph,
-				Evaluator:       eval,
-				StrategyStore:   strategyStore,
-				SST:             ssTree,
-				OperationStore:  operationStore,
-				ScaleFactor:     csOpts.ScaleFactor,
-				MinSamplingRate: csOpts.MinSamplingRate,
+				Logger:         logger,
+				GrpcListenPort: csOpts.GrpcListenPort,
+				HttpListenPort: csOpts.HttpListenPort,
+				GossipSeed:     gossipSeed,
+				GossipRegistry: gossipRegistry,
+				TraceGraph:     traceGraph,
+				Evaluator:      eval,
+				StrategyStore:  strategyStore,
+				SST:            ssTree,
+				OperationStore: operationStore,
+				RateGenerators: rateGenerators,
 			})
 
 			if err = cs.Start(); err != nil {
 				return err
 			}
+			if treeSnapshotter != nil {
+				treeSnapshotter.Start()
+			}
+			if tgSnapshotter != nil {
+				tgSnapshotter.Start()
+			}
+			var treeDecayer store.TreeDecayer
+			if sstOpts.HalfLife > 0 {
+				treeDecayer = store.NewTreeDecayer(ssTree, sstOpts.HalfLife)
+				treeDecayer.Start()
+			}
 
 			svc.RunAndThen(func() {
 				// Do something before completing shutting down.
@@ -127,6 +197,18 @@ func main() {
 				if err = cs.Stop(); err != nil {
 					logger.Error("failed to stop configuration server", zap.Error(err))
 				}
+				if treeDecayer != nil {
+					treeDecayer.Stop()
+				}
+				if treeSnapshotter != nil {
+					treeSnapshotter.Stop()
+				}
+				if tgSnapshotter != nil {
+					tgSnapshotter.Stop()
+				}
+				if persistentStore != nil {
+					persistentStore.Stop()
+				}
 			})
 			return nil
 		},
@@ -137,6 +219,7 @@ func main() {
 		rootCmd,
 		seed.AddFlags,
 		sst.AddFlags,
+		tg.AddFlags,
 		app.AddFlags,
 		svc.AddFlags)
 
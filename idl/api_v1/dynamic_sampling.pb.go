@@ -615,18 +615,85 @@ func (*NullRely) Descriptor() ([]byte, []int) {
 	return file_dynamic_sampling_proto_rawDescGZIP(), []int{8}
 }
 
+// PromoteRequest is wire-compatible with houyi.Operation so that requests from old collectors are promoted with the
+// default weight.
+type PromoteRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Service   string `protobuf:"bytes,1,opt,name=service,proto3" json:"service,omitempty"`
+	Operation string `protobuf:"bytes,2,opt,name=operation,proto3" json:"operation,omitempty"`
+	// weight is the number of steps to promote the operation in sampling strategy tree. 0 is treated as 1.
+	Weight int32 `protobuf:"varint,3,opt,name=weight,proto3" json:"weight,omitempty"`
+}
+
+func (x *PromoteRequest) Reset() {
+	*x = PromoteRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_dynamic_sampling_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PromoteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PromoteRequest) ProtoMessage() {}
+
+func (x *PromoteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_dynamic_sampling_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PromoteRequest.ProtoReflect.Descriptor instead.
+func (*PromoteRequest) Descriptor() ([]byte, []int) {
+	return file_dynamic_sampling_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *PromoteRequest) GetService() string {
+	if x != nil {
+		return x.Service
+	}
+	return ""
+}
+
+func (x *PromoteRequest) GetOperation() string {
+	if x != nil {
+		return x.Operation
+	}
+	return ""
+}
+
+func (x *PromoteRequest) GetWeight() int32 {
+	if x != nil {
+		return x.Weight
+	}
+	return 0
+}
+
 type UpdateTagsRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Tags []*EvaluatingTag `protobuf:"bytes,1,rep,name=tags,proto3" json:"tags,omitempty"`
+	Tags  []*EvaluatingTag  `protobuf:"bytes,1,rep,name=tags,proto3" json:"tags,omitempty"`
+	Rules []*EvaluatingRule `protobuf:"bytes,2,rep,name=rules,proto3" json:"rules,omitempty"`
 }
 
 func (x *UpdateTagsRequest) Reset() {
 	*x = UpdateTagsRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_dynamic_sampling_proto_msgTypes[9]
+		mi := &file_dynamic_sampling_proto_msgTypes[10]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -639,7 +706,7 @@ func (x *UpdateTagsRequest) String() string {
 func (*UpdateTagsRequest) ProtoMessage() {}
 
 func (x *UpdateTagsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_dynamic_sampling_proto_msgTypes[9]
+	mi := &file_dynamic_sampling_proto_msgTypes[10]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -652,7 +719,7 @@ func (x *UpdateTagsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateTagsRequest.ProtoReflect.Descriptor instead.
 func (*UpdateTagsRequest) Descriptor() ([]byte, []int) {
-	return file_dynamic_sampling_proto_rawDescGZIP(), []int{9}
+	return file_dynamic_sampling_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *UpdateTagsRequest) GetTags() []*EvaluatingTag {
@@ -662,6 +729,147 @@ func (x *UpdateTagsRequest) GetTags() []*EvaluatingTag {
 	return nil
 }
 
+func (x *UpdateTagsRequest) GetRules() []*EvaluatingRule {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+type GetMatchesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *GetMatchesRequest) Reset() {
+	*x = GetMatchesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_dynamic_sampling_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetMatchesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMatchesRequest) ProtoMessage() {}
+
+func (x *GetMatchesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_dynamic_sampling_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMatchesRequest.ProtoReflect.Descriptor instead.
+func (*GetMatchesRequest) Descriptor() ([]byte, []int) {
+	return file_dynamic_sampling_proto_rawDescGZIP(), []int{11}
+}
+
+type GetMatchesResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Matches []*EvaluatorMatch `protobuf:"bytes,1,rep,name=matches,proto3" json:"matches,omitempty"`
+}
+
+func (x *GetMatchesResponse) Reset() {
+	*x = GetMatchesResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_dynamic_sampling_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetMatchesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMatchesResponse) ProtoMessage() {}
+
+func (x *GetMatchesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_dynamic_sampling_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMatchesResponse.ProtoReflect.Descriptor instead.
+func (*GetMatchesResponse) Descriptor() ([]byte, []int) {
+	return file_dynamic_sampling_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *GetMatchesResponse) GetMatches() []*EvaluatorMatch {
+	if x != nil {
+		return x.Matches
+	}
+	return nil
+}
+
+type WatchRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// service limits events to those relate to operations of this service. Events of all services are sent if it is
+	// empty.
+	Service string `protobuf:"bytes,1,opt,name=service,proto3" json:"service,omitempty"`
+}
+
+func (x *WatchRequest) Reset() {
+	*x = WatchRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_dynamic_sampling_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchRequest) ProtoMessage() {}
+
+func (x *WatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_dynamic_sampling_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchRequest.ProtoReflect.Descriptor instead.
+func (*WatchRequest) Descriptor() ([]byte, []int) {
+	return file_dynamic_sampling_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *WatchRequest) GetService() string {
+	if x != nil {
+		return x.Service
+	}
+	return ""
+}
+
 type StrategyRequest_Operation struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -674,7 +882,7 @@ type StrategyRequest_Operation struct {
 func (x *StrategyRequest_Operation) Reset() {
 	*x = StrategyRequest_Operation{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_dynamic_sampling_proto_msgTypes[10]
+		mi := &file_dynamic_sampling_proto_msgTypes[14]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -687,7 +895,7 @@ func (x *StrategyRequest_Operation) String() string {
 func (*StrategyRequest_Operation) ProtoMessage() {}
 
 func (x *StrategyRequest_Operation) ProtoReflect() protoreflect.Message {
-	mi := &file_dynamic_sampling_proto_msgTypes[10]
+	mi := &file_dynamic_sampling_proto_msgTypes[14]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -784,34 +992,61 @@ var file_dynamic_sampling_proto_rawDesc = []byte{
 	0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x73, 0x61, 0x6d, 0x70, 0x6c, 0x69, 0x6e, 0x67, 0x2e, 0x50, 0x65,
 	0x72, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x72, 0x61, 0x74, 0x65,
 	0x67, 0x79, 0x52, 0x0a, 0x73, 0x74, 0x72, 0x61, 0x74, 0x65, 0x67, 0x69, 0x65, 0x73, 0x22, 0x0a,
-	0x0a, 0x08, 0x4e, 0x75, 0x6c, 0x6c, 0x52, 0x65, 0x6c, 0x79, 0x22, 0x3d, 0x0a, 0x11, 0x55, 0x70,
-	0x64, 0x61, 0x74, 0x65, 0x54, 0x61, 0x67, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
-	0x28, 0x0a, 0x04, 0x74, 0x61, 0x67, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e,
-	0x68, 0x6f, 0x75, 0x79, 0x69, 0x2e, 0x45, 0x76, 0x61, 0x6c, 0x75, 0x61, 0x74, 0x69, 0x6e, 0x67,
-	0x54, 0x61, 0x67, 0x52, 0x04, 0x74, 0x61, 0x67, 0x73, 0x2a, 0x50, 0x0a, 0x04, 0x54, 0x79, 0x70,
-	0x65, 0x12, 0x09, 0x0a, 0x05, 0x43, 0x4f, 0x4e, 0x53, 0x54, 0x10, 0x00, 0x12, 0x0f, 0x0a, 0x0b,
-	0x50, 0x52, 0x4f, 0x42, 0x41, 0x42, 0x49, 0x4c, 0x49, 0x54, 0x59, 0x10, 0x01, 0x12, 0x11, 0x0a,
-	0x0d, 0x52, 0x41, 0x54, 0x45, 0x5f, 0x4c, 0x49, 0x4d, 0x49, 0x54, 0x49, 0x4e, 0x47, 0x10, 0x02,
-	0x12, 0x0c, 0x0a, 0x08, 0x41, 0x44, 0x41, 0x50, 0x54, 0x49, 0x56, 0x45, 0x10, 0x03, 0x12, 0x0b,
-	0x0a, 0x07, 0x44, 0x59, 0x4e, 0x41, 0x4d, 0x49, 0x43, 0x10, 0x04, 0x32, 0x8e, 0x01, 0x0a, 0x0f,
-	0x53, 0x74, 0x72, 0x61, 0x74, 0x65, 0x67, 0x79, 0x4d, 0x61, 0x6e, 0x61, 0x67, 0x65, 0x72, 0x12,
-	0x48, 0x0a, 0x0d, 0x47, 0x65, 0x74, 0x53, 0x74, 0x72, 0x61, 0x74, 0x65, 0x67, 0x69, 0x65, 0x73,
-	0x12, 0x19, 0x2e, 0x73, 0x61, 0x6d, 0x70, 0x6c, 0x69, 0x6e, 0x67, 0x2e, 0x53, 0x74, 0x72, 0x61,
-	0x74, 0x65, 0x67, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x73, 0x61,
-	0x6d, 0x70, 0x6c, 0x69, 0x6e, 0x67, 0x2e, 0x53, 0x74, 0x72, 0x61, 0x74, 0x65, 0x67, 0x69, 0x65,
-	0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x31, 0x0a, 0x07, 0x50, 0x72, 0x6f,
-	0x6d, 0x6f, 0x74, 0x65, 0x12, 0x10, 0x2e, 0x68, 0x6f, 0x75, 0x79, 0x69, 0x2e, 0x4f, 0x70, 0x65,
-	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x1a, 0x12, 0x2e, 0x73, 0x61, 0x6d, 0x70, 0x6c, 0x69, 0x6e,
-	0x67, 0x2e, 0x4e, 0x75, 0x6c, 0x6c, 0x52, 0x65, 0x6c, 0x79, 0x22, 0x00, 0x32, 0x53, 0x0a, 0x10,
-	0x45, 0x76, 0x61, 0x6c, 0x75, 0x61, 0x74, 0x6f, 0x72, 0x4d, 0x61, 0x6e, 0x61, 0x67, 0x65, 0x72,
-	0x12, 0x3f, 0x0a, 0x0a, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x54, 0x61, 0x67, 0x73, 0x12, 0x1b,
-	0x2e, 0x73, 0x61, 0x6d, 0x70, 0x6c, 0x69, 0x6e, 0x67, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65,
-	0x54, 0x61, 0x67, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x12, 0x2e, 0x73, 0x61,
-	0x6d, 0x70, 0x6c, 0x69, 0x6e, 0x67, 0x2e, 0x4e, 0x75, 0x6c, 0x6c, 0x52, 0x65, 0x6c, 0x79, 0x22,
-	0x00, 0x42, 0x2b, 0x5a, 0x29, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f,
-	0x68, 0x6f, 0x75, 0x79, 0x69, 0x2d, 0x74, 0x72, 0x61, 0x63, 0x69, 0x6e, 0x67, 0x2f, 0x68, 0x6f,
-	0x75, 0x79, 0x69, 0x2f, 0x69, 0x64, 0x6c, 0x2f, 0x61, 0x70, 0x69, 0x5f, 0x76, 0x31, 0x62, 0x06,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x0a, 0x08, 0x4e, 0x75, 0x6c, 0x6c, 0x52, 0x65, 0x6c, 0x79, 0x22, 0x60, 0x0a, 0x0e, 0x50, 0x72,
+	0x6f, 0x6d, 0x6f, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07,
+	0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x73,
+	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x6f, 0x70, 0x65, 0x72, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x22, 0x6a, 0x0a, 0x11,
+	0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x54, 0x61, 0x67, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x28, 0x0a, 0x04, 0x74, 0x61, 0x67, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x14, 0x2e, 0x68, 0x6f, 0x75, 0x79, 0x69, 0x2e, 0x45, 0x76, 0x61, 0x6c, 0x75, 0x61, 0x74, 0x69,
+	0x6e, 0x67, 0x54, 0x61, 0x67, 0x52, 0x04, 0x74, 0x61, 0x67, 0x73, 0x12, 0x2b, 0x0a, 0x05, 0x72,
+	0x75, 0x6c, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x68, 0x6f, 0x75,
+	0x79, 0x69, 0x2e, 0x45, 0x76, 0x61, 0x6c, 0x75, 0x61, 0x74, 0x69, 0x6e, 0x67, 0x52, 0x75, 0x6c,
+	0x65, 0x52, 0x05, 0x72, 0x75, 0x6c, 0x65, 0x73, 0x22, 0x13, 0x0a, 0x11, 0x47, 0x65, 0x74, 0x4d,
+	0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x45, 0x0a,
+	0x12, 0x47, 0x65, 0x74, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x2f, 0x0a, 0x07, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x68, 0x6f, 0x75, 0x79, 0x69, 0x2e, 0x45, 0x76, 0x61,
+	0x6c, 0x75, 0x61, 0x74, 0x6f, 0x72, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x52, 0x07, 0x6d, 0x61, 0x74,
+	0x63, 0x68, 0x65, 0x73, 0x22, 0x28, 0x0a, 0x0c, 0x57, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2a, 0x50,
+	0x0a, 0x04, 0x54, 0x79, 0x70, 0x65, 0x12, 0x09, 0x0a, 0x05, 0x43, 0x4f, 0x4e, 0x53, 0x54, 0x10,
+	0x00, 0x12, 0x0f, 0x0a, 0x0b, 0x50, 0x52, 0x4f, 0x42, 0x41, 0x42, 0x49, 0x4c, 0x49, 0x54, 0x59,
+	0x10, 0x01, 0x12, 0x11, 0x0a, 0x0d, 0x52, 0x41, 0x54, 0x45, 0x5f, 0x4c, 0x49, 0x4d, 0x49, 0x54,
+	0x49, 0x4e, 0x47, 0x10, 0x02, 0x12, 0x0c, 0x0a, 0x08, 0x41, 0x44, 0x41, 0x50, 0x54, 0x49, 0x56,
+	0x45, 0x10, 0x03, 0x12, 0x0b, 0x0a, 0x07, 0x44, 0x59, 0x4e, 0x41, 0x4d, 0x49, 0x43, 0x10, 0x04,
+	0x32, 0x96, 0x01, 0x0a, 0x0f, 0x53, 0x74, 0x72, 0x61, 0x74, 0x65, 0x67, 0x79, 0x4d, 0x61, 0x6e,
+	0x61, 0x67, 0x65, 0x72, 0x12, 0x48, 0x0a, 0x0d, 0x47, 0x65, 0x74, 0x53, 0x74, 0x72, 0x61, 0x74,
+	0x65, 0x67, 0x69, 0x65, 0x73, 0x12, 0x19, 0x2e, 0x73, 0x61, 0x6d, 0x70, 0x6c, 0x69, 0x6e, 0x67,
+	0x2e, 0x53, 0x74, 0x72, 0x61, 0x74, 0x65, 0x67, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x1c, 0x2e, 0x73, 0x61, 0x6d, 0x70, 0x6c, 0x69, 0x6e, 0x67, 0x2e, 0x53, 0x74, 0x72, 0x61,
+	0x74, 0x65, 0x67, 0x69, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x39,
+	0x0a, 0x07, 0x50, 0x72, 0x6f, 0x6d, 0x6f, 0x74, 0x65, 0x12, 0x18, 0x2e, 0x73, 0x61, 0x6d, 0x70,
+	0x6c, 0x69, 0x6e, 0x67, 0x2e, 0x50, 0x72, 0x6f, 0x6d, 0x6f, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x12, 0x2e, 0x73, 0x61, 0x6d, 0x70, 0x6c, 0x69, 0x6e, 0x67, 0x2e, 0x4e,
+	0x75, 0x6c, 0x6c, 0x52, 0x65, 0x6c, 0x79, 0x22, 0x00, 0x32, 0x9e, 0x01, 0x0a, 0x10, 0x45, 0x76,
+	0x61, 0x6c, 0x75, 0x61, 0x74, 0x6f, 0x72, 0x4d, 0x61, 0x6e, 0x61, 0x67, 0x65, 0x72, 0x12, 0x3f,
+	0x0a, 0x0a, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x54, 0x61, 0x67, 0x73, 0x12, 0x1b, 0x2e, 0x73,
+	0x61, 0x6d, 0x70, 0x6c, 0x69, 0x6e, 0x67, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x54, 0x61,
+	0x67, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x12, 0x2e, 0x73, 0x61, 0x6d, 0x70,
+	0x6c, 0x69, 0x6e, 0x67, 0x2e, 0x4e, 0x75, 0x6c, 0x6c, 0x52, 0x65, 0x6c, 0x79, 0x22, 0x00, 0x12,
+	0x49, 0x0a, 0x0a, 0x47, 0x65, 0x74, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x12, 0x1b, 0x2e,
+	0x73, 0x61, 0x6d, 0x70, 0x6c, 0x69, 0x6e, 0x67, 0x2e, 0x47, 0x65, 0x74, 0x4d, 0x61, 0x74, 0x63,
+	0x68, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x73, 0x61, 0x6d,
+	0x70, 0x6c, 0x69, 0x6e, 0x67, 0x2e, 0x47, 0x65, 0x74, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x32, 0x4b, 0x0a, 0x11, 0x54, 0x72,
+	0x61, 0x63, 0x65, 0x47, 0x72, 0x61, 0x70, 0x68, 0x4d, 0x61, 0x6e, 0x61, 0x67, 0x65, 0x72, 0x12,
+	0x36, 0x0a, 0x05, 0x57, 0x61, 0x74, 0x63, 0x68, 0x12, 0x16, 0x2e, 0x73, 0x61, 0x6d, 0x70, 0x6c,
+	0x69, 0x6e, 0x67, 0x2e, 0x57, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x11, 0x2e, 0x68, 0x6f, 0x75, 0x79, 0x69, 0x2e, 0x47, 0x72, 0x61, 0x70, 0x68, 0x45, 0x76,
+	0x65, 0x6e, 0x74, 0x22, 0x00, 0x30, 0x01, 0x42, 0x2b, 0x5a, 0x29, 0x67, 0x69, 0x74, 0x68, 0x75,
+	0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x68, 0x6f, 0x75, 0x79, 0x69, 0x2d, 0x74, 0x72, 0x61, 0x63,
+	0x69, 0x6e, 0x67, 0x2f, 0x68, 0x6f, 0x75, 0x79, 0x69, 0x2f, 0x69, 0x64, 0x6c, 0x2f, 0x61, 0x70,
+	0x69, 0x5f, 0x76, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -827,7 +1062,7 @@ func file_dynamic_sampling_proto_rawDescGZIP() []byte {
 }
 
 var file_dynamic_sampling_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_dynamic_sampling_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_dynamic_sampling_proto_msgTypes = make([]protoimpl.MessageInfo, 15)
 var file_dynamic_sampling_proto_goTypes = []interface{}{
 	(Type)(0),                         // 0: sampling.Type
 	(*StrategyRequest)(nil),           // 1: sampling.StrategyRequest
@@ -839,13 +1074,19 @@ var file_dynamic_sampling_proto_goTypes = []interface{}{
 	(*PerOperationStrategy)(nil),      // 7: sampling.PerOperationStrategy
 	(*StrategiesResponse)(nil),        // 8: sampling.StrategiesResponse
 	(*NullRely)(nil),                  // 9: sampling.NullRely
-	(*UpdateTagsRequest)(nil),         // 10: sampling.UpdateTagsRequest
-	(*StrategyRequest_Operation)(nil), // 11: sampling.StrategyRequest.Operation
-	(*EvaluatingTag)(nil),             // 12: houyi.EvaluatingTag
-	(*Operation)(nil),                 // 13: houyi.Operation
+	(*PromoteRequest)(nil),            // 10: sampling.PromoteRequest
+	(*UpdateTagsRequest)(nil),         // 11: sampling.UpdateTagsRequest
+	(*GetMatchesRequest)(nil),         // 12: sampling.GetMatchesRequest
+	(*GetMatchesResponse)(nil),        // 13: sampling.GetMatchesResponse
+	(*WatchRequest)(nil),              // 14: sampling.WatchRequest
+	(*StrategyRequest_Operation)(nil), // 15: sampling.StrategyRequest.Operation
+	(*EvaluatingTag)(nil),             // 16: houyi.EvaluatingTag
+	(*EvaluatingRule)(nil),            // 17: houyi.EvaluatingRule
+	(*EvaluatorMatch)(nil),            // 18: houyi.EvaluatorMatch
+	(*GraphEvent)(nil),                // 19: houyi.GraphEvent
 }
 var file_dynamic_sampling_proto_depIdxs = []int32{
-	11, // 0: sampling.StrategyRequest.operations:type_name -> sampling.StrategyRequest.Operation
+	15, // 0: sampling.StrategyRequest.operations:type_name -> sampling.StrategyRequest.Operation
 	0,  // 1: sampling.PerOperationStrategy.type:type_name -> sampling.Type
 	2,  // 2: sampling.PerOperationStrategy.const:type_name -> sampling.ConstSampling
 	3,  // 3: sampling.PerOperationStrategy.probability:type_name -> sampling.ProbabilitySampling
@@ -853,18 +1094,24 @@ var file_dynamic_sampling_proto_depIdxs = []int32{
 	5,  // 5: sampling.PerOperationStrategy.adaptive:type_name -> sampling.AdaptiveSampling
 	6,  // 6: sampling.PerOperationStrategy.dynamic:type_name -> sampling.DynamicSampling
 	7,  // 7: sampling.StrategiesResponse.strategies:type_name -> sampling.PerOperationStrategy
-	12, // 8: sampling.UpdateTagsRequest.tags:type_name -> houyi.EvaluatingTag
-	1,  // 9: sampling.strategyManager.GetStrategies:input_type -> sampling.StrategyRequest
-	13, // 10: sampling.strategyManager.Promote:input_type -> houyi.Operation
-	10, // 11: sampling.EvaluatorManager.UpdateTags:input_type -> sampling.UpdateTagsRequest
-	8,  // 12: sampling.strategyManager.GetStrategies:output_type -> sampling.StrategiesResponse
-	9,  // 13: sampling.strategyManager.Promote:output_type -> sampling.NullRely
-	9,  // 14: sampling.EvaluatorManager.UpdateTags:output_type -> sampling.NullRely
-	12, // [12:15] is the sub-list for method output_type
-	9,  // [9:12] is the sub-list for method input_type
-	9,  // [9:9] is the sub-list for extension type_name
-	9,  // [9:9] is the sub-list for extension extendee
-	0,  // [0:9] is the sub-list for field type_name
+	16, // 8: sampling.UpdateTagsRequest.tags:type_name -> houyi.EvaluatingTag
+	17, // 9: sampling.UpdateTagsRequest.rules:type_name -> houyi.EvaluatingRule
+	18, // 10: sampling.GetMatchesResponse.matches:type_name -> houyi.EvaluatorMatch
+	1,  // 11: sampling.StrategyManager.GetStrategies:input_type -> sampling.StrategyRequest
+	10, // 12: sampling.StrategyManager.Promote:input_type -> sampling.PromoteRequest
+	11, // 13: sampling.EvaluatorManager.UpdateTags:input_type -> sampling.UpdateTagsRequest
+	12, // 14: sampling.EvaluatorManager.GetMatches:input_type -> sampling.GetMatchesRequest
+	14, // 15: sampling.TraceGraphManager.Watch:input_type -> sampling.WatchRequest
+	8,  // 16: sampling.StrategyManager.GetStrategies:output_type -> sampling.StrategiesResponse
+	9,  // 17: sampling.StrategyManager.Promote:output_type -> sampling.NullRely
+	9,  // 18: sampling.EvaluatorManager.UpdateTags:output_type -> sampling.NullRely
+	13, // 19: sampling.EvaluatorManager.GetMatches:output_type -> sampling.GetMatchesResponse
+	19, // 20: sampling.TraceGraphManager.Watch:output_type -> houyi.GraphEvent
+	16, // [16:21] is the sub-list for method output_type
+	11, // [11:16] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
 }
 
 func init() { file_dynamic_sampling_proto_init() }
@@ -983,7 +1230,7 @@ func file_dynamic_sampling_proto_init() {
 			}
 		}
 		file_dynamic_sampling_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UpdateTagsRequest); i {
+			switch v := v.(*PromoteRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -995,6 +1242,54 @@ func file_dynamic_sampling_proto_init() {
 			}
 		}
 		file_dynamic_sampling_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UpdateTagsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_dynamic_sampling_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetMatchesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_dynamic_sampling_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetMatchesResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_dynamic_sampling_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WatchRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_dynamic_sampling_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*StrategyRequest_Operation); i {
 			case 0:
 				return &v.state
@@ -1020,9 +1315,9 @@ func file_dynamic_sampling_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_dynamic_sampling_proto_rawDesc,
 			NumEnums:      1,
-			NumMessages:   11,
+			NumMessages:   15,
 			NumExtensions: 0,
-			NumServices:   2,
+			NumServices:   3,
 		},
 		GoTypes:           file_dynamic_sampling_proto_goTypes,
 		DependencyIndexes: file_dynamic_sampling_proto_depIdxs,
@@ -18,7 +18,7 @@ const _ = grpc.SupportPackageIsVersion7
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type StrategyManagerClient interface {
 	GetStrategies(ctx context.Context, in *StrategyRequest, opts ...grpc.CallOption) (*StrategiesResponse, error)
-	Promote(ctx context.Context, in *Operation, opts ...grpc.CallOption) (*NullRely, error)
+	Promote(ctx context.Context, in *PromoteRequest, opts ...grpc.CallOption) (*NullRely, error)
 }
 
 type strategyManagerClient struct {
@@ -38,7 +38,7 @@ func (c *strategyManagerClient) GetStrategies(ctx context.Context, in *StrategyR
 	return out, nil
 }
 
-func (c *strategyManagerClient) Promote(ctx context.Context, in *Operation, opts ...grpc.CallOption) (*NullRely, error) {
+func (c *strategyManagerClient) Promote(ctx context.Context, in *PromoteRequest, opts ...grpc.CallOption) (*NullRely, error) {
 	out := new(NullRely)
 	err := c.cc.Invoke(ctx, "/sampling.strategyManager/Promote", in, out, opts...)
 	if err != nil {
@@ -52,7 +52,7 @@ func (c *strategyManagerClient) Promote(ctx context.Context, in *Operation, opts
 // for forward compatibility
 type StrategyManagerServer interface {
 	GetStrategies(context.Context, *StrategyRequest) (*StrategiesResponse, error)
-	Promote(context.Context, *Operation) (*NullRely, error)
+	Promote(context.Context, *PromoteRequest) (*NullRely, error)
 	mustEmbedUnimplementedStrategyManagerServer()
 }
 
@@ -63,7 +63,7 @@ type UnimplementedStrategyManagerServer struct {
 func (UnimplementedStrategyManagerServer) GetStrategies(context.Context, *StrategyRequest) (*StrategiesResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetStrategies not implemented")
 }
-func (UnimplementedStrategyManagerServer) Promote(context.Context, *Operation) (*NullRely, error) {
+func (UnimplementedStrategyManagerServer) Promote(context.Context, *PromoteRequest) (*NullRely, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Promote not implemented")
 }
 func (UnimplementedStrategyManagerServer) mustEmbedUnimplementedStrategyManagerServer() {}
@@ -98,7 +98,7 @@ func _StrategyManager_GetStrategies_Handler(srv interface{}, ctx context.Context
 }
 
 func _StrategyManager_Promote_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Operation)
+	in := new(PromoteRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
@@ -110,7 +110,7 @@ func _StrategyManager_Promote_Handler(srv interface{}, ctx context.Context, dec
 		FullMethod: "/sampling.strategyManager/Promote",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(StrategyManagerServer).Promote(ctx, req.(*Operation))
+		return srv.(StrategyManagerServer).Promote(ctx, req.(*PromoteRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -137,6 +137,7 @@ var _StrategyManager_serviceDesc = grpc.ServiceDesc{
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type EvaluatorManagerClient interface {
 	UpdateTags(ctx context.Context, in *UpdateTagsRequest, opts ...grpc.CallOption) (*NullRely, error)
+	GetMatches(ctx context.Context, in *GetMatchesRequest, opts ...grpc.CallOption) (*GetMatchesResponse, error)
 }
 
 type evaluatorManagerClient struct {
@@ -156,11 +157,21 @@ func (c *evaluatorManagerClient) UpdateTags(ctx context.Context, in *UpdateTagsR
 	return out, nil
 }
 
+func (c *evaluatorManagerClient) GetMatches(ctx context.Context, in *GetMatchesRequest, opts ...grpc.CallOption) (*GetMatchesResponse, error) {
+	out := new(GetMatchesResponse)
+	err := c.cc.Invoke(ctx, "/sampling.EvaluatorManager/GetMatches", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // EvaluatorManagerServer is the server API for EvaluatorManager service.
 // All implementations must embed UnimplementedEvaluatorManagerServer
 // for forward compatibility
 type EvaluatorManagerServer interface {
 	UpdateTags(context.Context, *UpdateTagsRequest) (*NullRely, error)
+	GetMatches(context.Context, *GetMatchesRequest) (*GetMatchesResponse, error)
 	mustEmbedUnimplementedEvaluatorManagerServer()
 }
 
@@ -171,6 +182,9 @@ type UnimplementedEvaluatorManagerServer struct {
 func (UnimplementedEvaluatorManagerServer) UpdateTags(context.Context, *UpdateTagsRequest) (*NullRely, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UpdateTags not implemented")
 }
+func (UnimplementedEvaluatorManagerServer) GetMatches(context.Context, *GetMatchesRequest) (*GetMatchesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMatches not implemented")
+}
 func (UnimplementedEvaluatorManagerServer) mustEmbedUnimplementedEvaluatorManagerServer() {}
 
 // UnsafeEvaluatorManagerServer may be embedded to opt out of forward compatibility for this service.
@@ -202,6 +216,24 @@ func _EvaluatorManager_UpdateTags_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _EvaluatorManager_GetMatches_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMatchesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EvaluatorManagerServer).GetMatches(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sampling.EvaluatorManager/GetMatches",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EvaluatorManagerServer).GetMatches(ctx, req.(*GetMatchesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _EvaluatorManager_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "sampling.EvaluatorManager",
 	HandlerType: (*EvaluatorManagerServer)(nil),
@@ -210,7 +242,121 @@ var _EvaluatorManager_serviceDesc = grpc.ServiceDesc{
 			MethodName: "UpdateTags",
 			Handler:    _EvaluatorManager_UpdateTags_Handler,
 		},
+		{
+			MethodName: "GetMatches",
+			Handler:    _EvaluatorManager_GetMatches_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "dynamic_sampling.proto",
 }
+
+// TraceGraphManagerClient is the client API for TraceGraphManager service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type TraceGraphManagerClient interface {
+	Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (TraceGraphManager_WatchClient, error)
+}
+
+type traceGraphManagerClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTraceGraphManagerClient(cc grpc.ClientConnInterface) TraceGraphManagerClient {
+	return &traceGraphManagerClient{cc}
+}
+
+func (c *traceGraphManagerClient) Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (TraceGraphManager_WatchClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_TraceGraphManager_serviceDesc.Streams[0], "/sampling.TraceGraphManager/Watch", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &traceGraphManagerWatchClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type TraceGraphManager_WatchClient interface {
+	Recv() (*GraphEvent, error)
+	grpc.ClientStream
+}
+
+type traceGraphManagerWatchClient struct {
+	grpc.ClientStream
+}
+
+func (x *traceGraphManagerWatchClient) Recv() (*GraphEvent, error) {
+	m := new(GraphEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// TraceGraphManagerServer is the server API for TraceGraphManager service.
+// All implementations must embed UnimplementedTraceGraphManagerServer
+// for forward compatibility
+type TraceGraphManagerServer interface {
+	Watch(*WatchRequest, TraceGraphManager_WatchServer) error
+	mustEmbedUnimplementedTraceGraphManagerServer()
+}
+
+// UnimplementedTraceGraphManagerServer must be embedded to have forward compatible implementations.
+type UnimplementedTraceGraphManagerServer struct {
+}
+
+func (UnimplementedTraceGraphManagerServer) Watch(*WatchRequest, TraceGraphManager_WatchServer) error {
+	return status.Errorf(codes.Unimplemented, "method Watch not implemented")
+}
+func (UnimplementedTraceGraphManagerServer) mustEmbedUnimplementedTraceGraphManagerServer() {}
+
+// UnsafeTraceGraphManagerServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TraceGraphManagerServer will
+// result in compilation errors.
+type UnsafeTraceGraphManagerServer interface {
+	mustEmbedUnimplementedTraceGraphManagerServer()
+}
+
+func RegisterTraceGraphManagerServer(s grpc.ServiceRegistrar, srv TraceGraphManagerServer) {
+	s.RegisterService(&_TraceGraphManager_serviceDesc, srv)
+}
+
+func _TraceGraphManager_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TraceGraphManagerServer).Watch(m, &traceGraphManagerWatchServer{stream})
+}
+
+type TraceGraphManager_WatchServer interface {
+	Send(*GraphEvent) error
+	grpc.ServerStream
+}
+
+type traceGraphManagerWatchServer struct {
+	grpc.ServerStream
+}
+
+func (x *traceGraphManagerWatchServer) Send(m *GraphEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+var _TraceGraphManager_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "sampling.TraceGraphManager",
+	HandlerType: (*TraceGraphManagerServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Watch",
+			Handler:       _TraceGraphManager_Watch_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "dynamic_sampling.proto",
+}
@@ -18,6 +18,7 @@ const _ = grpc.SupportPackageIsVersion7
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type SeedClient interface {
 	Sync(ctx context.Context, in *Message, opts ...grpc.CallOption) (*NullReply, error)
+	Pull(ctx context.Context, in *PullRequest, opts ...grpc.CallOption) (*GraphState, error)
 }
 
 type seedClient struct {
@@ -37,11 +38,21 @@ func (c *seedClient) Sync(ctx context.Context, in *Message, opts ...grpc.CallOpt
 	return out, nil
 }
 
+func (c *seedClient) Pull(ctx context.Context, in *PullRequest, opts ...grpc.CallOption) (*GraphState, error) {
+	out := new(GraphState)
+	err := c.cc.Invoke(ctx, "/gossip.Seed/Pull", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // SeedServer is the server API for Seed service.
 // All implementations must embed UnimplementedSeedServer
 // for forward compatibility
 type SeedServer interface {
 	Sync(context.Context, *Message) (*NullReply, error)
+	Pull(context.Context, *PullRequest) (*GraphState, error)
 	mustEmbedUnimplementedSeedServer()
 }
 
@@ -52,6 +63,9 @@ type UnimplementedSeedServer struct {
 func (UnimplementedSeedServer) Sync(context.Context, *Message) (*NullReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Sync not implemented")
 }
+func (UnimplementedSeedServer) Pull(context.Context, *PullRequest) (*GraphState, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Pull not implemented")
+}
 func (UnimplementedSeedServer) mustEmbedUnimplementedSeedServer() {}
 
 // UnsafeSeedServer may be embedded to opt out of forward compatibility for this service.
@@ -83,6 +97,24 @@ func _Seed_Sync_Handler(srv interface{}, ctx context.Context, dec func(interface
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Seed_Pull_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PullRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SeedServer).Pull(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gossip.Seed/Pull",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SeedServer).Pull(ctx, req.(*PullRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Seed_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "gossip.Seed",
 	HandlerType: (*SeedServer)(nil),
@@ -91,6 +123,10 @@ var _Seed_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Sync",
 			Handler:    _Seed_Sync_Handler,
 		},
+		{
+			MethodName: "Pull",
+			Handler:    _Seed_Pull_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "gossip.proto",
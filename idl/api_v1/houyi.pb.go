@@ -39,6 +39,64 @@ const (
 // of the legacy proto package is being used.
 const _ = proto.ProtoPackageIsVersion4
 
+type GraphEvent_EventType int32
+
+const (
+	GraphEvent_OPERATION_ADDED   GraphEvent_EventType = 0
+	GraphEvent_OPERATION_REMOVED GraphEvent_EventType = 1
+	GraphEvent_RELATION_ADDED    GraphEvent_EventType = 2
+	GraphEvent_RELATION_REMOVED  GraphEvent_EventType = 3
+	GraphEvent_INGRESS_ADDED     GraphEvent_EventType = 4
+	GraphEvent_INGRESS_REMOVED   GraphEvent_EventType = 5
+)
+
+// Enum value maps for GraphEvent_EventType.
+var (
+	GraphEvent_EventType_name = map[int32]string{
+		0: "OPERATION_ADDED",
+		1: "OPERATION_REMOVED",
+		2: "RELATION_ADDED",
+		3: "RELATION_REMOVED",
+		4: "INGRESS_ADDED",
+		5: "INGRESS_REMOVED",
+	}
+	GraphEvent_EventType_value = map[string]int32{
+		"OPERATION_ADDED":   0,
+		"OPERATION_REMOVED": 1,
+		"RELATION_ADDED":    2,
+		"RELATION_REMOVED":  3,
+		"INGRESS_ADDED":     4,
+		"INGRESS_REMOVED":   5,
+	}
+)
+
+func (x GraphEvent_EventType) Enum() *GraphEvent_EventType {
+	p := new(GraphEvent_EventType)
+	*p = x
+	return p
+}
+
+func (x GraphEvent_EventType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (GraphEvent_EventType) Descriptor() protoreflect.EnumDescriptor {
+	return file_houyi_proto_enumTypes[0].Descriptor()
+}
+
+func (GraphEvent_EventType) Type() protoreflect.EnumType {
+	return &file_houyi_proto_enumTypes[0]
+}
+
+func (x GraphEvent_EventType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use GraphEvent_EventType.Descriptor instead.
+func (GraphEvent_EventType) EnumDescriptor() ([]byte, []int) {
+	return file_houyi_proto_rawDescGZIP(), []int{8, 0}
+}
+
 type EvaluatingTag_ValueType int32
 
 const (
@@ -75,11 +133,11 @@ func (x EvaluatingTag_ValueType) String() string {
 }
 
 func (EvaluatingTag_ValueType) Descriptor() protoreflect.EnumDescriptor {
-	return file_houyi_proto_enumTypes[0].Descriptor()
+	return file_houyi_proto_enumTypes[1].Descriptor()
 }
 
 func (EvaluatingTag_ValueType) Type() protoreflect.EnumType {
-	return &file_houyi_proto_enumTypes[0]
+	return &file_houyi_proto_enumTypes[1]
 }
 
 func (x EvaluatingTag_ValueType) Number() protoreflect.EnumNumber {
@@ -88,7 +146,7 @@ func (x EvaluatingTag_ValueType) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use EvaluatingTag_ValueType.Descriptor instead.
 func (EvaluatingTag_ValueType) EnumDescriptor() ([]byte, []int) {
-	return file_houyi_proto_rawDescGZIP(), []int{2, 0}
+	return file_houyi_proto_rawDescGZIP(), []int{9, 0}
 }
 
 type EvaluatingTag_OperationType int32
@@ -133,11 +191,11 @@ func (x EvaluatingTag_OperationType) String() string {
 }
 
 func (EvaluatingTag_OperationType) Descriptor() protoreflect.EnumDescriptor {
-	return file_houyi_proto_enumTypes[1].Descriptor()
+	return file_houyi_proto_enumTypes[2].Descriptor()
 }
 
 func (EvaluatingTag_OperationType) Type() protoreflect.EnumType {
-	return &file_houyi_proto_enumTypes[1]
+	return &file_houyi_proto_enumTypes[2]
 }
 
 func (x EvaluatingTag_OperationType) Number() protoreflect.EnumNumber {
@@ -146,7 +204,7 @@ func (x EvaluatingTag_OperationType) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use EvaluatingTag_OperationType.Descriptor instead.
 func (EvaluatingTag_OperationType) EnumDescriptor() ([]byte, []int) {
-	return file_houyi_proto_rawDescGZIP(), []int{2, 1}
+	return file_houyi_proto_rawDescGZIP(), []int{9, 1}
 }
 
 type Operation struct {
@@ -259,24 +317,25 @@ func (x *Relation) GetTo() *Operation {
 	return nil
 }
 
-type EvaluatingTag struct {
+// RelationStats is the statistics of calls of a relation observed by a collector during a reporting period.
+type RelationStats struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	TagName       string                      `protobuf:"bytes,1,opt,name=tagName,proto3" json:"tagName,omitempty"`
-	OperationType EvaluatingTag_OperationType `protobuf:"varint,2,opt,name=operationType,proto3,enum=houyi.EvaluatingTag_OperationType" json:"operationType,omitempty"`
-	ValueType     EvaluatingTag_ValueType     `protobuf:"varint,3,opt,name=valueType,proto3,enum=houyi.EvaluatingTag_ValueType" json:"valueType,omitempty"`
-	// Types that are assignable to Value:
-	//	*EvaluatingTag_IntegerVal
-	//	*EvaluatingTag_FloatVal
-	//	*EvaluatingTag_BooleanVal
-	//	*EvaluatingTag_StringVal
-	Value isEvaluatingTag_Value `protobuf_oneof:"value"`
+	Relation *Relation `protobuf:"bytes,1,opt,name=relation,proto3" json:"relation,omitempty"`
+	Calls    int64     `protobuf:"varint,2,opt,name=calls,proto3" json:"calls,omitempty"`
+	Errors   int64     `protobuf:"varint,3,opt,name=errors,proto3" json:"errors,omitempty"`
+	// durationBuckets is a histogram of durations of calls. The i-th bucket counts calls whose durations are in
+	// [2^(i-1), 2^i) microseconds, and the first bucket counts calls shorter than 1 microsecond.
+	DurationBuckets []int64 `protobuf:"varint,4,rep,packed,name=durationBuckets,proto3" json:"durationBuckets,omitempty"`
+	// firstSeen and lastSeen are unix timestamps in nanoseconds.
+	FirstSeen int64 `protobuf:"varint,5,opt,name=firstSeen,proto3" json:"firstSeen,omitempty"`
+	LastSeen  int64 `protobuf:"varint,6,opt,name=lastSeen,proto3" json:"lastSeen,omitempty"`
 }
 
-func (x *EvaluatingTag) Reset() {
-	*x = EvaluatingTag{}
+func (x *RelationStats) Reset() {
+	*x = RelationStats{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_houyi_proto_msgTypes[2]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -284,13 +343,13 @@ func (x *EvaluatingTag) Reset() {
 	}
 }
 
-func (x *EvaluatingTag) String() string {
+func (x *RelationStats) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*EvaluatingTag) ProtoMessage() {}
+func (*RelationStats) ProtoMessage() {}
 
-func (x *EvaluatingTag) ProtoReflect() protoreflect.Message {
+func (x *RelationStats) ProtoReflect() protoreflect.Message {
 	mi := &file_houyi_proto_msgTypes[2]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -302,212 +361,1087 @@ func (x *EvaluatingTag) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use EvaluatingTag.ProtoReflect.Descriptor instead.
-func (*EvaluatingTag) Descriptor() ([]byte, []int) {
+// Deprecated: Use RelationStats.ProtoReflect.Descriptor instead.
+func (*RelationStats) Descriptor() ([]byte, []int) {
 	return file_houyi_proto_rawDescGZIP(), []int{2}
 }
 
-func (x *EvaluatingTag) GetTagName() string {
+func (x *RelationStats) GetRelation() *Relation {
 	if x != nil {
-		return x.TagName
+		return x.Relation
 	}
-	return ""
+	return nil
 }
 
-func (x *EvaluatingTag) GetOperationType() EvaluatingTag_OperationType {
+func (x *RelationStats) GetCalls() int64 {
 	if x != nil {
-		return x.OperationType
+		return x.Calls
 	}
-	return EvaluatingTag_EQUAL_TO
+	return 0
 }
 
-func (x *EvaluatingTag) GetValueType() EvaluatingTag_ValueType {
+func (x *RelationStats) GetErrors() int64 {
 	if x != nil {
-		return x.ValueType
+		return x.Errors
 	}
-	return EvaluatingTag_INTEGER
+	return 0
 }
 
-func (m *EvaluatingTag) GetValue() isEvaluatingTag_Value {
-	if m != nil {
-		return m.Value
+func (x *RelationStats) GetDurationBuckets() []int64 {
+	if x != nil {
+		return x.DurationBuckets
 	}
 	return nil
 }
 
-func (x *EvaluatingTag) GetIntegerVal() int64 {
-	if x, ok := x.GetValue().(*EvaluatingTag_IntegerVal); ok {
-		return x.IntegerVal
+func (x *RelationStats) GetFirstSeen() int64 {
+	if x != nil {
+		return x.FirstSeen
 	}
 	return 0
 }
 
-func (x *EvaluatingTag) GetFloatVal() float64 {
-	if x, ok := x.GetValue().(*EvaluatingTag_FloatVal); ok {
-		return x.FloatVal
+func (x *RelationStats) GetLastSeen() int64 {
+	if x != nil {
+		return x.LastSeen
 	}
 	return 0
 }
 
-func (x *EvaluatingTag) GetBooleanVal() bool {
-	if x, ok := x.GetValue().(*EvaluatingTag_BooleanVal); ok {
-		return x.BooleanVal
+// Dot identifies an addition of an operation or a relation made by a replica of trace graph. Counters of dots made
+// by the same replica start from 1 and increase one by one.
+type Dot struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Replica string `protobuf:"bytes,1,opt,name=replica,proto3" json:"replica,omitempty"`
+	Counter uint64 `protobuf:"varint,2,opt,name=counter,proto3" json:"counter,omitempty"`
+}
+
+func (x *Dot) Reset() {
+	*x = Dot{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_houyi_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
 	}
-	return false
 }
 
-func (x *EvaluatingTag) GetStringVal() string {
-	if x, ok := x.GetValue().(*EvaluatingTag_StringVal); ok {
-		return x.StringVal
+func (x *Dot) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Dot) ProtoMessage() {}
+
+func (x *Dot) ProtoReflect() protoreflect.Message {
+	mi := &file_houyi_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Dot.ProtoReflect.Descriptor instead.
+func (*Dot) Descriptor() ([]byte, []int) {
+	return file_houyi_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Dot) GetReplica() string {
+	if x != nil {
+		return x.Replica
 	}
 	return ""
 }
 
-type isEvaluatingTag_Value interface {
-	isEvaluatingTag_Value()
+func (x *Dot) GetCounter() uint64 {
+	if x != nil {
+		return x.Counter
+	}
+	return 0
 }
 
-type EvaluatingTag_IntegerVal struct {
-	IntegerVal int64 `protobuf:"varint,4,opt,name=integerVal,proto3,oneof"`
+// CausalContext is the set of dots a replica of trace graph has seen. versions is a vector clock covering dots from 1
+// to the version of each replica, and dots are other seen dots which are not contiguous yet.
+type CausalContext struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Versions map[string]uint64 `protobuf:"bytes,1,rep,name=versions,proto3" json:"versions,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	Dots     []*Dot            `protobuf:"bytes,2,rep,name=dots,proto3" json:"dots,omitempty"`
 }
 
-type EvaluatingTag_FloatVal struct {
-	FloatVal float64 `protobuf:"fixed64,5,opt,name=floatVal,proto3,oneof"`
+func (x *CausalContext) Reset() {
+	*x = CausalContext{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_houyi_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
 
-type EvaluatingTag_BooleanVal struct {
-	BooleanVal bool `protobuf:"varint,6,opt,name=booleanVal,proto3,oneof"`
+func (x *CausalContext) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-type EvaluatingTag_StringVal struct {
-	StringVal string `protobuf:"bytes,7,opt,name=stringVal,proto3,oneof"`
+func (*CausalContext) ProtoMessage() {}
+
+func (x *CausalContext) ProtoReflect() protoreflect.Message {
+	mi := &file_houyi_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
 
-func (*EvaluatingTag_IntegerVal) isEvaluatingTag_Value() {}
+// Deprecated: Use CausalContext.ProtoReflect.Descriptor instead.
+func (*CausalContext) Descriptor() ([]byte, []int) {
+	return file_houyi_proto_rawDescGZIP(), []int{4}
+}
 
-func (*EvaluatingTag_FloatVal) isEvaluatingTag_Value() {}
+func (x *CausalContext) GetVersions() map[string]uint64 {
+	if x != nil {
+		return x.Versions
+	}
+	return nil
+}
 
-func (*EvaluatingTag_BooleanVal) isEvaluatingTag_Value() {}
+func (x *CausalContext) GetDots() []*Dot {
+	if x != nil {
+		return x.Dots
+	}
+	return nil
+}
 
-func (*EvaluatingTag_StringVal) isEvaluatingTag_Value() {}
+type VersionedOperation struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
 
-var File_houyi_proto protoreflect.FileDescriptor
+	Operation *Operation `protobuf:"bytes,1,opt,name=operation,proto3" json:"operation,omitempty"`
+	Dots      []*Dot     `protobuf:"bytes,2,rep,name=dots,proto3" json:"dots,omitempty"`
+}
 
-var file_houyi_proto_rawDesc = []byte{
-	0x0a, 0x0b, 0x68, 0x6f, 0x75, 0x79, 0x69, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x05, 0x68,
-	0x6f, 0x75, 0x79, 0x69, 0x22, 0x43, 0x0a, 0x09, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f,
-	0x6e, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x07, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x6f,
-	0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09,
-	0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x52, 0x0a, 0x08, 0x52, 0x65, 0x6c,
-	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x24, 0x0a, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x68, 0x6f, 0x75, 0x79, 0x69, 0x2e, 0x4f, 0x70, 0x65, 0x72,
-	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x12, 0x20, 0x0a, 0x02, 0x74,
-	0x6f, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x68, 0x6f, 0x75, 0x79, 0x69, 0x2e,
-	0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x02, 0x74, 0x6f, 0x22, 0x86, 0x04,
-	0x0a, 0x0d, 0x45, 0x76, 0x61, 0x6c, 0x75, 0x61, 0x74, 0x69, 0x6e, 0x67, 0x54, 0x61, 0x67, 0x12,
-	0x18, 0x0a, 0x07, 0x74, 0x61, 0x67, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x07, 0x74, 0x61, 0x67, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x48, 0x0a, 0x0d, 0x6f, 0x70, 0x65,
-	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e,
-	0x32, 0x22, 0x2e, 0x68, 0x6f, 0x75, 0x79, 0x69, 0x2e, 0x45, 0x76, 0x61, 0x6c, 0x75, 0x61, 0x74,
-	0x69, 0x6e, 0x67, 0x54, 0x61, 0x67, 0x2e, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e,
-	0x54, 0x79, 0x70, 0x65, 0x52, 0x0d, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x54,
-	0x79, 0x70, 0x65, 0x12, 0x3c, 0x0a, 0x09, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x54, 0x79, 0x70, 0x65,
-	0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1e, 0x2e, 0x68, 0x6f, 0x75, 0x79, 0x69, 0x2e, 0x45,
-	0x76, 0x61, 0x6c, 0x75, 0x61, 0x74, 0x69, 0x6e, 0x67, 0x54, 0x61, 0x67, 0x2e, 0x56, 0x61, 0x6c,
-	0x75, 0x65, 0x54, 0x79, 0x70, 0x65, 0x52, 0x09, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x54, 0x79, 0x70,
-	0x65, 0x12, 0x20, 0x0a, 0x0a, 0x69, 0x6e, 0x74, 0x65, 0x67, 0x65, 0x72, 0x56, 0x61, 0x6c, 0x18,
-	0x04, 0x20, 0x01, 0x28, 0x03, 0x48, 0x00, 0x52, 0x0a, 0x69, 0x6e, 0x74, 0x65, 0x67, 0x65, 0x72,
-	0x56, 0x61, 0x6c, 0x12, 0x1c, 0x0a, 0x08, 0x66, 0x6c, 0x6f, 0x61, 0x74, 0x56, 0x61, 0x6c, 0x18,
-	0x05, 0x20, 0x01, 0x28, 0x01, 0x48, 0x00, 0x52, 0x08, 0x66, 0x6c, 0x6f, 0x61, 0x74, 0x56, 0x61,
-	0x6c, 0x12, 0x20, 0x0a, 0x0a, 0x62, 0x6f, 0x6f, 0x6c, 0x65, 0x61, 0x6e, 0x56, 0x61, 0x6c, 0x18,
-	0x06, 0x20, 0x01, 0x28, 0x08, 0x48, 0x00, 0x52, 0x0a, 0x62, 0x6f, 0x6f, 0x6c, 0x65, 0x61, 0x6e,
-	0x56, 0x61, 0x6c, 0x12, 0x1e, 0x0a, 0x09, 0x73, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x56, 0x61, 0x6c,
-	0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x09, 0x73, 0x74, 0x72, 0x69, 0x6e, 0x67,
-	0x56, 0x61, 0x6c, 0x22, 0x3c, 0x0a, 0x09, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x54, 0x79, 0x70, 0x65,
-	0x12, 0x0b, 0x0a, 0x07, 0x49, 0x4e, 0x54, 0x45, 0x47, 0x45, 0x52, 0x10, 0x00, 0x12, 0x09, 0x0a,
-	0x05, 0x46, 0x4c, 0x4f, 0x41, 0x54, 0x10, 0x01, 0x12, 0x0b, 0x0a, 0x07, 0x42, 0x4f, 0x4f, 0x4c,
-	0x45, 0x41, 0x4e, 0x10, 0x02, 0x12, 0x0a, 0x0a, 0x06, 0x53, 0x54, 0x52, 0x49, 0x4e, 0x47, 0x10,
-	0x04, 0x22, 0x89, 0x01, 0x0a, 0x0d, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x54,
-	0x79, 0x70, 0x65, 0x12, 0x0c, 0x0a, 0x08, 0x45, 0x51, 0x55, 0x41, 0x4c, 0x5f, 0x54, 0x4f, 0x10,
-	0x00, 0x12, 0x10, 0x0a, 0x0c, 0x4e, 0x4f, 0x54, 0x5f, 0x45, 0x51, 0x55, 0x41, 0x4c, 0x5f, 0x54,
-	0x4f, 0x10, 0x01, 0x12, 0x10, 0x0a, 0x0c, 0x47, 0x52, 0x45, 0x41, 0x54, 0x45, 0x52, 0x5f, 0x54,
-	0x48, 0x41, 0x4e, 0x10, 0x02, 0x12, 0x1c, 0x0a, 0x18, 0x47, 0x52, 0x45, 0x41, 0x54, 0x45, 0x52,
-	0x5f, 0x54, 0x48, 0x41, 0x4e, 0x5f, 0x4f, 0x52, 0x5f, 0x45, 0x51, 0x55, 0x41, 0x4c, 0x5f, 0x54,
-	0x4f, 0x10, 0x03, 0x12, 0x0d, 0x0a, 0x09, 0x4c, 0x45, 0x53, 0x53, 0x5f, 0x54, 0x48, 0x41, 0x4e,
-	0x10, 0x04, 0x12, 0x19, 0x0a, 0x15, 0x4c, 0x45, 0x53, 0x53, 0x5f, 0x54, 0x48, 0x41, 0x4e, 0x5f,
-	0x4f, 0x52, 0x5f, 0x45, 0x51, 0x55, 0x41, 0x4c, 0x5f, 0x54, 0x4f, 0x10, 0x05, 0x42, 0x07, 0x0a,
-	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x42, 0x2b, 0x5a, 0x29, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62,
-	0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x68, 0x6f, 0x75, 0x79, 0x69, 0x2d, 0x74, 0x72, 0x61, 0x63, 0x69,
-	0x6e, 0x67, 0x2f, 0x68, 0x6f, 0x75, 0x79, 0x69, 0x2f, 0x69, 0x64, 0x6c, 0x2f, 0x61, 0x70, 0x69,
-	0x5f, 0x76, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+func (x *VersionedOperation) Reset() {
+	*x = VersionedOperation{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_houyi_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
 
-var (
-	file_houyi_proto_rawDescOnce sync.Once
-	file_houyi_proto_rawDescData = file_houyi_proto_rawDesc
-)
+func (x *VersionedOperation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
 
-func file_houyi_proto_rawDescGZIP() []byte {
-	file_houyi_proto_rawDescOnce.Do(func() {
-		file_houyi_proto_rawDescData = protoimpl.X.CompressGZIP(file_houyi_proto_rawDescData)
-	})
-	return file_houyi_proto_rawDescData
+func (*VersionedOperation) ProtoMessage() {}
+
+func (x *VersionedOperation) ProtoReflect() protoreflect.Message {
+	mi := &file_houyi_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
 
-var file_houyi_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
-var file_houyi_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
-var file_houyi_proto_goTypes = []interface{}{
-	(EvaluatingTag_ValueType)(0),     // 0: houyi.EvaluatingTag.ValueType
-	(EvaluatingTag_OperationType)(0), // 1: houyi.EvaluatingTag.OperationType
-	(*Operation)(nil),                // 2: houyi.Operation
-	(*Relation)(nil),                 // 3: houyi.Relation
-	(*EvaluatingTag)(nil),            // 4: houyi.EvaluatingTag
+// Deprecated: Use VersionedOperation.ProtoReflect.Descriptor instead.
+func (*VersionedOperation) Descriptor() ([]byte, []int) {
+	return file_houyi_proto_rawDescGZIP(), []int{5}
 }
-var file_houyi_proto_depIdxs = []int32{
-	2, // 0: houyi.Relation.from:type_name -> houyi.Operation
-	2, // 1: houyi.Relation.to:type_name -> houyi.Operation
-	1, // 2: houyi.EvaluatingTag.operationType:type_name -> houyi.EvaluatingTag.OperationType
-	0, // 3: houyi.EvaluatingTag.valueType:type_name -> houyi.EvaluatingTag.ValueType
-	4, // [4:4] is the sub-list for method output_type
-	4, // [4:4] is the sub-list for method input_type
-	4, // [4:4] is the sub-list for extension type_name
-	4, // [4:4] is the sub-list for extension extendee
-	0, // [0:4] is the sub-list for field type_name
+
+func (x *VersionedOperation) GetOperation() *Operation {
+	if x != nil {
+		return x.Operation
+	}
+	return nil
 }
 
-func init() { file_houyi_proto_init() }
-func file_houyi_proto_init() {
-	if File_houyi_proto != nil {
-		return
+func (x *VersionedOperation) GetDots() []*Dot {
+	if x != nil {
+		return x.Dots
 	}
-	if !protoimpl.UnsafeEnabled {
-		file_houyi_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Operation); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_houyi_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Relation); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
+	return nil
+}
+
+type VersionedRelation struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Relation *Relation `protobuf:"bytes,1,opt,name=relation,proto3" json:"relation,omitempty"`
+	Dots     []*Dot    `protobuf:"bytes,2,rep,name=dots,proto3" json:"dots,omitempty"`
+}
+
+func (x *VersionedRelation) Reset() {
+	*x = VersionedRelation{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_houyi_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *VersionedRelation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VersionedRelation) ProtoMessage() {}
+
+func (x *VersionedRelation) ProtoReflect() protoreflect.Message {
+	mi := &file_houyi_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
 		}
-		file_houyi_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*EvaluatingTag); i {
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VersionedRelation.ProtoReflect.Descriptor instead.
+func (*VersionedRelation) Descriptor() ([]byte, []int) {
+	return file_houyi_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *VersionedRelation) GetRelation() *Relation {
+	if x != nil {
+		return x.Relation
+	}
+	return nil
+}
+
+func (x *VersionedRelation) GetDots() []*Dot {
+	if x != nil {
+		return x.Dots
+	}
+	return nil
+}
+
+// GraphDelta is the state of trace graph as an observed-remove set of operations and relations, or a part of it.
+// An element exists while any of its dots exists, and a seen dot which is no longer of any element has been removed.
+// Merging deltas in any order, any number of times results in the same state.
+type GraphDelta struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Operations []*VersionedOperation `protobuf:"bytes,1,rep,name=operations,proto3" json:"operations,omitempty"`
+	Relations  []*VersionedRelation  `protobuf:"bytes,2,rep,name=relations,proto3" json:"relations,omitempty"`
+	Context    *CausalContext        `protobuf:"bytes,3,opt,name=context,proto3" json:"context,omitempty"`
+}
+
+func (x *GraphDelta) Reset() {
+	*x = GraphDelta{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_houyi_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GraphDelta) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GraphDelta) ProtoMessage() {}
+
+func (x *GraphDelta) ProtoReflect() protoreflect.Message {
+	mi := &file_houyi_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GraphDelta.ProtoReflect.Descriptor instead.
+func (*GraphDelta) Descriptor() ([]byte, []int) {
+	return file_houyi_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GraphDelta) GetOperations() []*VersionedOperation {
+	if x != nil {
+		return x.Operations
+	}
+	return nil
+}
+
+func (x *GraphDelta) GetRelations() []*VersionedRelation {
+	if x != nil {
+		return x.Relations
+	}
+	return nil
+}
+
+func (x *GraphDelta) GetContext() *CausalContext {
+	if x != nil {
+		return x.Context
+	}
+	return nil
+}
+
+// GraphEvent is a change of trace graph.
+type GraphEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Type GraphEvent_EventType `protobuf:"varint,1,opt,name=type,proto3,enum=houyi.GraphEvent_EventType" json:"type,omitempty"`
+	// operation is set for events of operations and ingresses, and relation is set for events of relations.
+	Operation *Operation `protobuf:"bytes,2,opt,name=operation,proto3" json:"operation,omitempty"`
+	Relation  *Relation  `protobuf:"bytes,3,opt,name=relation,proto3" json:"relation,omitempty"`
+	// timestamp is an unix timestamp in nanoseconds.
+	Timestamp int64 `protobuf:"varint,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+}
+
+func (x *GraphEvent) Reset() {
+	*x = GraphEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_houyi_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GraphEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GraphEvent) ProtoMessage() {}
+
+func (x *GraphEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_houyi_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GraphEvent.ProtoReflect.Descriptor instead.
+func (*GraphEvent) Descriptor() ([]byte, []int) {
+	return file_houyi_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GraphEvent) GetType() GraphEvent_EventType {
+	if x != nil {
+		return x.Type
+	}
+	return GraphEvent_OPERATION_ADDED
+}
+
+func (x *GraphEvent) GetOperation() *Operation {
+	if x != nil {
+		return x.Operation
+	}
+	return nil
+}
+
+func (x *GraphEvent) GetRelation() *Relation {
+	if x != nil {
+		return x.Relation
+	}
+	return nil
+}
+
+func (x *GraphEvent) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+type EvaluatingTag struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// tagName is the key of a tag of spans, or one of "duration" (in microseconds), "warnings", "log.<key>" for fields
+	// of logs and "process.<key>" for tags of processes.
+	TagName       string                      `protobuf:"bytes,1,opt,name=tagName,proto3" json:"tagName,omitempty"`
+	OperationType EvaluatingTag_OperationType `protobuf:"varint,2,opt,name=operationType,proto3,enum=houyi.EvaluatingTag_OperationType" json:"operationType,omitempty"`
+	ValueType     EvaluatingTag_ValueType     `protobuf:"varint,3,opt,name=valueType,proto3,enum=houyi.EvaluatingTag_ValueType" json:"valueType,omitempty"`
+	// Types that are assignable to Value:
+	//	*EvaluatingTag_IntegerVal
+	//	*EvaluatingTag_FloatVal
+	//	*EvaluatingTag_BooleanVal
+	//	*EvaluatingTag_StringVal
+	Value isEvaluatingTag_Value `protobuf_oneof:"value"`
+	// weight is the severity of a span matching this tag and it is used as the weight of promotion. 0 is treated as 1.
+	Weight int32 `protobuf:"varint,8,opt,name=weight,proto3" json:"weight,omitempty"`
+	// service and operation limit this tag to spans of matching services and operations. They are glob patterns
+	// where '*' matches any sequence of characters and '?' matches any single character. Empty matches everything.
+	Service   string `protobuf:"bytes,9,opt,name=service,proto3" json:"service,omitempty"`
+	Operation string `protobuf:"bytes,10,opt,name=operation,proto3" json:"operation,omitempty"`
+	// dryRun counts spans matching this tag without promoting their operations.
+	DryRun bool `protobuf:"varint,11,opt,name=dryRun,proto3" json:"dryRun,omitempty"`
+}
+
+func (x *EvaluatingTag) Reset() {
+	*x = EvaluatingTag{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_houyi_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EvaluatingTag) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EvaluatingTag) ProtoMessage() {}
+
+func (x *EvaluatingTag) ProtoReflect() protoreflect.Message {
+	mi := &file_houyi_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EvaluatingTag.ProtoReflect.Descriptor instead.
+func (*EvaluatingTag) Descriptor() ([]byte, []int) {
+	return file_houyi_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *EvaluatingTag) GetTagName() string {
+	if x != nil {
+		return x.TagName
+	}
+	return ""
+}
+
+func (x *EvaluatingTag) GetOperationType() EvaluatingTag_OperationType {
+	if x != nil {
+		return x.OperationType
+	}
+	return EvaluatingTag_EQUAL_TO
+}
+
+func (x *EvaluatingTag) GetValueType() EvaluatingTag_ValueType {
+	if x != nil {
+		return x.ValueType
+	}
+	return EvaluatingTag_INTEGER
+}
+
+func (m *EvaluatingTag) GetValue() isEvaluatingTag_Value {
+	if m != nil {
+		return m.Value
+	}
+	return nil
+}
+
+func (x *EvaluatingTag) GetIntegerVal() int64 {
+	if x, ok := x.GetValue().(*EvaluatingTag_IntegerVal); ok {
+		return x.IntegerVal
+	}
+	return 0
+}
+
+func (x *EvaluatingTag) GetFloatVal() float64 {
+	if x, ok := x.GetValue().(*EvaluatingTag_FloatVal); ok {
+		return x.FloatVal
+	}
+	return 0
+}
+
+func (x *EvaluatingTag) GetBooleanVal() bool {
+	if x, ok := x.GetValue().(*EvaluatingTag_BooleanVal); ok {
+		return x.BooleanVal
+	}
+	return false
+}
+
+func (x *EvaluatingTag) GetStringVal() string {
+	if x, ok := x.GetValue().(*EvaluatingTag_StringVal); ok {
+		return x.StringVal
+	}
+	return ""
+}
+
+func (x *EvaluatingTag) GetWeight() int32 {
+	if x != nil {
+		return x.Weight
+	}
+	return 0
+}
+
+func (x *EvaluatingTag) GetService() string {
+	if x != nil {
+		return x.Service
+	}
+	return ""
+}
+
+func (x *EvaluatingTag) GetOperation() string {
+	if x != nil {
+		return x.Operation
+	}
+	return ""
+}
+
+func (x *EvaluatingTag) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+type isEvaluatingTag_Value interface {
+	isEvaluatingTag_Value()
+}
+
+type EvaluatingTag_IntegerVal struct {
+	IntegerVal int64 `protobuf:"varint,4,opt,name=integerVal,proto3,oneof"`
+}
+
+type EvaluatingTag_FloatVal struct {
+	FloatVal float64 `protobuf:"fixed64,5,opt,name=floatVal,proto3,oneof"`
+}
+
+type EvaluatingTag_BooleanVal struct {
+	BooleanVal bool `protobuf:"varint,6,opt,name=booleanVal,proto3,oneof"`
+}
+
+type EvaluatingTag_StringVal struct {
+	StringVal string `protobuf:"bytes,7,opt,name=stringVal,proto3,oneof"`
+}
+
+func (*EvaluatingTag_IntegerVal) isEvaluatingTag_Value() {}
+
+func (*EvaluatingTag_FloatVal) isEvaluatingTag_Value() {}
+
+func (*EvaluatingTag_BooleanVal) isEvaluatingTag_Value() {}
+
+func (*EvaluatingTag_StringVal) isEvaluatingTag_Value() {}
+
+// EvaluatingRule is a boolean expression on tags of spans, e.g. `error == true && http.status_code >= 500`.
+type EvaluatingRule struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name       string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Expression string `protobuf:"bytes,2,opt,name=expression,proto3" json:"expression,omitempty"`
+	// weight is the severity of a span matching this rule and it is used as the weight of promotion. 0 is treated as 1.
+	Weight int32 `protobuf:"varint,3,opt,name=weight,proto3" json:"weight,omitempty"`
+	// service and operation limit this rule to spans of matching services and operations, as in EvaluatingTag.
+	Service   string `protobuf:"bytes,4,opt,name=service,proto3" json:"service,omitempty"`
+	Operation string `protobuf:"bytes,5,opt,name=operation,proto3" json:"operation,omitempty"`
+	// dryRun counts spans matching this rule without promoting their operations.
+	DryRun bool `protobuf:"varint,6,opt,name=dryRun,proto3" json:"dryRun,omitempty"`
+}
+
+func (x *EvaluatingRule) Reset() {
+	*x = EvaluatingRule{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_houyi_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EvaluatingRule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EvaluatingRule) ProtoMessage() {}
+
+func (x *EvaluatingRule) ProtoReflect() protoreflect.Message {
+	mi := &file_houyi_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EvaluatingRule.ProtoReflect.Descriptor instead.
+func (*EvaluatingRule) Descriptor() ([]byte, []int) {
+	return file_houyi_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *EvaluatingRule) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *EvaluatingRule) GetExpression() string {
+	if x != nil {
+		return x.Expression
+	}
+	return ""
+}
+
+func (x *EvaluatingRule) GetWeight() int32 {
+	if x != nil {
+		return x.Weight
+	}
+	return 0
+}
+
+func (x *EvaluatingRule) GetService() string {
+	if x != nil {
+		return x.Service
+	}
+	return ""
+}
+
+func (x *EvaluatingRule) GetOperation() string {
+	if x != nil {
+		return x.Operation
+	}
+	return ""
+}
+
+func (x *EvaluatingRule) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+// EvaluatorMatch is the number of spans matching an evaluating tag or rule since it was added.
+type EvaluatorMatch struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// name describes the evaluating tag or rule.
+	Name   string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	DryRun bool   `protobuf:"varint,2,opt,name=dryRun,proto3" json:"dryRun,omitempty"`
+	Count  int64  `protobuf:"varint,3,opt,name=count,proto3" json:"count,omitempty"`
+}
+
+func (x *EvaluatorMatch) Reset() {
+	*x = EvaluatorMatch{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_houyi_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EvaluatorMatch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EvaluatorMatch) ProtoMessage() {}
+
+func (x *EvaluatorMatch) ProtoReflect() protoreflect.Message {
+	mi := &file_houyi_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EvaluatorMatch.ProtoReflect.Descriptor instead.
+func (*EvaluatorMatch) Descriptor() ([]byte, []int) {
+	return file_houyi_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *EvaluatorMatch) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *EvaluatorMatch) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+func (x *EvaluatorMatch) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+var File_houyi_proto protoreflect.FileDescriptor
+
+var file_houyi_proto_rawDesc = []byte{
+	0x0a, 0x0b, 0x68, 0x6f, 0x75, 0x79, 0x69, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x05, 0x68,
+	0x6f, 0x75, 0x79, 0x69, 0x22, 0x43, 0x0a, 0x09, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x07, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x6f,
+	0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09,
+	0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x52, 0x0a, 0x08, 0x52, 0x65, 0x6c,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x24, 0x0a, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x68, 0x6f, 0x75, 0x79, 0x69, 0x2e, 0x4f, 0x70, 0x65, 0x72,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x12, 0x20, 0x0a, 0x02, 0x74,
+	0x6f, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x68, 0x6f, 0x75, 0x79, 0x69, 0x2e,
+	0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x02, 0x74, 0x6f, 0x22, 0xce, 0x01,
+	0x0a, 0x0d, 0x52, 0x65, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x73, 0x12,
+	0x2b, 0x0a, 0x08, 0x72, 0x65, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x0f, 0x2e, 0x68, 0x6f, 0x75, 0x79, 0x69, 0x2e, 0x52, 0x65, 0x6c, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x52, 0x08, 0x72, 0x65, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x14, 0x0a, 0x05,
+	0x63, 0x61, 0x6c, 0x6c, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x63, 0x61, 0x6c,
+	0x6c, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x06, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x12, 0x28, 0x0a, 0x0f, 0x64, 0x75,
+	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x42, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x73, 0x18, 0x04, 0x20,
+	0x03, 0x28, 0x03, 0x52, 0x0f, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x42, 0x75, 0x63,
+	0x6b, 0x65, 0x74, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x66, 0x69, 0x72, 0x73, 0x74, 0x53, 0x65, 0x65,
+	0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x66, 0x69, 0x72, 0x73, 0x74, 0x53, 0x65,
+	0x65, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x6c, 0x61, 0x73, 0x74, 0x53, 0x65, 0x65, 0x6e, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x6c, 0x61, 0x73, 0x74, 0x53, 0x65, 0x65, 0x6e, 0x22, 0x39,
+	0x0a, 0x03, 0x44, 0x6f, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x72, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x72, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x12,
+	0x18, 0x0a, 0x07, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x07, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x65, 0x72, 0x22, 0xac, 0x01, 0x0a, 0x0d, 0x43, 0x61,
+	0x75, 0x73, 0x61, 0x6c, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74, 0x12, 0x3e, 0x0a, 0x08, 0x76,
+	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x22, 0x2e,
+	0x68, 0x6f, 0x75, 0x79, 0x69, 0x2e, 0x43, 0x61, 0x75, 0x73, 0x61, 0x6c, 0x43, 0x6f, 0x6e, 0x74,
+	0x65, 0x78, 0x74, 0x2e, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x45, 0x6e, 0x74, 0x72,
+	0x79, 0x52, 0x08, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x1e, 0x0a, 0x04, 0x64,
+	0x6f, 0x74, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x68, 0x6f, 0x75, 0x79,
+	0x69, 0x2e, 0x44, 0x6f, 0x74, 0x52, 0x04, 0x64, 0x6f, 0x74, 0x73, 0x1a, 0x3b, 0x0a, 0x0d, 0x56,
+	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03,
+	0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14,
+	0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x64, 0x0a, 0x12, 0x56, 0x65, 0x72, 0x73,
+	0x69, 0x6f, 0x6e, 0x65, 0x64, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x2e,
+	0x0a, 0x09, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x10, 0x2e, 0x68, 0x6f, 0x75, 0x79, 0x69, 0x2e, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x52, 0x09, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1e,
+	0x0a, 0x04, 0x64, 0x6f, 0x74, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x68,
+	0x6f, 0x75, 0x79, 0x69, 0x2e, 0x44, 0x6f, 0x74, 0x52, 0x04, 0x64, 0x6f, 0x74, 0x73, 0x22, 0x60,
+	0x0a, 0x11, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x65, 0x64, 0x52, 0x65, 0x6c, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x12, 0x2b, 0x0a, 0x08, 0x72, 0x65, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x68, 0x6f, 0x75, 0x79, 0x69, 0x2e, 0x52, 0x65,
+	0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x08, 0x72, 0x65, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x12, 0x1e, 0x0a, 0x04, 0x64, 0x6f, 0x74, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0a,
+	0x2e, 0x68, 0x6f, 0x75, 0x79, 0x69, 0x2e, 0x44, 0x6f, 0x74, 0x52, 0x04, 0x64, 0x6f, 0x74, 0x73,
+	0x22, 0xaf, 0x01, 0x0a, 0x0a, 0x47, 0x72, 0x61, 0x70, 0x68, 0x44, 0x65, 0x6c, 0x74, 0x61, 0x12,
+	0x39, 0x0a, 0x0a, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x68, 0x6f, 0x75, 0x79, 0x69, 0x2e, 0x56, 0x65, 0x72, 0x73,
+	0x69, 0x6f, 0x6e, 0x65, 0x64, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0a,
+	0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x36, 0x0a, 0x09, 0x72, 0x65,
+	0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e,
+	0x68, 0x6f, 0x75, 0x79, 0x69, 0x2e, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x65, 0x64, 0x52,
+	0x65, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x09, 0x72, 0x65, 0x6c, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x73, 0x12, 0x2e, 0x0a, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x68, 0x6f, 0x75, 0x79, 0x69, 0x2e, 0x43, 0x61, 0x75, 0x73,
+	0x61, 0x6c, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74, 0x52, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65,
+	0x78, 0x74, 0x22, 0xc4, 0x02, 0x0a, 0x0a, 0x47, 0x72, 0x61, 0x70, 0x68, 0x45, 0x76, 0x65, 0x6e,
+	0x74, 0x12, 0x2f, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32,
+	0x1b, 0x2e, 0x68, 0x6f, 0x75, 0x79, 0x69, 0x2e, 0x47, 0x72, 0x61, 0x70, 0x68, 0x45, 0x76, 0x65,
+	0x6e, 0x74, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74, 0x79,
+	0x70, 0x65, 0x12, 0x2e, 0x0a, 0x09, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x68, 0x6f, 0x75, 0x79, 0x69, 0x2e, 0x4f, 0x70,
+	0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x09, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x12, 0x2b, 0x0a, 0x08, 0x72, 0x65, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x68, 0x6f, 0x75, 0x79, 0x69, 0x2e, 0x52, 0x65, 0x6c,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x08, 0x72, 0x65, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12,
+	0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x22, 0x89, 0x01,
+	0x0a, 0x09, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x13, 0x0a, 0x0f, 0x4f,
+	0x50, 0x45, 0x52, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x41, 0x44, 0x44, 0x45, 0x44, 0x10, 0x00,
+	0x12, 0x15, 0x0a, 0x11, 0x4f, 0x50, 0x45, 0x52, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x52, 0x45,
+	0x4d, 0x4f, 0x56, 0x45, 0x44, 0x10, 0x01, 0x12, 0x12, 0x0a, 0x0e, 0x52, 0x45, 0x4c, 0x41, 0x54,
+	0x49, 0x4f, 0x4e, 0x5f, 0x41, 0x44, 0x44, 0x45, 0x44, 0x10, 0x02, 0x12, 0x14, 0x0a, 0x10, 0x52,
+	0x45, 0x4c, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x52, 0x45, 0x4d, 0x4f, 0x56, 0x45, 0x44, 0x10,
+	0x03, 0x12, 0x11, 0x0a, 0x0d, 0x49, 0x4e, 0x47, 0x52, 0x45, 0x53, 0x53, 0x5f, 0x41, 0x44, 0x44,
+	0x45, 0x44, 0x10, 0x04, 0x12, 0x13, 0x0a, 0x0f, 0x49, 0x4e, 0x47, 0x52, 0x45, 0x53, 0x53, 0x5f,
+	0x52, 0x45, 0x4d, 0x4f, 0x56, 0x45, 0x44, 0x10, 0x05, 0x22, 0xee, 0x04, 0x0a, 0x0d, 0x45, 0x76,
+	0x61, 0x6c, 0x75, 0x61, 0x74, 0x69, 0x6e, 0x67, 0x54, 0x61, 0x67, 0x12, 0x18, 0x0a, 0x07, 0x74,
+	0x61, 0x67, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x74, 0x61,
+	0x67, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x48, 0x0a, 0x0d, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x22, 0x2e, 0x68,
+	0x6f, 0x75, 0x79, 0x69, 0x2e, 0x45, 0x76, 0x61, 0x6c, 0x75, 0x61, 0x74, 0x69, 0x6e, 0x67, 0x54,
+	0x61, 0x67, 0x2e, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65,
+	0x52, 0x0d, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x12,
+	0x3c, 0x0a, 0x09, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x54, 0x79, 0x70, 0x65, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x0e, 0x32, 0x1e, 0x2e, 0x68, 0x6f, 0x75, 0x79, 0x69, 0x2e, 0x45, 0x76, 0x61, 0x6c, 0x75,
+	0x61, 0x74, 0x69, 0x6e, 0x67, 0x54, 0x61, 0x67, 0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x54, 0x79,
+	0x70, 0x65, 0x52, 0x09, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x54, 0x79, 0x70, 0x65, 0x12, 0x20, 0x0a,
+	0x0a, 0x69, 0x6e, 0x74, 0x65, 0x67, 0x65, 0x72, 0x56, 0x61, 0x6c, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x03, 0x48, 0x00, 0x52, 0x0a, 0x69, 0x6e, 0x74, 0x65, 0x67, 0x65, 0x72, 0x56, 0x61, 0x6c, 0x12,
+	0x1c, 0x0a, 0x08, 0x66, 0x6c, 0x6f, 0x61, 0x74, 0x56, 0x61, 0x6c, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x01, 0x48, 0x00, 0x52, 0x08, 0x66, 0x6c, 0x6f, 0x61, 0x74, 0x56, 0x61, 0x6c, 0x12, 0x20, 0x0a,
+	0x0a, 0x62, 0x6f, 0x6f, 0x6c, 0x65, 0x61, 0x6e, 0x56, 0x61, 0x6c, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x08, 0x48, 0x00, 0x52, 0x0a, 0x62, 0x6f, 0x6f, 0x6c, 0x65, 0x61, 0x6e, 0x56, 0x61, 0x6c, 0x12,
+	0x1e, 0x0a, 0x09, 0x73, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x56, 0x61, 0x6c, 0x18, 0x07, 0x20, 0x01,
+	0x28, 0x09, 0x48, 0x00, 0x52, 0x09, 0x73, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x56, 0x61, 0x6c, 0x12,
+	0x16, 0x0a, 0x06, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x06, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x12, 0x1c, 0x0a, 0x09, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x0a,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12,
+	0x16, 0x0a, 0x06, 0x64, 0x72, 0x79, 0x52, 0x75, 0x6e, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x06, 0x64, 0x72, 0x79, 0x52, 0x75, 0x6e, 0x22, 0x3c, 0x0a, 0x09, 0x56, 0x61, 0x6c, 0x75, 0x65,
+	0x54, 0x79, 0x70, 0x65, 0x12, 0x0b, 0x0a, 0x07, 0x49, 0x4e, 0x54, 0x45, 0x47, 0x45, 0x52, 0x10,
+	0x00, 0x12, 0x09, 0x0a, 0x05, 0x46, 0x4c, 0x4f, 0x41, 0x54, 0x10, 0x01, 0x12, 0x0b, 0x0a, 0x07,
+	0x42, 0x4f, 0x4f, 0x4c, 0x45, 0x41, 0x4e, 0x10, 0x02, 0x12, 0x0a, 0x0a, 0x06, 0x53, 0x54, 0x52,
+	0x49, 0x4e, 0x47, 0x10, 0x04, 0x22, 0x89, 0x01, 0x0a, 0x0d, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x12, 0x0c, 0x0a, 0x08, 0x45, 0x51, 0x55, 0x41, 0x4c,
+	0x5f, 0x54, 0x4f, 0x10, 0x00, 0x12, 0x10, 0x0a, 0x0c, 0x4e, 0x4f, 0x54, 0x5f, 0x45, 0x51, 0x55,
+	0x41, 0x4c, 0x5f, 0x54, 0x4f, 0x10, 0x01, 0x12, 0x10, 0x0a, 0x0c, 0x47, 0x52, 0x45, 0x41, 0x54,
+	0x45, 0x52, 0x5f, 0x54, 0x48, 0x41, 0x4e, 0x10, 0x02, 0x12, 0x1c, 0x0a, 0x18, 0x47, 0x52, 0x45,
+	0x41, 0x54, 0x45, 0x52, 0x5f, 0x54, 0x48, 0x41, 0x4e, 0x5f, 0x4f, 0x52, 0x5f, 0x45, 0x51, 0x55,
+	0x41, 0x4c, 0x5f, 0x54, 0x4f, 0x10, 0x03, 0x12, 0x0d, 0x0a, 0x09, 0x4c, 0x45, 0x53, 0x53, 0x5f,
+	0x54, 0x48, 0x41, 0x4e, 0x10, 0x04, 0x12, 0x19, 0x0a, 0x15, 0x4c, 0x45, 0x53, 0x53, 0x5f, 0x54,
+	0x48, 0x41, 0x4e, 0x5f, 0x4f, 0x52, 0x5f, 0x45, 0x51, 0x55, 0x41, 0x4c, 0x5f, 0x54, 0x4f, 0x10,
+	0x05, 0x42, 0x07, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x22, 0xac, 0x01, 0x0a, 0x0e, 0x45,
+	0x76, 0x61, 0x6c, 0x75, 0x61, 0x74, 0x69, 0x6e, 0x67, 0x52, 0x75, 0x6c, 0x65, 0x12, 0x12, 0x0a,
+	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d,
+	0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x65, 0x78, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x65, 0x78, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f,
+	0x6e, 0x12, 0x16, 0x0a, 0x06, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x06, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x65, 0x72,
+	0x76, 0x69, 0x63, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x73, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x12, 0x16, 0x0a, 0x06, 0x64, 0x72, 0x79, 0x52, 0x75, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x06, 0x64, 0x72, 0x79, 0x52, 0x75, 0x6e, 0x22, 0x52, 0x0a, 0x0e, 0x45, 0x76, 0x61,
+	0x6c, 0x75, 0x61, 0x74, 0x6f, 0x72, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x12, 0x12, 0x0a, 0x04, 0x6e,
+	0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12,
+	0x16, 0x0a, 0x06, 0x64, 0x72, 0x79, 0x52, 0x75, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x06, 0x64, 0x72, 0x79, 0x52, 0x75, 0x6e, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x42, 0x2b, 0x5a,
+	0x29, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x68, 0x6f, 0x75, 0x79,
+	0x69, 0x2d, 0x74, 0x72, 0x61, 0x63, 0x69, 0x6e, 0x67, 0x2f, 0x68, 0x6f, 0x75, 0x79, 0x69, 0x2f,
+	0x69, 0x64, 0x6c, 0x2f, 0x61, 0x70, 0x69, 0x5f, 0x76, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x33,
+}
+
+var (
+	file_houyi_proto_rawDescOnce sync.Once
+	file_houyi_proto_rawDescData = file_houyi_proto_rawDesc
+)
+
+func file_houyi_proto_rawDescGZIP() []byte {
+	file_houyi_proto_rawDescOnce.Do(func() {
+		file_houyi_proto_rawDescData = protoimpl.X.CompressGZIP(file_houyi_proto_rawDescData)
+	})
+	return file_houyi_proto_rawDescData
+}
+
+var file_houyi_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
+var file_houyi_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
+var file_houyi_proto_goTypes = []interface{}{
+	(GraphEvent_EventType)(0),        // 0: houyi.GraphEvent.EventType
+	(EvaluatingTag_ValueType)(0),     // 1: houyi.EvaluatingTag.ValueType
+	(EvaluatingTag_OperationType)(0), // 2: houyi.EvaluatingTag.OperationType
+	(*Operation)(nil),                // 3: houyi.Operation
+	(*Relation)(nil),                 // 4: houyi.Relation
+	(*RelationStats)(nil),            // 5: houyi.RelationStats
+	(*Dot)(nil),                      // 6: houyi.Dot
+	(*CausalContext)(nil),            // 7: houyi.CausalContext
+	(*VersionedOperation)(nil),       // 8: houyi.VersionedOperation
+	(*VersionedRelation)(nil),        // 9: houyi.VersionedRelation
+	(*GraphDelta)(nil),               // 10: houyi.GraphDelta
+	(*GraphEvent)(nil),               // 11: houyi.GraphEvent
+	(*EvaluatingTag)(nil),            // 12: houyi.EvaluatingTag
+	(*EvaluatingRule)(nil),           // 13: houyi.EvaluatingRule
+	(*EvaluatorMatch)(nil),           // 14: houyi.EvaluatorMatch
+	nil,                              // 15: houyi.CausalContext.VersionsEntry
+}
+var file_houyi_proto_depIdxs = []int32{
+	3,  // 0: houyi.Relation.from:type_name -> houyi.Operation
+	3,  // 1: houyi.Relation.to:type_name -> houyi.Operation
+	4,  // 2: houyi.RelationStats.relation:type_name -> houyi.Relation
+	15, // 3: houyi.CausalContext.versions:type_name -> houyi.CausalContext.VersionsEntry
+	6,  // 4: houyi.CausalContext.dots:type_name -> houyi.Dot
+	3,  // 5: houyi.VersionedOperation.operation:type_name -> houyi.Operation
+	6,  // 6: houyi.VersionedOperation.dots:type_name -> houyi.Dot
+	4,  // 7: houyi.VersionedRelation.relation:type_name -> houyi.Relation
+	6,  // 8: houyi.VersionedRelation.dots:type_name -> houyi.Dot
+	8,  // 9: houyi.GraphDelta.operations:type_name -> houyi.VersionedOperation
+	9,  // 10: houyi.GraphDelta.relations:type_name -> houyi.VersionedRelation
+	7,  // 11: houyi.GraphDelta.context:type_name -> houyi.CausalContext
+	0,  // 12: houyi.GraphEvent.type:type_name -> houyi.GraphEvent.EventType
+	3,  // 13: houyi.GraphEvent.operation:type_name -> houyi.Operation
+	4,  // 14: houyi.GraphEvent.relation:type_name -> houyi.Relation
+	2,  // 15: houyi.EvaluatingTag.operationType:type_name -> houyi.EvaluatingTag.OperationType
+	1,  // 16: houyi.EvaluatingTag.valueType:type_name -> houyi.EvaluatingTag.ValueType
+	17, // [17:17] is the sub-list for method output_type
+	17, // [17:17] is the sub-list for method input_type
+	17, // [17:17] is the sub-list for extension type_name
+	17, // [17:17] is the sub-list for extension extendee
+	0,  // [0:17] is the sub-list for field type_name
+}
+
+func init() { file_houyi_proto_init() }
+func file_houyi_proto_init() {
+	if File_houyi_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_houyi_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Operation); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_houyi_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Relation); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_houyi_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RelationStats); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_houyi_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Dot); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_houyi_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CausalContext); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_houyi_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*VersionedOperation); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_houyi_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*VersionedRelation); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_houyi_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GraphDelta); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_houyi_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GraphEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_houyi_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EvaluatingTag); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_houyi_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EvaluatingRule); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_houyi_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EvaluatorMatch); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -519,7 +1453,7 @@ func file_houyi_proto_init() {
 			}
 		}
 	}
-	file_houyi_proto_msgTypes[2].OneofWrappers = []interface{}{
+	file_houyi_proto_msgTypes[9].OneofWrappers = []interface{}{
 		(*EvaluatingTag_IntegerVal)(nil),
 		(*EvaluatingTag_FloatVal)(nil),
 		(*EvaluatingTag_BooleanVal)(nil),
@@ -530,8 +1464,8 @@ func file_houyi_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_houyi_proto_rawDesc,
-			NumEnums:      2,
-			NumMessages:   3,
+			NumEnums:      3,
+			NumMessages:   13,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -18,8 +18,9 @@ import (
 	"github.com/houyi-tracing/houyi/idl/api_v1"
 	"github.com/jaegertracing/jaeger/model"
 	"github.com/spf13/cast"
+	"github.com/uber/jaeger-lib/metrics"
 	"go.uber.org/zap"
-	"log"
+	"sort"
 	"sync"
 )
 
@@ -29,65 +30,103 @@ type spanEvaluator struct {
 
 	logger *zap.Logger
 	tags   *api_v1.EvaluatingTags
-	eqTags map[string]interface{} // equal to
-	neTags map[string]interface{} // not equal to
-	ltTags map[string]interface{} // less than
-	gtTags map[string]interface{} // greater than
-	leTags map[string]interface{} // less than or equal to
-	geTags map[string]interface{} // greater than or equal to
+	eqTags map[string][]*condition // equal to
+	neTags map[string][]*condition // not equal to
+	ltTags map[string][]*condition // less than
+	gtTags map[string][]*condition // greater than
+	leTags map[string][]*condition // less than or equal to
+	geTags map[string][]*condition // greater than or equal to
+	rules  []*compiledRule
+
+	metricsFactory metrics.Factory
+	counters       map[matchKey]*matchCounter
+}
+
+// compiledRule is an evaluating rule with its compiled expression.
+type compiledRule struct {
+	rule    *api_v1.EvaluatingRule
+	match   Predicate
+	weight  int
+	scope   *scope
+	counter *matchCounter
+}
+
+// condition is the value to compare with and the weight of promotion if it is matched by a span in scope.
+type condition struct {
+	val     interface{}
+	weight  int
+	scope   *scope
+	counter *matchCounter
 }
 
-func NewEvaluator(logger *zap.Logger) Evaluator {
+// NewEvaluator returns an evaluator which reports numbers of spans matching evaluating tags and rules to
+// metricsFactory. metricsFactory can be nil if these metrics are not needed.
+func NewEvaluator(logger *zap.Logger, metricsFactory metrics.Factory) Evaluator {
+	if metricsFactory == nil {
+		metricsFactory = metrics.NullFactory
+	}
 	return &spanEvaluator{
-		logger: logger,
+		logger:         logger,
+		metricsFactory: metricsFactory.Namespace(metrics.NSOptions{Name: "evaluator"}),
+		counters:       make(map[matchKey]*matchCounter),
 		tags: &api_v1.EvaluatingTags{
 			Tags: []*api_v1.EvaluatingTag{},
 		},
-		eqTags: make(map[string]interface{}),
-		neTags: make(map[string]interface{}),
-		ltTags: make(map[string]interface{}),
-		gtTags: make(map[string]interface{}),
-		leTags: make(map[string]interface{}),
-		geTags: make(map[string]interface{}),
+		eqTags: make(map[string][]*condition),
+		neTags: make(map[string][]*condition),
+		ltTags: make(map[string][]*condition),
+		gtTags: make(map[string][]*condition),
+		leTags: make(map[string][]*condition),
+		geTags: make(map[string][]*condition),
 	}
 }
 
 func (f *spanEvaluator) Evaluate(span *model.Span) bool {
+	return f.Weigh(span) > 0
+}
+
+func (f *spanEvaluator) Weigh(span *model.Span) int {
 	f.RLock()
 	defer f.RUnlock()
 
-	for _, t := range span.GetTags() {
+	weight := 0
+	var matches spanMatches
+	eachField(span, func(t *model.KeyValue) bool {
 		switch t.GetVType() {
 		case model.ValueType_BOOL:
-			if f.checkBool(t.GetKey(), t.GetVBool()) {
-				return true
-			}
+			weight = max(weight, f.checkBool(span, &matches, t.GetKey(), t.GetVBool()))
 		case model.ValueType_FLOAT64:
-			if f.checkFloat64(t.GetKey(), t.GetVFloat64()) {
-				return true
-			}
+			weight = max(weight, f.checkFloat64(span, &matches, t.GetKey(), t.GetVFloat64()))
 		case model.ValueType_STRING:
-			if f.checkString(t.GetKey(), t.GetVStr()) {
-				return true
-			}
+			weight = max(weight, f.checkString(span, &matches, t.GetKey(), t.GetVStr()))
 		case model.ValueType_INT64:
-			if f.checkInt64(t.GetKey(), t.GetVInt64()) {
-				return true
-			}
+			weight = max(weight, f.checkInt64(span, &matches, t.GetKey(), t.GetVInt64()))
 		default:
-			log.Println("unsupported Tag type:", t.GetVType())
+			f.logger.Debug("unsupported tag type",
+				zap.String("key", t.GetKey()),
+				zap.Stringer("type", t.GetVType()))
+		}
+		return true
+	})
+	for _, r := range f.rules {
+		if r.scope.contains(span) && r.match(span) {
+			weight = max(weight, matches.add(r.counter, r.weight))
 		}
 	}
-	return false
+	matches.count()
+	return weight
 }
 
 func (f *spanEvaluator) Update(tags *api_v1.EvaluatingTags) {
 	f.Lock()
 	defer f.Unlock()
 
+	previous := f.counters
+	f.counters = make(map[matchKey]*matchCounter)
 	f.clear()
 	f.tags = tags
-	f.parseTags(tags)
+	f.parseTags(tags, previous)
+	f.parseRules(tags, previous)
 }
 
 func (f *spanEvaluator) Get() *api_v1.EvaluatingTags {
@@ -97,130 +136,224 @@ func (f *spanEvaluator) Get() *api_v1.EvaluatingTags {
 	return f.tags
 }
 
-func (f *spanEvaluator) parseTags(tags *api_v1.EvaluatingTags) {
+func (f *spanEvaluator) Matches() []*api_v1.EvaluatorMatch {
+	f.RLock()
+	defer f.RUnlock()
+
+	ret := make([]*api_v1.EvaluatorMatch, 0, len(f.counters))
+	for _, c := range f.counters {
+		ret = append(ret, c.toProto())
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].GetName() < ret[j].GetName() ||
+			(ret[i].GetName() == ret[j].GetName() && !ret[i].GetDryRun() && ret[j].GetDryRun())
+	})
+	return ret
+}
+
+// counter returns the match counter of an evaluating tag or rule, which is kept from previous counters if possible.
+func (f *spanEvaluator) counter(previous map[matchKey]*matchCounter, name string, dryRun bool) *matchCounter {
+	key := matchKey{name: name, dryRun: dryRun}
+	c, has := f.counters[key]
+	if !has {
+		c, has = previous[key]
+	}
+	if !has {
+		c = newMatchCounter(f.metricsFactory, name, dryRun)
+	}
+	f.counters[key] = c
+	return c
+}
+
+func (f *spanEvaluator) parseTags(tags *api_v1.EvaluatingTags, previous map[matchKey]*matchCounter) {
 	for _, tag := range tags.Tags {
+		cond := newCondition(tag)
+		cond.counter = f.counter(previous, tagName(tag), tag.GetDryRun())
 		switch tag.OperationType {
 		case api_v1.EvaluatingTag_EQUAL_TO:
-			f.eqTags[tag.TagName] = toActualType(tag)
+			f.eqTags[tag.TagName] = append(f.eqTags[tag.TagName], cond)
 		case api_v1.EvaluatingTag_NOT_EQUAL_TO:
-			f.neTags[tag.TagName] = toActualType(tag)
+			f.neTags[tag.TagName] = append(f.neTags[tag.TagName], cond)
 		case api_v1.EvaluatingTag_GREATER_THAN:
-			f.gtTags[tag.TagName] = toActualType(tag)
+			f.gtTags[tag.TagName] = append(f.gtTags[tag.TagName], cond)
 		case api_v1.EvaluatingTag_GREATER_THAN_OR_EQUAL_TO:
-			f.geTags[tag.TagName] = toActualType(tag)
+			f.geTags[tag.TagName] = append(f.geTags[tag.TagName], cond)
 		case api_v1.EvaluatingTag_LESS_THAN:
-			f.ltTags[tag.TagName] = toActualType(tag)
+			f.ltTags[tag.TagName] = append(f.ltTags[tag.TagName], cond)
 		case api_v1.EvaluatingTag_LESS_THAN_OR_EQUAL_TO:
-			f.leTags[tag.TagName] = toActualType(tag)
+			f.leTags[tag.TagName] = append(f.leTags[tag.TagName], cond)
 		}
 	}
 }
 
-// clear removes all tags.
+// parseRules compiles the rules of tags. Rules with invalid expressions are ignored.
+func (f *spanEvaluator) parseRules(tags *api_v1.EvaluatingTags, previous map[matchKey]*matchCounter) {
+	valid := make([]*api_v1.EvaluatingRule, 0, len(tags.GetRules()))
+	for _, rule := range tags.GetRules() {
+		match, err := Compile(rule.GetExpression())
+		if err != nil {
+			f.logger.Error("ignored invalid evaluating rule",
+				zap.String("name", rule.GetName()),
+				zap.String("expression", rule.GetExpression()),
+				zap.Error(err))
+			continue
+		}
+		weight := int(rule.GetWeight())
+		if weight < 1 {
+			weight = 1
+		}
+		f.rules = append(f.rules, &compiledRule{
+			rule:    rule,
+			match:   match,
+			weight:  weight,
+			scope:   newScope(rule.GetService(), rule.GetOperation()),
+			counter: f.counter(previous, ruleName(rule), rule.GetDryRun()),
+		})
+		valid = append(valid, rule)
+	}
+	f.tags = &api_v1.EvaluatingTags{Tags: tags.GetTags(), Rules: valid}
+}
+
+// clear removes all tags and rules.
 func (f *spanEvaluator) clear() {
 	f.tags = &api_v1.EvaluatingTags{Tags: []*api_v1.EvaluatingTag{}}
-	f.eqTags = make(map[string]interface{})
-	f.neTags = make(map[string]interface{})
-	f.gtTags = make(map[string]interface{})
-	f.geTags = make(map[string]interface{})
-	f.ltTags = make(map[string]interface{})
-	f.leTags = make(map[string]interface{})
+	f.eqTags = make(map[string][]*condition)
+	f.neTags = make(map[string][]*condition)
+	f.gtTags = make(map[string][]*condition)
+	f.geTags = make(map[string][]*condition)
+	f.ltTags = make(map[string][]*condition)
+	f.leTags = make(map[string][]*condition)
+	f.rules = nil
 }
 
-func (f *spanEvaluator) checkBool(tKey string, tVal bool) bool {
-	if cmp, has := f.eqTags[tKey]; has {
-		if cVal, err := cast.ToBoolE(cmp); err == nil && cVal == tVal {
-			return true
+func (f *spanEvaluator) checkBool(span *model.Span, matches *spanMatches, tKey string, tVal bool) int {
+	weight := 0
+	for _, cmp := range f.eqTags[tKey] {
+		if cVal, err := cast.ToBoolE(cmp.val); err == nil && cVal == tVal && cmp.scope.contains(span) {
+			weight = max(weight, matches.add(cmp.counter, cmp.weight))
 		}
 	}
-	if cmp, has := f.neTags[tKey]; has {
-		if cVal, err := cast.ToBoolE(cmp); err == nil && cVal != tVal {
-			return true
+	for _, cmp := range f.neTags[tKey] {
+		if cVal, err := cast.ToBoolE(cmp.val); err == nil && cVal != tVal && cmp.scope.contains(span) {
+			weight = max(weight, matches.add(cmp.counter, cmp.weight))
 		}
 	}
-	return false
+	return weight
 }
 
-func (f *spanEvaluator) checkFloat64(tKey string, tVal float64) bool {
-	if cmp, has := f.eqTags[tKey]; has {
-		if cVal, err := cast.ToFloat64E(cmp); err == nil && cVal == tVal {
-			return true
+func (f *spanEvaluator) checkFloat64(span *model.Span, matches *spanMatches, tKey string, tVal float64) int {
+	weight := 0
+	for _, cmp := range f.eqTags[tKey] {
+		if cVal, err := cast.ToFloat64E(cmp.val); err == nil && cVal == tVal && cmp.scope.contains(span) {
+			weight = max(weight, matches.add(cmp.counter, cmp.weight))
 		}
 	}
-	if cmp, has := f.neTags[tKey]; has {
-		if cVal, err := cast.ToFloat64E(cmp); err == nil && cVal != tVal {
-			return true
+	for _, cmp := range f.neTags[tKey] {
+		if cVal, err := cast.ToFloat64E(cmp.val); err == nil && cVal != tVal && cmp.scope.contains(span) {
+			weight = max(weight, matches.add(cmp.counter, cmp.weight))
 		}
 	}
-	if cmp, has := f.ltTags[tKey]; has {
-		if cVal, err := cast.ToFloat64E(cmp); err == nil && cVal < tVal {
-			return true
+	for _, cmp := range f.ltTags[tKey] {
+		if cVal, err := cast.ToFloat64E(cmp.val); err == nil && tVal < cVal && cmp.scope.contains(span) {
+			weight = max(weight, matches.add(cmp.counter, cmp.weight))
 		}
 	}
-	if cmp, has := f.gtTags[tKey]; has {
-		if cVal, err := cast.ToFloat64E(cmp); err == nil && cVal > tVal {
-			return true
+	for _, cmp := range f.gtTags[tKey] {
+		if cVal, err := cast.ToFloat64E(cmp.val); err == nil && tVal > cVal && cmp.scope.contains(span) {
+			weight = max(weight, matches.add(cmp.counter, cmp.weight))
 		}
 	}
-	if cmp, has := f.leTags[tKey]; has {
-		if cVal, err := cast.ToFloat64E(cmp); err == nil && cVal <= tVal {
-			return true
+	for _, cmp := range f.leTags[tKey] {
+		if cVal, err := cast.ToFloat64E(cmp.val); err == nil && tVal <= cVal && cmp.scope.contains(span) {
+			weight = max(weight, matches.add(cmp.counter, cmp.weight))
 		}
 	}
-	if cmp, has := f.geTags[tKey]; has {
-		if cVal, err := cast.ToFloat64E(cmp); err == nil && cVal >= tVal {
-			return true
+	for _, cmp := range f.geTags[tKey] {
+		if cVal, err := cast.ToFloat64E(cmp.val); err == nil && tVal >= cVal && cmp.scope.contains(span) {
+			weight = max(weight, matches.add(cmp.counter, cmp.weight))
 		}
 	}
-	return false
+	return weight
 }
 
-func (f *spanEvaluator) checkString(tKey string, tVal string) bool {
-	if cmp, has := f.eqTags[tKey]; has {
-		if cVal, err := cast.ToStringE(cmp); err == nil && cVal == tVal {
-			return true
+func (f *spanEvaluator) checkString(span *model.Span, matches *spanMatches, tKey string, tVal string) int {
+	weight := 0
+	for _, cmp := range f.eqTags[tKey] {
+		if cVal, err := cast.ToStringE(cmp.val); err == nil && cVal == tVal && cmp.scope.contains(span) {
+			weight = max(weight, matches.add(cmp.counter, cmp.weight))
 		}
 	}
-	if cmp, has := f.neTags[tKey]; has {
-		if cVal, err := cast.ToStringE(cmp); err == nil && cVal != tVal {
-			return true
+	for _, cmp := range f.neTags[tKey] {
+		if cVal, err := cast.ToStringE(cmp.val); err == nil && cVal != tVal && cmp.scope.contains(span) {
+			weight = max(weight, matches.add(cmp.counter, cmp.weight))
 		}
 	}
-	return false
+	return weight
 }
 
-func (f *spanEvaluator) checkInt64(tKey string, tVal int64) bool {
-	if cmp, has := f.eqTags[tKey]; has {
-		if cVal, err := cast.ToInt64E(cmp); err == nil && cVal == tVal {
-			return true
+func (f *spanEvaluator) checkInt64(span *model.Span, matches *spanMatches, tKey string, tVal int64) int {
+	weight := 0
+	for _, cmp := range f.eqTags[tKey] {
+		if cVal, err := cast.ToInt64E(cmp.val); err == nil && cVal == tVal && cmp.scope.contains(span) {
+			weight = max(weight, matches.add(cmp.counter, cmp.weight))
 		}
 	}
-	if cmp, has := f.neTags[tKey]; has {
-		if cVal, err := cast.ToInt64E(cmp); err == nil && cVal != tVal {
-			return true
+	for _, cmp := range f.neTags[tKey] {
+		if cVal, err := cast.ToInt64E(cmp.val); err == nil && cVal != tVal && cmp.scope.contains(span) {
+			weight = max(weight, matches.add(cmp.counter, cmp.weight))
 		}
 	}
-	if cmp, has := f.ltTags[tKey]; has {
-		if cVal, err := cast.ToInt64E(cmp); err == nil && cVal < tVal {
-			return true
+	for _, cmp := range f.ltTags[tKey] {
+		if cVal, err := cast.ToInt64E(cmp.val); err == nil && tVal < cVal && cmp.scope.contains(span) {
+			weight = max(weight, matches.add(cmp.counter, cmp.weight))
 		}
 	}
-	if cmp, has := f.gtTags[tKey]; has {
-		if cVal, err := cast.ToInt64E(cmp); err == nil && cVal > tVal {
-			return true
+	for _, cmp := range f.gtTags[tKey] {
+		if cVal, err := cast.ToInt64E(cmp.val); err == nil && tVal > cVal && cmp.scope.contains(span) {
+			weight = max(weight, matches.add(cmp.counter, cmp.weight))
 		}
 	}
-	if cmp, has := f.leTags[tKey]; has {
-		if cVal, err := cast.ToInt64E(cmp); err == nil && cVal <= tVal {
-			return true
+	for _, cmp := range f.leTags[tKey] {
+		if cVal, err := cast.ToInt64E(cmp.val); err == nil && tVal <= cVal && cmp.scope.contains(span) {
+			weight = max(weight, matches.add(cmp.counter, cmp.weight))
 		}
 	}
-	if cmp, has := f.geTags[tKey]; has {
-		if cVal, err := cast.ToInt64E(cmp); err == nil && cVal >= tVal {
-			return true
+	for _, cmp := range f.geTags[tKey] {
+		if cVal, err := cast.ToInt64E(cmp.val); err == nil && tVal >= cVal && cmp.scope.contains(span) {
+			weight = max(weight, matches.add(cmp.counter, cmp.weight))
 		}
 	}
-	return false
+	return weight
+}
+
+func newCondition(tag *api_v1.EvaluatingTag) *condition {
+	weight := int(tag.GetWeight())
+	if weight < 1 {
+		weight = 1
+	}
+	return &condition{
+		val:    toActualType(tag),
+		weight: weight,
+		scope:  newScope(tag.GetService(), tag.GetOperation()),
+	}
+}
+
+// MaxWeight returns an EvaluateSpan which returns the greatest weight returned by evaluates.
+func MaxWeight(evaluates ...EvaluateSpan) EvaluateSpan {
+	return func(span *model.Span) int {
+		weight := 0
+		for _, evaluate := range evaluates {
+			weight = max(weight, evaluate(span))
+		}
+		return weight
+	}
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
 }
 
 func toActualType(tag *api_v1.EvaluatingTag) interface{} {
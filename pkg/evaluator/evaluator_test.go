@@ -24,7 +24,7 @@ import (
 
 func TestMustReturnsFalseWhenGetDifferentTypeButSameTagName(t *testing.T) {
 	logger, _ := zap.NewDevelopment()
-	eval := NewEvaluator(logger)
+	eval := NewEvaluator(logger, nil)
 	tagName := "tag name"
 	span := &model.Span{
 		Tags: []model.KeyValue{
@@ -84,7 +84,7 @@ func TestMustReturnsFalseWhenGetDifferentTypeButSameTagName(t *testing.T) {
 
 func TestMustReturnsTrueWhenTypeAndValueMatched(t *testing.T) {
 	logger, _ := zap.NewDevelopment()
-	eval := NewEvaluator(logger)
+	eval := NewEvaluator(logger, nil)
 	tagName := "tag name"
 
 	span := &model.Span{
@@ -179,3 +179,104 @@ func TestMustReturnsTrueWhenTypeAndValueMatched(t *testing.T) {
 	eval.Update(evaluatingTags)
 	assert.True(t, eval.Evaluate(span))
 }
+
+func TestMustReturnsGreatestWeightOfMatchedTags(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	eval := NewEvaluator(logger, nil)
+
+	span := &model.Span{
+		Tags: []model.KeyValue{
+			{
+				Key:   "error",
+				VType: model.ValueType_BOOL,
+				VBool: true,
+			},
+			{
+				Key:    "http.status_code",
+				VType:  model.ValueType_INT64,
+				VInt64: 500,
+			},
+		},
+	}
+	evaluatingTags := &api_v1.EvaluatingTags{
+		Tags: []*api_v1.EvaluatingTag{
+			{
+				TagName:       "error",
+				OperationType: api_v1.EvaluatingTag_EQUAL_TO,
+				ValueType:     api_v1.EvaluatingTag_BOOLEAN,
+				Value: &api_v1.EvaluatingTag_BooleanVal{
+					BooleanVal: true},
+			},
+			{
+				TagName:       "http.status_code",
+				OperationType: api_v1.EvaluatingTag_EQUAL_TO,
+				ValueType:     api_v1.EvaluatingTag_INTEGER,
+				Value: &api_v1.EvaluatingTag_IntegerVal{
+					IntegerVal: 500},
+				Weight: 3,
+			},
+		},
+	}
+	eval.Update(evaluatingTags)
+	assert.Equal(t, 3, eval.Weigh(span))
+
+	// tags without weight are weighted as 1.
+	span.Tags = span.Tags[:1]
+	assert.Equal(t, 1, eval.Weigh(span))
+
+	span.Tags = nil
+	assert.Equal(t, 0, eval.Weigh(span))
+	assert.False(t, eval.Evaluate(span))
+}
+
+func TestOrderingTagsMustCompareSpanValuesWithThresholds(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	eval := NewEvaluator(logger, nil)
+	tag := func(opType api_v1.EvaluatingTag_OperationType) *api_v1.EvaluatingTag {
+		return &api_v1.EvaluatingTag{
+			TagName:       "http.status_code",
+			OperationType: opType,
+			ValueType:     api_v1.EvaluatingTag_INTEGER,
+			Value:         &api_v1.EvaluatingTag_IntegerVal{IntegerVal: 500},
+		}
+	}
+	floatTag := func(opType api_v1.EvaluatingTag_OperationType) *api_v1.EvaluatingTag {
+		return &api_v1.EvaluatingTag{
+			TagName:       "amount",
+			OperationType: opType,
+			ValueType:     api_v1.EvaluatingTag_FLOAT,
+			Value:         &api_v1.EvaluatingTag_FloatVal{FloatVal: 99.5},
+		}
+	}
+	span := func(code int64, amount float64) *model.Span {
+		return &model.Span{
+			Tags: []model.KeyValue{
+				model.Int64("http.status_code", code),
+				model.Float64("amount", amount),
+			},
+		}
+	}
+
+	cases := []struct {
+		opType  api_v1.EvaluatingTag_OperationType
+		less    bool
+		equal   bool
+		greater bool
+	}{
+		{api_v1.EvaluatingTag_GREATER_THAN, false, false, true},
+		{api_v1.EvaluatingTag_GREATER_THAN_OR_EQUAL_TO, false, true, true},
+		{api_v1.EvaluatingTag_LESS_THAN, true, false, false},
+		{api_v1.EvaluatingTag_LESS_THAN_OR_EQUAL_TO, true, true, false},
+	}
+	for _, c := range cases {
+		eval.Update(&api_v1.EvaluatingTags{Tags: []*api_v1.EvaluatingTag{tag(c.opType)}})
+		assert.Equal(t, c.less, eval.Evaluate(span(404, 0)), c.opType.String())
+		assert.Equal(t, c.equal, eval.Evaluate(span(500, 0)), c.opType.String())
+		assert.Equal(t, c.greater, eval.Evaluate(span(503, 0)), c.opType.String())
+
+		eval.Update(&api_v1.EvaluatingTags{Tags: []*api_v1.EvaluatingTag{floatTag(c.opType)}})
+		assert.Equal(t, c.less, eval.Evaluate(span(0, 10)), c.opType.String())
+		assert.Equal(t, c.equal, eval.Evaluate(span(0, 99.5)), c.opType.String())
+		assert.Equal(t, c.greater, eval.Evaluate(span(0, 120.25)), c.opType.String())
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright (c) 2021 The Houyi Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package evaluator
+
+import (
+	"github.com/jaegertracing/jaeger/model"
+)
+
+// Besides tags of spans, evaluating tags and rules can refer to these fields of spans.
+const (
+	// durationField is the duration of span in microseconds.
+	durationField = "duration"
+	// warningsField is every warning of span.
+	warningsField = "warnings"
+	// logFieldPrefix prefixes keys of fields in logs of span, e.g. "log.event".
+	logFieldPrefix = "log."
+	// processTagPrefix prefixes keys of tags of the process of span, e.g. "process.hostname".
+	processTagPrefix = "process."
+)
+
+// eachField calls fn with every field of span: its tags, the tags of its process, the fields of its logs, its
+// duration and its warnings, keyed as described above. It stops as soon as fn returns false. A field may occur more
+// than once, e.g. "log.event" of several logs.
+func eachField(span *model.Span, fn func(kv *model.KeyValue) bool) {
+	for i := range span.Tags {
+		if !fn(&span.Tags[i]) {
+			return
+		}
+	}
+	if process := span.GetProcess(); process != nil {
+		for _, t := range process.Tags {
+			t.Key = processTagPrefix + t.Key
+			if !fn(&t) {
+				return
+			}
+		}
+	}
+	for _, l := range span.Logs {
+		for _, f := range l.Fields {
+			f.Key = logFieldPrefix + f.Key
+			if !fn(&f) {
+				return
+			}
+		}
+	}
+	duration := model.Int64(durationField, int64(model.DurationAsMicroseconds(span.Duration)))
+	if !fn(&duration) {
+		return
+	}
+	for _, w := range span.Warnings {
+		warning := model.String(warningsField, w)
+		if !fn(&warning) {
+			return
+		}
+	}
+}
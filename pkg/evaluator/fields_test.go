@@ -0,0 +1,127 @@
+// Copyright (c) 2021 The Houyi Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package evaluator
+
+import (
+	"github.com/houyi-tracing/houyi/idl/api_v1"
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"testing"
+	"time"
+)
+
+func newFieldsTestSpan() *model.Span {
+	return &model.Span{
+		Duration: 750 * time.Millisecond,
+		Tags: []model.KeyValue{
+			model.Binary("payload", []byte{0x1}),
+		},
+		Logs: []model.Log{
+			{Fields: []model.KeyValue{model.String("event", "retry")}},
+			{Fields: []model.KeyValue{
+				model.String("event", "error"),
+				model.String("error.kind", "TimeoutException"),
+			}},
+		},
+		Process: &model.Process{
+			ServiceName: "frontend",
+			Tags: []model.KeyValue{
+				model.String("hostname", "node-3"),
+				model.String("version", "1.2.7"),
+			},
+		},
+		Warnings: []string{"clock skew adjustment disabled"},
+	}
+}
+
+func TestEachField(t *testing.T) {
+	span := newFieldsTestSpan()
+	keys := make([]string, 0)
+	eachField(span, func(kv *model.KeyValue) bool {
+		keys = append(keys, kv.Key)
+		return true
+	})
+	assert.Equal(t, []string{
+		"payload", "process.hostname", "process.version", "log.event", "log.event", "log.error.kind",
+		"duration", "warnings",
+	}, keys)
+	assert.Equal(t, "hostname", span.Process.Tags[0].Key)
+	assert.Equal(t, "event", span.Logs[0].Fields[0].Key)
+}
+
+func TestRulesOnFields(t *testing.T) {
+	span := newFieldsTestSpan()
+	cases := map[string]bool{
+		`duration > 500000`:                         true,
+		`duration <= 500000`:                        false,
+		`log.event == "error"`:                      true,
+		`log.event == "retry"`:                      true,
+		`log.event == "timeout"`:                    false,
+		`log.error.kind matches "Exception$"`:       true,
+		`process.version startsWith "1.2."`:         true,
+		`process.hostname in ["node-1", "node-2"]`:  false,
+		`warnings matches "clock skew"`:             true,
+		`payload == "x"`:                            false,
+		`hostname == "node-3"`:                      false,
+		`duration > 100000 && log.event == "error"`: true,
+	}
+	for expr, expected := range cases {
+		pred, err := Compile(expr)
+		if assert.NoError(t, err, expr) {
+			assert.Equal(t, expected, pred(span), expr)
+		}
+	}
+}
+
+func TestTagsOnFields(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	eval := NewEvaluator(logger, nil)
+	span := newFieldsTestSpan()
+
+	eval.Update(&api_v1.EvaluatingTags{
+		Tags: []*api_v1.EvaluatingTag{
+			{
+				TagName:       "duration",
+				OperationType: api_v1.EvaluatingTag_EQUAL_TO,
+				ValueType:     api_v1.EvaluatingTag_INTEGER,
+				Value:         &api_v1.EvaluatingTag_IntegerVal{IntegerVal: 750000},
+				Weight:        2,
+			},
+		},
+	})
+	assert.Equal(t, 2, eval.Weigh(span))
+
+	eval.Update(&api_v1.EvaluatingTags{
+		Tags: []*api_v1.EvaluatingTag{
+			{
+				TagName:       "log.event",
+				OperationType: api_v1.EvaluatingTag_EQUAL_TO,
+				ValueType:     api_v1.EvaluatingTag_STRING,
+				Value:         &api_v1.EvaluatingTag_StringVal{StringVal: "error"},
+				Weight:        3,
+			},
+			{
+				TagName:       "process.version",
+				OperationType: api_v1.EvaluatingTag_EQUAL_TO,
+				ValueType:     api_v1.EvaluatingTag_STRING,
+				Value:         &api_v1.EvaluatingTag_StringVal{StringVal: "1.2.7"},
+				Weight:        1,
+			},
+		},
+	})
+	assert.Equal(t, 3, eval.Weigh(span))
+	assert.False(t, eval.Evaluate(&model.Span{Duration: time.Second}))
+}
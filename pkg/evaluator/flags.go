@@ -0,0 +1,83 @@
+// Copyright (c) 2021 The Houyi Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package evaluator
+
+import (
+	"flag"
+	"github.com/spf13/viper"
+	"time"
+)
+
+const (
+	latencyZScore     = "evaluator.latency.z.score"
+	latencyPercentile = "evaluator.latency.percentile"
+	latencyAlpha      = "evaluator.latency.alpha"
+	latencyMinSamples = "evaluator.latency.min.samples"
+	latencyWeight     = "evaluator.latency.weight"
+	latencyInterval   = "evaluator.latency.promotion.interval"
+	latencyMaxOps     = "evaluator.latency.max.operations"
+
+	DefaultLatencyZScore     = 0.0
+	DefaultLatencyPercentile = 0.0
+	DefaultLatencyAlpha      = 0.05
+	DefaultLatencyMinSamples = 100
+	DefaultLatencyWeight     = 1
+	DefaultLatencyInterval   = 30 * time.Second
+
+	DefaultLatencyMaxOperations = 10000
+)
+
+type Flags struct {
+	LatencyZScore     float64
+	LatencyPercentile float64
+	LatencyAlpha      float64
+	LatencyMinSamples int
+	LatencyWeight     int
+	LatencyInterval   time.Duration
+	LatencyMaxOps     int
+}
+
+func AddFlags(flags *flag.FlagSet) {
+	flags.Float64(latencyZScore, DefaultLatencyZScore,
+		"[Sampling] Spans slower than the mean duration of their operations by this number of standard deviations "+
+			"are promoted. Latency evaluator is disabled if both this and percentile are 0.")
+	flags.Float64(latencyPercentile, DefaultLatencyPercentile,
+		"[Sampling] Percentile in (50, 100) of the normal distribution with the mean and standard deviation of "+
+			"durations of each operation, beyond which spans are promoted. It is converted to a z-score, so it "+
+			"is not an empirical percentile of observed durations. It overrides z-score if it is set.")
+	flags.Float64(latencyAlpha, DefaultLatencyAlpha,
+		"[Sampling] Smoothing factor in (0, 1] of moving averages of durations of operations.")
+	flags.Int(latencyMinSamples, DefaultLatencyMinSamples,
+		"[Sampling] Number of spans of an operation to observe before promoting its slow spans.")
+	flags.Int(latencyWeight, DefaultLatencyWeight,
+		"[Sampling] Weight of promotion of slow spans.")
+	flags.Duration(latencyInterval, DefaultLatencyInterval,
+		"[Sampling] Minimum interval between promotions of an operation for its slow spans. "+
+			"Every slow span is promoted if it is 0.")
+	flags.Int(latencyMaxOps, DefaultLatencyMaxOperations,
+		"[Sampling] Maximum number of operations of which latency baselines are kept. Baselines of the least "+
+			"recently seen operations are evicted beyond it.")
+}
+
+func (f *Flags) InitFromViper(v *viper.Viper) *Flags {
+	f.LatencyZScore = v.GetFloat64(latencyZScore)
+	f.LatencyPercentile = v.GetFloat64(latencyPercentile)
+	f.LatencyAlpha = v.GetFloat64(latencyAlpha)
+	f.LatencyMinSamples = v.GetInt(latencyMinSamples)
+	f.LatencyWeight = v.GetInt(latencyWeight)
+	f.LatencyInterval = v.GetDuration(latencyInterval)
+	f.LatencyMaxOps = v.GetInt(latencyMaxOps)
+	return f
+}
@@ -19,17 +19,25 @@ import (
 	"github.com/jaegertracing/jaeger/model"
 )
 
-// EvaluateSpan decides whether to increase the sampling rate of operation relate to this span and
-// it is called after FilterSpan.
-type EvaluateSpan func(span *model.Span) bool
+// EvaluateSpan decides how much to increase the sampling rate of operation relate to this span and
+// it is called after FilterSpan. It returns 0 if the sampling rate should not be increased.
+type EvaluateSpan func(span *model.Span) int
 
 type Evaluator interface {
-	// Evaluate returns true if span has tags that exist in evaluating tags, else false.
+	// Evaluate returns true if span has tags that exist in evaluating tags or matches an evaluating rule, else false.
 	Evaluate(span *model.Span) bool
 
-	// Get returns evaluating tags
+	// Weigh returns the greatest weight of evaluating tags and rules matched by span, or 0 if nothing is matched.
+	// Dry-run tags and rules are counted but do not contribute to the weight.
+	Weigh(span *model.Span) int
+
+	// Get returns evaluating tags and rules
 	Get() *api_v1.EvaluatingTags
 
-	// Update updates evaluating tags
+	// Update updates evaluating tags and rules. Rules whose expressions fail to compile are ignored.
 	Update(tags *api_v1.EvaluatingTags)
+
+	// Matches returns the number of spans matching each evaluating tag and rule, including dry-run ones which count
+	// spans without promoting their operations.
+	Matches() []*api_v1.EvaluatorMatch
 }
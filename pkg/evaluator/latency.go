@@ -0,0 +1,217 @@
+// Copyright (c) 2021 The Houyi Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package evaluator
+
+import (
+	"container/list"
+	"github.com/jaegertracing/jaeger/model"
+	"go.uber.org/zap"
+	"hash/fnv"
+	"math"
+	"sync"
+	"time"
+)
+
+// latencyShards is the number of shards of baselines, each of which is locked separately so that workers weighing
+// spans of different operations rarely contend.
+const latencyShards = 16
+
+// LatencyEvaluator promotes spans which are unusually slow for their operations.
+type LatencyEvaluator interface {
+	// Weigh returns the weight of promotion if the duration of span is an outlier of the latency baseline of its
+	// operation and the operation has not been promoted for outliers within the promotion interval, else 0.
+	// It also updates the baseline with the duration of span.
+	Weigh(span *model.Span) int
+}
+
+type LatencyEvaluatorParams struct {
+	Logger *zap.Logger
+	// ZScore is the number of standard deviations above the mean beyond which spans are outliers.
+	// The evaluator is disabled if it is not positive.
+	ZScore float64
+	// Percentile overrides ZScore by the z-score of this percentile of normal distribution if it is in (50, 100).
+	Percentile float64
+	// Alpha is the smoothing factor of moving averages in (0, 1]. Greater alpha forgets old durations faster.
+	Alpha float64
+	// MinSamples is the number of spans of an operation to observe before evaluating its spans.
+	MinSamples int
+	// Weight is the weight of promotion of outliers. 0 is treated as 1.
+	Weight int
+	// PromotionInterval is the minimum interval between promotions of an operation, so that an operation with a
+	// heavy tail of latency does not flood the strategy manager with promotions. 0 promotes every outlier.
+	PromotionInterval time.Duration
+	// MaxOperations is the maximum number of operations of which baselines are maintained. Baselines of the least
+	// recently weighed operations are evicted beyond it, so that high-cardinality operation names do not leak
+	// memory. It is rounded up to a multiple of the number of shards. DefaultLatencyMaxOperations is used if it is
+	// not positive.
+	MaxOperations int
+}
+
+// latencyEvaluator maintains the exponentially weighted moving average and variance of durations of each operation.
+type latencyEvaluator struct {
+	logger     *zap.Logger
+	zScore     float64
+	alpha      float64
+	minSamples int
+	weight     int
+	interval   time.Duration
+	shards     []*baselineShard
+}
+
+// baselineShard is a LRU cache of baselines, in which the most recently weighed baseline is at the back of lru.
+type baselineShard struct {
+	sync.Mutex
+
+	size      int
+	baselines map[latencyKey]*list.Element
+	lru       *list.List
+}
+
+type latencyKey struct {
+	service   string
+	operation string
+}
+
+type baseline struct {
+	key        latencyKey
+	count      int
+	mean       float64
+	variance   float64
+	promotedAt time.Time
+}
+
+func NewLatencyEvaluator(params *LatencyEvaluatorParams) LatencyEvaluator {
+	zScore := params.ZScore
+	if params.Percentile > 50 && params.Percentile < 100 {
+		zScore = math.Sqrt2 * math.Erfinv(2*params.Percentile/100-1)
+	} else if params.Percentile != 0 {
+		params.Logger.Warn("ignored invalid percentile of latency evaluator",
+			zap.Float64("percentile", params.Percentile))
+	}
+	alpha := params.Alpha
+	if alpha <= 0 || alpha > 1 {
+		alpha = DefaultLatencyAlpha
+	}
+	weight := params.Weight
+	if weight < 1 {
+		weight = 1
+	}
+	maxOperations := params.MaxOperations
+	if maxOperations <= 0 {
+		maxOperations = DefaultLatencyMaxOperations
+	}
+	shards := make([]*baselineShard, latencyShards)
+	for i := range shards {
+		shards[i] = &baselineShard{
+			size:      (maxOperations + latencyShards - 1) / latencyShards,
+			baselines: make(map[latencyKey]*list.Element),
+			lru:       list.New(),
+		}
+	}
+	return &latencyEvaluator{
+		logger:     params.Logger,
+		zScore:     zScore,
+		alpha:      alpha,
+		minSamples: params.MinSamples,
+		weight:     weight,
+		interval:   params.PromotionInterval,
+		shards:     shards,
+	}
+}
+
+func (e *latencyEvaluator) Weigh(span *model.Span) int {
+	if e.zScore <= 0 {
+		return 0
+	}
+
+	key := latencyKey{
+		service:   span.GetProcess().GetServiceName(),
+		operation: span.GetOperationName(),
+	}
+	duration := float64(span.Duration)
+
+	shard := e.shard(key)
+	shard.Lock()
+	defer shard.Unlock()
+
+	b := shard.get(key)
+	outlier := b.count >= e.minSamples && b.isOutlier(duration, e.zScore)
+	b.observe(duration, e.alpha)
+
+	if outlier {
+		now := time.Now()
+		if now.Sub(b.promotedAt) < e.interval {
+			return 0
+		}
+		b.promotedAt = now
+
+		e.logger.Debug("found latency outlier",
+			zap.String("service", key.service),
+			zap.String("operation", key.operation),
+			zap.Duration("duration", span.Duration),
+			zap.Duration("mean", time.Duration(b.mean)))
+		return e.weight
+	}
+	return 0
+}
+
+func (e *latencyEvaluator) shard(key latencyKey) *baselineShard {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key.service))
+	_, _ = h.Write([]byte{0})
+	_, _ = h.Write([]byte(key.operation))
+	return e.shards[h.Sum32()%uint32(len(e.shards))]
+}
+
+// get returns the baseline of key and marks it as the most recently used one. A new baseline is created if key is
+// absent, and the least recently used baseline is evicted if the shard is full.
+func (s *baselineShard) get(key latencyKey) *baseline {
+	if elem, has := s.baselines[key]; has {
+		s.lru.MoveToBack(elem)
+		return elem.Value.(*baseline)
+	}
+	if s.lru.Len() >= s.size {
+		oldest := s.lru.Front()
+		s.lru.Remove(oldest)
+		delete(s.baselines, oldest.Value.(*baseline).key)
+	}
+	b := &baseline{key: key}
+	s.baselines[key] = s.lru.PushBack(b)
+	return b
+}
+
+// isOutlier returns true if x is more than zScore standard deviations above the mean.
+func (b *baseline) isOutlier(x, zScore float64) bool {
+	stddev := math.Sqrt(b.variance)
+	if stddev == 0 {
+		return false
+	}
+	return (x-b.mean)/stddev > zScore
+}
+
+// observe adds x into the moving average and variance. Before 1/alpha samples have been observed, it weighs samples
+// equally so that the first samples do not dominate the baseline.
+func (b *baseline) observe(x, alpha float64) {
+	b.count++
+	if b.count == 1 {
+		b.mean = x
+		return
+	}
+	a := math.Max(alpha, 1/float64(b.count))
+	diff := x - b.mean
+	incr := a * diff
+	b.mean += incr
+	b.variance = (1 - a) * (b.variance + diff*incr)
+}
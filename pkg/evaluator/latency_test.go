@@ -0,0 +1,158 @@
+// Copyright (c) 2021 The Houyi Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package evaluator
+
+import (
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"math/rand"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// weighDuration weighs span as if it took duration d.
+func weighDuration(eval LatencyEvaluator, span *model.Span, d time.Duration) int {
+	span.Duration = d
+	return eval.Weigh(span)
+}
+
+// warmUp weighs n spans of operation with durations normally distributed around mean.
+func warmUp(eval LatencyEvaluator, service, operation string, n int, mean, stddev time.Duration) int {
+	r := rand.New(rand.NewSource(1))
+	promoted := 0
+	for i := 0; i < n; i++ {
+		d := mean + time.Duration(r.NormFloat64()*float64(stddev))
+		if weighDuration(eval, newScopedSpan(service, operation), d) > 0 {
+			promoted++
+		}
+	}
+	return promoted
+}
+
+func TestLatencyOutliers(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	eval := NewLatencyEvaluator(&LatencyEvaluatorParams{
+		Logger:     logger,
+		ZScore:     4,
+		Alpha:      0.05,
+		MinSamples: 50,
+		Weight:     3,
+	})
+
+	// no span is promoted before enough spans are observed
+	assert.Equal(t, 0, weighDuration(eval, newScopedSpan("search", "query"), time.Second))
+	assert.Equal(t, 0, warmUp(eval, "search", "query", 49, 100*time.Millisecond, 10*time.Millisecond))
+	assert.Equal(t, 0, warmUp(eval, "search", "query", 500, 100*time.Millisecond, 10*time.Millisecond))
+	assert.Equal(t, 0, warmUp(eval, "search", "suggest", 500, time.Second, 100*time.Millisecond))
+
+	assert.Equal(t, 3, weighDuration(eval, newScopedSpan("search", "query"), 500*time.Millisecond))
+	assert.Equal(t, 0, weighDuration(eval, newScopedSpan("search", "query"), 110*time.Millisecond))
+	assert.Equal(t, 0, weighDuration(eval, newScopedSpan("search", "suggest"), 500*time.Millisecond))
+	assert.Equal(t, 0, weighDuration(eval, newScopedSpan("frontend", "query"), 500*time.Millisecond))
+
+	// baseline follows a lasting shift of latency
+	warmUp(eval, "search", "query", 500, 500*time.Millisecond, 50*time.Millisecond)
+	assert.Equal(t, 0, weighDuration(eval, newScopedSpan("search", "query"), 500*time.Millisecond))
+}
+
+func TestLatencyPromotionInterval(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	eval := NewLatencyEvaluator(&LatencyEvaluatorParams{
+		Logger:            logger,
+		ZScore:            4,
+		MinSamples:        50,
+		PromotionInterval: time.Hour,
+	})
+	warmUp(eval, "search", "query", 500, 100*time.Millisecond, 10*time.Millisecond)
+	warmUp(eval, "search", "suggest", 500, 100*time.Millisecond, 10*time.Millisecond)
+
+	// outliers of an operation are promoted once per interval, independently of other operations
+	assert.Equal(t, 1, weighDuration(eval, newScopedSpan("search", "query"), time.Second))
+	assert.Equal(t, 0, weighDuration(eval, newScopedSpan("search", "query"), time.Second))
+	assert.Equal(t, 1, weighDuration(eval, newScopedSpan("search", "suggest"), time.Second))
+
+	key := latencyKey{service: "search", operation: "query"}
+	eval.(*latencyEvaluator).shard(key).get(key).promotedAt = time.Now().Add(-time.Hour)
+	assert.Equal(t, 1, weighDuration(eval, newScopedSpan("search", "query"), 10*time.Second))
+}
+
+func TestLatencyPercentile(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	eval := NewLatencyEvaluator(&LatencyEvaluatorParams{
+		Logger:     logger,
+		Percentile: 99.865,
+	}).(*latencyEvaluator)
+	assert.InDelta(t, 3.0, eval.zScore, 0.01)
+	assert.Equal(t, DefaultLatencyAlpha, eval.alpha)
+	assert.Equal(t, 1, eval.weight)
+
+	eval = NewLatencyEvaluator(&LatencyEvaluatorParams{
+		Logger:     logger,
+		ZScore:     2,
+		Percentile: 100,
+	}).(*latencyEvaluator)
+	assert.Equal(t, 2.0, eval.zScore)
+}
+
+func TestDisabledLatencyEvaluator(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	eval := NewLatencyEvaluator(&LatencyEvaluatorParams{Logger: logger})
+	warmUp(eval, "search", "query", 100, 100*time.Millisecond, 10*time.Millisecond)
+	assert.Equal(t, 0, weighDuration(eval, newScopedSpan("search", "query"), time.Hour))
+}
+
+func TestLatencyBaselinesMustBeBounded(t *testing.T) {
+	eval := NewLatencyEvaluator(&LatencyEvaluatorParams{
+		Logger:        zap.NewNop(),
+		ZScore:        3,
+		MinSamples:    10,
+		MaxOperations: latencyShards * 4,
+	}).(*latencyEvaluator)
+
+	countBaselines := func() int {
+		n := 0
+		for _, shard := range eval.shards {
+			assert.Equal(t, len(shard.baselines), shard.lru.Len())
+			n += len(shard.baselines)
+		}
+		return n
+	}
+
+	// Operation names of high cardinality such as raw URL paths.
+	for i := 0; i < 10000; i++ {
+		weighDuration(eval, newScopedSpan("svc", "/users/"+strconv.Itoa(i)), time.Millisecond)
+		assert.LessOrEqual(t, countBaselines(), latencyShards*4)
+	}
+
+	// Baselines of operations weighed recently are kept.
+	warmUp(eval, "svc", "op", 200, 100*time.Millisecond, 10*time.Millisecond)
+	for i := 0; i < latencyShards; i++ {
+		weighDuration(eval, newScopedSpan("svc", "/orders/"+strconv.Itoa(i)), time.Millisecond)
+		weighDuration(eval, newScopedSpan("svc", "op"), 100*time.Millisecond)
+	}
+	assert.Less(t, 0, weighDuration(eval, newScopedSpan("svc", "op"), time.Second))
+}
+
+func TestMaxWeight(t *testing.T) {
+	constant := func(weight int) EvaluateSpan {
+		return func(*model.Span) int {
+			return weight
+		}
+	}
+	assert.Equal(t, 0, MaxWeight()(&model.Span{}))
+	assert.Equal(t, 3, MaxWeight(constant(1), constant(3), constant(0))(&model.Span{}))
+}
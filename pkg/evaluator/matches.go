@@ -0,0 +1,111 @@
+// Copyright (c) 2021 The Houyi Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package evaluator
+
+import (
+	"fmt"
+	"github.com/houyi-tracing/houyi/idl/api_v1"
+	"github.com/uber/jaeger-lib/metrics"
+	"strconv"
+	"sync/atomic"
+)
+
+// matchCounter counts spans matching an evaluating tag or rule.
+type matchCounter struct {
+	name   string
+	dryRun bool
+	count  int64
+	metric metrics.Counter
+}
+
+// matchKey identifies counters of evaluating tags and rules, so that their counts are kept across updates.
+type matchKey struct {
+	name   string
+	dryRun bool
+}
+
+func newMatchCounter(factory metrics.Factory, name string, dryRun bool) *matchCounter {
+	return &matchCounter{
+		name:   name,
+		dryRun: dryRun,
+		metric: factory.Counter(metrics.Options{
+			Name: "matches",
+			Tags: map[string]string{"name": name, "dry_run": strconv.FormatBool(dryRun)},
+			Help: "Number of spans matching evaluating tags and rules",
+		}),
+	}
+}
+
+func (c *matchCounter) inc() {
+	atomic.AddInt64(&c.count, 1)
+	c.metric.Inc(1)
+}
+
+func (c *matchCounter) toProto() *api_v1.EvaluatorMatch {
+	return &api_v1.EvaluatorMatch{
+		Name:   c.name,
+		DryRun: c.dryRun,
+		Count:  atomic.LoadInt64(&c.count),
+	}
+}
+
+// spanMatches collects counters of evaluating tags and rules matched by a span, each of which is counted once.
+type spanMatches []*matchCounter
+
+// add adds c into matches and returns the weight of promotion, which is 0 for dry-run tags and rules.
+func (m *spanMatches) add(c *matchCounter, weight int) int {
+	found := false
+	for _, added := range *m {
+		if added == c {
+			found = true
+			break
+		}
+	}
+	if !found {
+		*m = append(*m, c)
+	}
+	if c.dryRun {
+		return 0
+	}
+	return weight
+}
+
+func (m spanMatches) count() {
+	for _, c := range m {
+		c.inc()
+	}
+}
+
+// tagName describes an evaluating tag, e.g. "amount GREATER_THAN 10000".
+func tagName(tag *api_v1.EvaluatingTag) string {
+	return scopedName(fmt.Sprintf("%s %s %v", tag.GetTagName(), tag.GetOperationType(), toActualType(tag)),
+		tag.GetService(), tag.GetOperation())
+}
+
+// ruleName describes an evaluating rule by its name, or by its expression if it has no name.
+func ruleName(rule *api_v1.EvaluatingRule) string {
+	name := rule.GetName()
+	if name == "" {
+		name = rule.GetExpression()
+	}
+	return scopedName(name, rule.GetService(), rule.GetOperation())
+}
+
+func scopedName(name, service, operation string) string {
+	if service == "" && operation == "" {
+		return name
+	}
+	return fmt.Sprintf("%s [service=%q operation=%q]", name, service, operation)
+}
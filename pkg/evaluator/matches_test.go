@@ -0,0 +1,90 @@
+// Copyright (c) 2021 The Houyi Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package evaluator
+
+import (
+	"github.com/houyi-tracing/houyi/idl/api_v1"
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"testing"
+)
+
+func newErrorTag(weight int32, dryRun bool) *api_v1.EvaluatingTag {
+	return &api_v1.EvaluatingTag{
+		TagName:       "log.event",
+		OperationType: api_v1.EvaluatingTag_EQUAL_TO,
+		ValueType:     api_v1.EvaluatingTag_STRING,
+		Value:         &api_v1.EvaluatingTag_StringVal{StringVal: "error"},
+		Weight:        weight,
+		DryRun:        dryRun,
+	}
+}
+
+func newErrorLogSpan(n int) *model.Span {
+	span := &model.Span{}
+	for i := 0; i < n; i++ {
+		span.Logs = append(span.Logs, model.Log{Fields: []model.KeyValue{model.String("event", "error")}})
+	}
+	return span
+}
+
+func TestMatches(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	eval := NewEvaluator(logger, nil)
+	eval.Update(&api_v1.EvaluatingTags{
+		Tags: []*api_v1.EvaluatingTag{newErrorTag(2, false)},
+		Rules: []*api_v1.EvaluatingRule{
+			{Name: "errors", Expression: `log.event == "error"`, Weight: 5, DryRun: true},
+			{Expression: `duration > 1000`, Service: "search"},
+		},
+	})
+
+	// spans are counted once however many times they match
+	assert.Equal(t, 2, eval.Weigh(newErrorLogSpan(3)))
+	assert.Equal(t, 2, eval.Weigh(newErrorLogSpan(1)))
+	assert.Equal(t, 0, eval.Weigh(newErrorLogSpan(0)))
+	assert.Equal(t, []*api_v1.EvaluatorMatch{
+		{Name: "duration > 1000 [service=\"search\" operation=\"\"]", Count: 0},
+		{Name: "errors", DryRun: true, Count: 2},
+		{Name: "log.event EQUAL_TO error", Count: 2},
+	}, eval.Matches())
+}
+
+func TestDryRun(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	eval := NewEvaluator(logger, nil)
+	eval.Update(&api_v1.EvaluatingTags{
+		Tags: []*api_v1.EvaluatingTag{newErrorTag(2, true)},
+	})
+	assert.Equal(t, 0, eval.Weigh(newErrorLogSpan(1)))
+	assert.False(t, eval.Evaluate(newErrorLogSpan(1)))
+	assert.Equal(t, int64(2), eval.Matches()[0].GetCount())
+	assert.True(t, eval.Matches()[0].GetDryRun())
+
+	// counts of unchanged tags are kept across updates, and dry-run tags are counted separately once promoted
+	eval.Update(&api_v1.EvaluatingTags{
+		Tags: []*api_v1.EvaluatingTag{newErrorTag(2, true), newErrorTag(3, false)},
+	})
+	assert.Equal(t, 3, eval.Weigh(newErrorLogSpan(1)))
+	matches := eval.Matches()
+	if assert.Len(t, matches, 2) {
+		assert.Equal(t, &api_v1.EvaluatorMatch{Name: "log.event EQUAL_TO error", Count: 1}, matches[0])
+		assert.Equal(t, &api_v1.EvaluatorMatch{Name: "log.event EQUAL_TO error", DryRun: true, Count: 3}, matches[1])
+	}
+
+	eval.Update(&api_v1.EvaluatingTags{})
+	assert.Empty(t, eval.Matches())
+}
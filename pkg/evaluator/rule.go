@@ -0,0 +1,500 @@
+// Copyright (c) 2021 The Houyi Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package evaluator
+
+import (
+	"fmt"
+	"github.com/jaegertracing/jaeger/model"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Predicate reports whether a span matches a compiled rule.
+type Predicate func(span *model.Span) bool
+
+// Compile compiles the expression of a rule into a predicate. An expression is made of conditions on fields of spans,
+// combined with && (and), || (or), ! (not) and parentheses. A condition compares the value of a field with a literal:
+//
+//	error == true && http.status_code >= 500
+//	http.method in ["POST", "PUT"] || !(peer.service startsWith "internal-")
+//	http.url matches "^/api/v[0-9]+/orders"
+//	duration > 500000 || log.event == "error" || process.version startsWith "1.2."
+//
+// Fields are tags of spans, "duration" in microseconds, "warnings", fields of logs prefixed with "log." and tags of
+// processes prefixed with "process.". Supported operators are ==, !=, >, >=, <, <=,
+// in, matches (regular expression) and startsWith. Literals are numbers, strings in single or double quotes, true and
+// false. Field names containing other characters than letters, digits and _.-/: can be quoted in backquotes.
+// A condition is true if any value of the field in the span satisfies it, so a condition on a field which the span does
+// not have, or whose values are of a different type from the literal, is false. Numbers are compared with values of
+// numbers or strings of numbers.
+func Compile(expression string) (Predicate, error) {
+	tokens, err := tokenize(expression)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	pred, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, fmt.Errorf("unexpected %s at position %d", tok, tok.pos)
+	}
+	return pred, nil
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenString
+	tokenNumber
+	tokenSymbol
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func (t token) String() string {
+	if t.kind == tokenEOF {
+		return "end of expression"
+	}
+	return strconv.Quote(t.text)
+}
+
+func (t token) is(kind tokenKind, texts ...string) bool {
+	if t.kind != kind {
+		return false
+	}
+	for _, text := range texts {
+		if t.text == text {
+			return true
+		}
+	}
+	return len(texts) == 0
+}
+
+var symbols = []string{"==", "!=", ">=", "<=", "&&", "||", "=", ">", "<", "!", "(", ")", "[", "]", ","}
+
+func tokenize(expr string) ([]token, error) {
+	tokens := make([]token, 0)
+	runes := []rune(expr)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '"' || r == '\'' || r == '`':
+			text, next, err := scanQuoted(runes, i)
+			if err != nil {
+				return nil, err
+			}
+			kind := tokenString
+			if r == '`' {
+				kind = tokenIdent
+			}
+			tokens = append(tokens, token{kind: kind, text: text, pos: i})
+			i = next
+		case unicode.IsDigit(r) || (r == '-' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			start := i
+			for i++; i < len(runes) && (unicode.IsDigit(runes[i]) || strings.ContainsRune(".eE+-", runes[i])); i++ {
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: string(runes[start:i]), pos: start})
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i++; i < len(runes) && isIdentRune(runes[i]); i++ {
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: string(runes[start:i]), pos: start})
+		default:
+			matched := false
+			for _, sym := range symbols {
+				if strings.HasPrefix(string(runes[i:]), sym) {
+					tokens = append(tokens, token{kind: tokenSymbol, text: sym, pos: i})
+					i += len([]rune(sym))
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, fmt.Errorf("unexpected character %q at position %d", r, i)
+			}
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, pos: len(runes)}), nil
+}
+
+func isIdentRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("_.-/:", r)
+}
+
+// scanQuoted scans the quoted text starting at runes[start] and returns it with the position following it.
+func scanQuoted(runes []rune, start int) (string, int, error) {
+	quote := runes[start]
+	var sb strings.Builder
+	for i := start + 1; i < len(runes); i++ {
+		switch runes[i] {
+		case quote:
+			return sb.String(), i + 1, nil
+		case '\\':
+			if i+1 < len(runes) {
+				i++
+			}
+		}
+		sb.WriteRune(runes[i])
+	}
+	return "", 0, fmt.Errorf("unterminated quote at position %d", start)
+}
+
+// literal is a constant in expressions.
+type literal struct {
+	raw     string
+	str     string
+	num     float64
+	integer int64
+	isInt   bool
+	boolean bool
+	kind    tokenKind
+}
+
+const tokenBool = tokenSymbol + 1
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *parser) accept(kind tokenKind, texts ...string) bool {
+	if p.peek().is(kind, texts...) {
+		p.next()
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(kind tokenKind, text string) error {
+	if tok := p.next(); !tok.is(kind, text) {
+		return fmt.Errorf("expected %q but got %s at position %d", text, tok, tok.pos)
+	}
+	return nil
+}
+
+func (p *parser) parseOr() (Predicate, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept(tokenSymbol, "||") || p.accept(tokenIdent, "or") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = or(left, right)
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (Predicate, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept(tokenSymbol, "&&") || p.accept(tokenIdent, "and") {
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = and(left, right)
+	}
+	return left, nil
+}
+
+func (p *parser) parseNot() (Predicate, error) {
+	if p.accept(tokenSymbol, "!") || p.accept(tokenIdent, "not") {
+		pred, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return func(span *model.Span) bool {
+			return !pred(span)
+		}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (Predicate, error) {
+	if p.accept(tokenSymbol, "(") {
+		pred, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(tokenSymbol, ")"); err != nil {
+			return nil, err
+		}
+		return pred, nil
+	}
+
+	field := p.next()
+	if field.kind != tokenIdent {
+		return nil, fmt.Errorf("expected tag name but got %s at position %d", field, field.pos)
+	}
+
+	op := p.next()
+	switch {
+	case op.is(tokenSymbol, "==", "=", "!=", ">", ">=", "<", "<="):
+		lit, err := p.parseLiteral()
+		if err != nil {
+			return nil, err
+		}
+		if lit.kind == tokenBool && !op.is(tokenSymbol, "==", "=", "!=") {
+			return nil, fmt.Errorf("operator %s at position %d can not be applied to booleans", op, op.pos)
+		}
+		return compare(field.text, op.text, lit), nil
+	case op.is(tokenIdent, "in"):
+		lits, err := p.parseList()
+		if err != nil {
+			return nil, err
+		}
+		return in(field.text, lits), nil
+	case op.is(tokenIdent, "matches"):
+		lit, err := p.parseLiteral()
+		if err != nil {
+			return nil, err
+		}
+		if lit.kind != tokenString {
+			return nil, fmt.Errorf("expected regular expression in quotes after %s at position %d", op, op.pos)
+		}
+		re, err := regexp.Compile(lit.str)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regular expression at position %d: %w", op.pos, err)
+		}
+		return matchString(field.text, re.MatchString), nil
+	case op.is(tokenIdent, "startsWith"):
+		lit, err := p.parseLiteral()
+		if err != nil {
+			return nil, err
+		}
+		if lit.kind != tokenString {
+			return nil, fmt.Errorf("expected prefix in quotes after %s at position %d", op, op.pos)
+		}
+		return matchString(field.text, func(s string) bool {
+			return strings.HasPrefix(s, lit.str)
+		}), nil
+	default:
+		return nil, fmt.Errorf("expected operator after tag %s but got %s at position %d", field, op, op.pos)
+	}
+}
+
+func (p *parser) parseLiteral() (*literal, error) {
+	tok := p.next()
+	lit := &literal{raw: tok.text, kind: tok.kind}
+	switch {
+	case tok.kind == tokenString:
+		lit.str = tok.text
+	case tok.kind == tokenNumber:
+		num, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %s at position %d", tok, tok.pos)
+		}
+		lit.num = num
+		if integer, err := strconv.ParseInt(tok.text, 10, 64); err == nil {
+			lit.integer, lit.isInt = integer, true
+		}
+	case tok.is(tokenIdent, "true", "false"):
+		lit.kind = tokenBool
+		lit.boolean = tok.text == "true"
+	default:
+		return nil, fmt.Errorf("expected value but got %s at position %d", tok, tok.pos)
+	}
+	return lit, nil
+}
+
+func (p *parser) parseList() ([]*literal, error) {
+	if err := p.expect(tokenSymbol, "["); err != nil {
+		return nil, err
+	}
+	lits := make([]*literal, 0)
+	for {
+		lit, err := p.parseLiteral()
+		if err != nil {
+			return nil, err
+		}
+		lits = append(lits, lit)
+		if !p.accept(tokenSymbol, ",") {
+			break
+		}
+	}
+	if err := p.expect(tokenSymbol, "]"); err != nil {
+		return nil, err
+	}
+	return lits, nil
+}
+
+func and(left, right Predicate) Predicate {
+	return func(span *model.Span) bool {
+		return left(span) && right(span)
+	}
+}
+
+func or(left, right Predicate) Predicate {
+	return func(span *model.Span) bool {
+		return left(span) || right(span)
+	}
+}
+
+func compare(field, op string, lit *literal) Predicate {
+	return anyValue(field, func(val interface{}) bool {
+		cmp, ok := compareValue(val, lit)
+		if !ok {
+			return false
+		}
+		switch op {
+		case "==", "=":
+			return cmp == 0
+		case "!=":
+			return cmp != 0
+		case ">":
+			return cmp > 0
+		case ">=":
+			return cmp >= 0
+		case "<":
+			return cmp < 0
+		case "<=":
+			return cmp <= 0
+		}
+		return false
+	})
+}
+
+func in(field string, lits []*literal) Predicate {
+	return anyValue(field, func(val interface{}) bool {
+		for _, lit := range lits {
+			if cmp, ok := compareValue(val, lit); ok && cmp == 0 {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+func matchString(field string, match func(string) bool) Predicate {
+	return anyValue(field, func(val interface{}) bool {
+		s, ok := val.(string)
+		return ok && match(s)
+	})
+}
+
+// anyValue returns a predicate which is true if any value of field of span satisfies cond.
+func anyValue(field string, cond func(val interface{}) bool) Predicate {
+	return func(span *model.Span) bool {
+		matched := false
+		eachField(span, func(kv *model.KeyValue) bool {
+			if kv.Key == field {
+				if val, ok := tagValue(kv); ok && cond(val) {
+					matched = true
+				}
+			}
+			return !matched
+		})
+		return matched
+	}
+}
+
+func tagValue(kv *model.KeyValue) (interface{}, bool) {
+	switch kv.GetVType() {
+	case model.ValueType_STRING:
+		return kv.GetVStr(), true
+	case model.ValueType_BOOL:
+		return kv.GetVBool(), true
+	case model.ValueType_INT64:
+		return kv.GetVInt64(), true
+	case model.ValueType_FLOAT64:
+		return kv.GetVFloat64(), true
+	default:
+		return nil, false
+	}
+}
+
+// compareValue compares val with lit. It returns false if they are of different types. Booleans are only equal or
+// not equal to each other.
+func compareValue(val interface{}, lit *literal) (int, bool) {
+	switch lit.kind {
+	case tokenString:
+		if s, ok := val.(string); ok {
+			return strings.Compare(s, lit.str), true
+		}
+	case tokenBool:
+		if b, ok := val.(bool); ok {
+			if b == lit.boolean {
+				return 0, true
+			}
+			return 1, true
+		}
+	case tokenNumber:
+		switch v := val.(type) {
+		case int64:
+			if lit.isInt {
+				return compareInt64(v, lit.integer), true
+			}
+			return compareFloat64(float64(v), lit.num), true
+		case float64:
+			return compareFloat64(v, lit.num), true
+		case string:
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				return compareFloat64(f, lit.num), true
+			}
+		}
+	}
+	return 0, false
+}
+
+func compareInt64(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func compareFloat64(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright (c) 2021 The Houyi Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package evaluator
+
+import (
+	"github.com/houyi-tracing/houyi/idl/api_v1"
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"testing"
+)
+
+func newRuleTestSpan() *model.Span {
+	return &model.Span{
+		Tags: []model.KeyValue{
+			model.Bool("error", true),
+			model.Int64("http.status_code", 503),
+			model.String("http.method", "POST"),
+			model.String("http.url", "/api/v2/orders/42"),
+			model.String("peer.service", "internal-billing"),
+			model.String("retries", "3"),
+			model.Float64("ratio", 0.75),
+			model.String("tag name", "with spaces"),
+		},
+	}
+}
+
+func TestCompileExpressions(t *testing.T) {
+	span := newRuleTestSpan()
+	cases := map[string]bool{
+		`error == true && http.status_code >= 500`: true,
+		`error = false || http.status_code < 500`:  false,
+		`!error == true`:                                                  false,
+		`not (http.status_code == 503)`:                                   false,
+		`http.method in ["GET", "POST"] and ratio > 0.5`:                  true,
+		`http.method in ['GET', 'PUT']`:                                   false,
+		`http.status_code in [500, 502, 503]`:                             true,
+		`http.url matches "^/api/v[0-9]+/orders"`:                         true,
+		`peer.service startsWith "internal-"`:                             true,
+		`peer.service startsWith "external-"`:                             false,
+		`retries > 2`:                                                     true,
+		`ratio <= 0.75 && ratio != 1`:                                     true,
+		`http.status_code > 502.5`:                                        true,
+		"`tag name` == 'with spaces'":                                     true,
+		`missing == 1 || missing != 1`:                                    false,
+		`http.status_code == "503"`:                                       false,
+		`(error == false || ratio < 1) && (retries == 3 || retries == 4)`: true,
+		`error == true || http.status_code >= 500 && ratio > 1`:           true,
+		`(error == false || http.status_code >= 500) && ratio > 1`:        false,
+	}
+	for expr, expected := range cases {
+		pred, err := Compile(expr)
+		if assert.NoError(t, err, expr) {
+			assert.Equal(t, expected, pred(span), expr)
+		}
+	}
+}
+
+func TestCompileInvalidExpressions(t *testing.T) {
+	for _, expr := range []string{
+		``,
+		`error ==`,
+		`error > true`,
+		`(error == true`,
+		`error == true)`,
+		`error == true &&`,
+		`http.method in ["GET"`,
+		`http.method in []`,
+		`http.url matches "["`,
+		`http.url matches 1`,
+		`peer.service startsWith true`,
+		`http.method ~ "GET"`,
+		`http.method == "GET`,
+		`== 1`,
+	} {
+		_, err := Compile(expr)
+		assert.Error(t, err, expr)
+	}
+}
+
+func TestWeighWithRules(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	eval := NewEvaluator(logger, nil)
+	span := newRuleTestSpan()
+
+	eval.Update(&api_v1.EvaluatingTags{
+		Tags: []*api_v1.EvaluatingTag{
+			{
+				TagName:       "error",
+				OperationType: api_v1.EvaluatingTag_EQUAL_TO,
+				ValueType:     api_v1.EvaluatingTag_BOOLEAN,
+				Value:         &api_v1.EvaluatingTag_BooleanVal{BooleanVal: true},
+				Weight:        2,
+			},
+		},
+		Rules: []*api_v1.EvaluatingRule{
+			{Name: "server errors", Expression: `error == true && http.status_code >= 500`, Weight: 5},
+			{Name: "client errors", Expression: `http.status_code >= 400 && http.status_code < 500`, Weight: 10},
+			{Name: "invalid", Expression: `http.status_code >=`, Weight: 20},
+		},
+	})
+	assert.Equal(t, 5, eval.Weigh(span))
+	assert.Len(t, eval.Get().GetTags(), 1)
+	assert.Len(t, eval.Get().GetRules(), 2)
+
+	eval.Update(&api_v1.EvaluatingTags{
+		Rules: []*api_v1.EvaluatingRule{
+			{Name: "orders", Expression: `http.url startsWith "/api/v2/orders"`},
+		},
+	})
+	assert.Equal(t, 1, eval.Weigh(span))
+	assert.True(t, eval.Evaluate(span))
+	assert.False(t, eval.Evaluate(&model.Span{}))
+}
@@ -0,0 +1,75 @@
+// Copyright (c) 2021 The Houyi Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package evaluator
+
+import (
+	"github.com/jaegertracing/jaeger/model"
+	"regexp"
+	"strings"
+)
+
+// scope limits evaluating tags and rules to spans of some services and operations.
+type scope struct {
+	service   *regexp.Regexp
+	operation *regexp.Regexp
+}
+
+// newScope returns the scope of service and operation glob patterns. It returns nil if both patterns are empty,
+// which means that spans of all services and operations are in scope.
+func newScope(service, operation string) *scope {
+	if service == "" && operation == "" {
+		return nil
+	}
+	return &scope{
+		service:   compileGlob(service),
+		operation: compileGlob(operation),
+	}
+}
+
+// contains returns true if span is in scope.
+func (s *scope) contains(span *model.Span) bool {
+	if s == nil {
+		return true
+	}
+	if s.service != nil && !s.service.MatchString(span.GetProcess().GetServiceName()) {
+		return false
+	}
+	if s.operation != nil && !s.operation.MatchString(span.GetOperationName()) {
+		return false
+	}
+	return true
+}
+
+// compileGlob compiles a glob pattern in which '*' matches any sequence of characters and '?' matches any single
+// character. It returns nil for empty pattern.
+func compileGlob(pattern string) *regexp.Regexp {
+	if pattern == "" {
+		return nil
+	}
+	var sb strings.Builder
+	sb.WriteString("^")
+	for _, r := range pattern {
+		switch r {
+		case '*':
+			sb.WriteString(".*")
+		case '?':
+			sb.WriteString(".")
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	sb.WriteString("$")
+	return regexp.MustCompile(sb.String())
+}
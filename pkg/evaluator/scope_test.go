@@ -0,0 +1,90 @@
+// Copyright (c) 2021 The Houyi Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package evaluator
+
+import (
+	"github.com/houyi-tracing/houyi/idl/api_v1"
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"testing"
+)
+
+func newScopedSpan(service, operation string, tags ...model.KeyValue) *model.Span {
+	return &model.Span{
+		OperationName: operation,
+		Process:       &model.Process{ServiceName: service},
+		Tags:          tags,
+	}
+}
+
+func TestScopeContains(t *testing.T) {
+	span := newScopedSpan("payments", "charge")
+	assert.True(t, newScope("", "").contains(span))
+	assert.True(t, newScope("payments", "").contains(span))
+	assert.True(t, newScope("", "charge").contains(span))
+	assert.True(t, newScope("pay*", "ch?rge").contains(span))
+	assert.True(t, newScope("*", "*").contains(span))
+	assert.False(t, newScope("payment", "").contains(span))
+	assert.False(t, newScope("payments", "refund").contains(span))
+	assert.False(t, newScope("*.payments", "").contains(span))
+	assert.True(t, newScope("a.b+c", "").contains(newScopedSpan("a.b+c", "")))
+	assert.False(t, newScope("a.b+c", "").contains(newScopedSpan("axbbc", "")))
+	assert.False(t, newScope("payments", "").contains(&model.Span{}))
+}
+
+func TestScopedTags(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	eval := NewEvaluator(logger, nil)
+	amount := func(weight int32, service, operation string) *api_v1.EvaluatingTag {
+		return &api_v1.EvaluatingTag{
+			TagName:       "amount",
+			OperationType: api_v1.EvaluatingTag_EQUAL_TO,
+			ValueType:     api_v1.EvaluatingTag_INTEGER,
+			Value:         &api_v1.EvaluatingTag_IntegerVal{IntegerVal: 20000},
+			Weight:        weight,
+			Service:       service,
+			Operation:     operation,
+		}
+	}
+	eval.Update(&api_v1.EvaluatingTags{
+		Tags: []*api_v1.EvaluatingTag{
+			amount(2, "payments", "charge"),
+			amount(3, "payments", "refund*"),
+		},
+	})
+
+	large := model.Int64("amount", 20000)
+	assert.Equal(t, 2, eval.Weigh(newScopedSpan("payments", "charge", large)))
+	assert.Equal(t, 3, eval.Weigh(newScopedSpan("payments", "refund-partial", large)))
+	assert.Equal(t, 0, eval.Weigh(newScopedSpan("orders", "charge", large)))
+	assert.Equal(t, 0, eval.Weigh(newScopedSpan("payments", "charge", model.Int64("amount", 100))))
+}
+
+func TestScopedRules(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	eval := NewEvaluator(logger, nil)
+	eval.Update(&api_v1.EvaluatingTags{
+		Rules: []*api_v1.EvaluatingRule{
+			{Expression: `error == true`, Weight: 1},
+			{Expression: `error == true`, Weight: 4, Service: "search", Operation: "GET /search*"},
+		},
+	})
+
+	failed := model.Bool("error", true)
+	assert.Equal(t, 4, eval.Weigh(newScopedSpan("search", "GET /search/v2", failed)))
+	assert.Equal(t, 1, eval.Weigh(newScopedSpan("search", "GET /suggest", failed)))
+	assert.Equal(t, 1, eval.Weigh(newScopedSpan("frontend", "GET /search", failed)))
+}
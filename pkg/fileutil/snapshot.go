@@ -0,0 +1,108 @@
+// Copyright (c) 2021 The Houyi Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fileutil
+
+import (
+	"go.uber.org/zap"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// PeriodicSnapshot saves snapshots taken by a function into a file periodically, and saves the last one when it stops.
+// Snapshots are only saved when it stops if interval is not positive.
+type PeriodicSnapshot struct {
+	logger   *zap.Logger
+	name     string
+	path     string
+	interval time.Duration
+	take     func() ([]byte, error)
+	stopChan chan *sync.WaitGroup
+}
+
+// NewPeriodicSnapshot returns a PeriodicSnapshot which saves snapshots taken by function take into file path. Name
+// describes what the snapshots are of in logs.
+func NewPeriodicSnapshot(logger *zap.Logger,
+	name string,
+	path string,
+	interval time.Duration,
+	take func() ([]byte, error)) *PeriodicSnapshot {
+	return &PeriodicSnapshot{
+		logger:   logger,
+		name:     name,
+		path:     path,
+		interval: interval,
+		take:     take,
+		stopChan: make(chan *sync.WaitGroup),
+	}
+}
+
+// Read returns the last saved snapshot, or nil if there is not any.
+func (s *PeriodicSnapshot) Read() ([]byte, error) {
+	data, err := ioutil.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	return data, err
+}
+
+func (s *PeriodicSnapshot) Start() {
+	if s.interval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				s.Save()
+			case wg := <-s.stopChan:
+				wg.Done()
+				return
+			}
+		}
+	}()
+}
+
+// Stop saves the last snapshot and stops taking snapshots.
+func (s *PeriodicSnapshot) Stop() {
+	if s.interval > 0 {
+		wg := &sync.WaitGroup{}
+		wg.Add(1)
+		s.stopChan <- wg
+		wg.Wait()
+	}
+
+	s.Save()
+}
+
+// Save takes a snapshot and saves it into file atomically. Errors are logged only.
+func (s *PeriodicSnapshot) Save() {
+	data, err := s.take()
+	if err != nil {
+		s.logger.Error("failed to take snapshot of "+s.name, zap.Error(err))
+		return
+	}
+	if err = os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		s.logger.Error("failed to create directory for snapshot of "+s.name, zap.Error(err))
+		return
+	}
+	if err = WriteFileAtomically(s.path, data); err != nil {
+		s.logger.Error("failed to save snapshot of "+s.name, zap.Error(err))
+	}
+}
@@ -51,6 +51,45 @@ func (h *Handler) RelationHandler(rel *api_v1.Relation) {
 	}
 }
 
+func (h *Handler) ExpiredRelationHandler(rel *api_v1.Relation) {
+	h.logger.Debug("Handle expired relation", zap.String("relation", rel.String()))
+
+	if h.tg.HasRelation(rel) {
+		if err := h.tg.RemoveRelation(rel); err != nil {
+			h.logger.Error("failed to remove expired relation for trace graph", zap.Error(err))
+		}
+	}
+}
+
+func (h *Handler) RelationStatsHandler(stats *api_v1.RelationStats) {
+	h.logger.Debug("Handle relation stats", zap.String("stats", stats.String()))
+
+	if !h.tg.HasRelation(stats.GetRelation()) {
+		h.RelationHandler(stats.GetRelation())
+	}
+	if err := h.tg.MergeRelationStats(stats); err != nil {
+		h.logger.Error("failed to merge relation stats for trace graph", zap.Error(err))
+	}
+}
+
+func (h *Handler) PullGraphHandler() *api_v1.GraphState {
+	return h.tg.State()
+}
+
+func (h *Handler) PulledGraphHandler(state *api_v1.GraphState) {
+	h.logger.Debug("Handle pulled graph",
+		zap.Int("operations", len(state.GetOperations())), zap.Int("relations", len(state.GetRelations())))
+
+	h.tg.Merge(state)
+}
+
+func (h *Handler) GraphDeltaHandler(delta *api_v1.GraphDelta) {
+	h.logger.Debug("Handle graph delta",
+		zap.Int("operations", len(delta.GetOperations())), zap.Int("relations", len(delta.GetRelations())))
+
+	h.tg.MergeDelta(delta)
+}
+
 func (h *Handler) ExpiredOperationHandler(op *api_v1.Operation) {
 	h.logger.Debug("Handle expired operation", zap.String("operation", op.String()))
 
@@ -55,12 +55,32 @@ type Seed interface {
 	// operation and process it.
 	OnExpiredOperation(func(op *api_v1.Operation))
 
-	// MongerNewRelation activates message mongering to synchronize new relations between gossip seeds.
-	MongerNewRelation(rel *api_v1.Relation)
+	// OnExpiredRelation sets function that would be invoked when gossip seed received a message carrying expired
+	// relation and process it.
+	//
+	// Expired operations and relations are removed from trace graph and gossiped by graph deltas now, but messages
+	// of expired operations and relations are still handled for peers which have not been upgraded yet.
+	OnExpiredRelation(func(rel *api_v1.Relation))
 
-	// MongerNewOperation activates message mongering to synchronize new operations between gossip seeds.
-	MongerNewOperation(op *api_v1.Operation)
+	// OnRelationStats sets function that would be invoked when gossip seed received a message carrying statistics of
+	// calls of relations and process each of them.
+	OnRelationStats(func(stats *api_v1.RelationStats))
 
-	// MongerExpiredOperation activates message mongering to synchronize expired operations between gossip seeds.
-	MongerExpiredOperation(op *api_v1.Operation)
+	// OnPullGraph sets function that would be invoked to return the full trace graph when a peer pulls it.
+	OnPullGraph(func() *api_v1.GraphState)
+
+	// OnPulledGraph sets function that would be invoked with the full trace graph pulled from a peer when gossip seed
+	// starts, which is used to warm up new nodes.
+	OnPulledGraph(func(state *api_v1.GraphState))
+
+	// OnGraphDelta sets function that would be invoked when gossip seed received a message carrying version metadata
+	// of changed operations and relations of trace graph and process it.
+	OnGraphDelta(func(delta *api_v1.GraphDelta))
+
+	// MongerRelationStats activates message mongering to report statistics of calls of relations to gossip seeds.
+	MongerRelationStats(stats []*api_v1.RelationStats)
+
+	// MongerGraphDelta activates message mongering to synchronize changes of trace graph with their version metadata
+	// between gossip seeds. Nil delta is ignored.
+	MongerGraphDelta(delta *api_v1.GraphDelta)
 }
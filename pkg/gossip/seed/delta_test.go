@@ -0,0 +1,58 @@
+// Copyright (c) 2021 The Houyi Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package seed
+
+import (
+	"context"
+	"github.com/houyi-tracing/houyi/idl/api_v1"
+	"github.com/houyi-tracing/houyi/pkg/routing"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"testing"
+)
+
+func TestSyncGraphDelta(t *testing.T) {
+	logger := zap.NewNop()
+	received := make([]*api_v1.GraphDelta, 0)
+	s := NewSeed(logger,
+		Options.ConfigServerEndpoint(&routing.Endpoint{Addr: "localhost", Port: 0}),
+		Options.OnGraphDelta(func(delta *api_v1.GraphDelta) {
+			received = append(received, delta)
+		})).(*seed)
+	h := newGrpcHandler(logger, s.lruSize, s)
+
+	delta := &api_v1.GraphDelta{
+		Operations: []*api_v1.VersionedOperation{
+			{
+				Operation: &api_v1.Operation{Service: "svcA", Operation: "opA"},
+				Dots:      []*api_v1.Dot{{Replica: "a", Counter: 1}},
+			},
+		},
+	}
+	msg := &api_v1.Message{
+		MsgId:   1,
+		MsgType: api_v1.Message_GRAPH_DELTA,
+		Msg: &api_v1.Message_GraphDelta{
+			GraphDelta: delta,
+		},
+	}
+	_, err := h.Sync(context.Background(), msg)
+	assert.Nil(t, err)
+	// the same message is handled only once.
+	_, err = h.Sync(context.Background(), msg)
+	assert.Nil(t, err)
+
+	assert.Equal(t, []*api_v1.GraphDelta{delta}, received)
+}
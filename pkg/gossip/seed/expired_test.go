@@ -0,0 +1,64 @@
+// Copyright (c) 2021 The Houyi Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package seed
+
+import (
+	"context"
+	"github.com/houyi-tracing/houyi/idl/api_v1"
+	"github.com/houyi-tracing/houyi/pkg/routing"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"testing"
+)
+
+func TestSyncExpiredOperationAndRelation(t *testing.T) {
+	logger := zap.NewNop()
+	expiredOps := make([]*api_v1.Operation, 0)
+	expiredRels := make([]*api_v1.Relation, 0)
+	s := NewSeed(logger,
+		Options.ConfigServerEndpoint(&routing.Endpoint{Addr: "localhost", Port: 0}),
+		Options.OnExpiredOperation(func(op *api_v1.Operation) {
+			expiredOps = append(expiredOps, op)
+		}),
+		Options.OnExpiredRelation(func(rel *api_v1.Relation) {
+			expiredRels = append(expiredRels, rel)
+		})).(*seed)
+	h := newGrpcHandler(logger, s.lruSize, s)
+
+	op := &api_v1.Operation{Service: "svcA", Operation: "opA"}
+	rel := &api_v1.Relation{
+		From: op,
+		To:   &api_v1.Operation{Service: "svcB", Operation: "opB"},
+	}
+	_, err := h.Sync(context.Background(), &api_v1.Message{
+		MsgId:   1,
+		MsgType: api_v1.Message_EXPIRED_OPERATION,
+		Msg: &api_v1.Message_Operation{
+			Operation: op,
+		},
+	})
+	assert.Nil(t, err)
+	_, err = h.Sync(context.Background(), &api_v1.Message{
+		MsgId:   2,
+		MsgType: api_v1.Message_EXPIRED_RELATION,
+		Msg: &api_v1.Message_Relation{
+			Relation: rel,
+		},
+	})
+	assert.Nil(t, err)
+
+	assert.Equal(t, []*api_v1.Operation{op}, expiredOps)
+	assert.Equal(t, []*api_v1.Relation{rel}, expiredRels)
+}
@@ -18,6 +18,7 @@ import (
 	"flag"
 	"github.com/houyi-tracing/houyi/ports"
 	"github.com/spf13/viper"
+	"time"
 )
 
 const (
@@ -25,6 +26,7 @@ const (
 	configServerAddr     = "gossip.config.server.addr"
 	configServerGrpcPort = "gossip.config.server.grpc.port"
 	lruSize              = "gossip.seed.lru.size"
+	antiEntropyInterval  = "gossip.seed.anti.entropy.interval"
 )
 
 const (
@@ -32,6 +34,7 @@ const (
 	DefaultConfigServerAddr     = "config-server"
 	DefaultConfigServerGrpcPort = ports.ConfigServerGrpcListenPort
 	DefaultLruSize              = 10000
+	DefaultAntiEntropyInterval  = time.Minute
 )
 
 type Flags struct {
@@ -39,6 +42,7 @@ type Flags struct {
 	ConfigServerAddress  string
 	ConfigServerGrpcPort int
 	LruSize              int
+	AntiEntropyInterval  time.Duration
 }
 
 func AddFlags(flags *flag.FlagSet) {
@@ -46,6 +50,8 @@ func AddFlags(flags *flag.FlagSet) {
 	flags.String(configServerAddr, DefaultConfigServerAddr, "[Gossip] IP or domain name of configuration server.")
 	flags.Int(configServerGrpcPort, DefaultConfigServerGrpcPort, "[Gossip] Port to serve gRPC for configuration server.")
 	flags.Int(lruSize, DefaultLruSize, "[Gossip] Size of LRU for gossip message caching.")
+	flags.Duration(antiEntropyInterval, DefaultAntiEntropyInterval,
+		"[Gossip] Interval to pull trace graph from a random peer to repair changes lost in gossip. 0 disables it.")
 }
 
 func (f *Flags) InitFromViper(v *viper.Viper) *Flags {
@@ -53,5 +59,6 @@ func (f *Flags) InitFromViper(v *viper.Viper) *Flags {
 	f.ConfigServerAddress = v.GetString(configServerAddr)
 	f.ConfigServerGrpcPort = v.GetInt(configServerGrpcPort)
 	f.LruSize = v.GetInt(lruSize)
+	f.AntiEntropyInterval = v.GetDuration(antiEntropyInterval)
 	return f
 }
@@ -74,6 +74,14 @@ func (g *grpcHandler) Sync(_ context.Context, msg *api_v1.Message) (*api_v1.Null
 			defer s.onNewOperation(msg.GetOperation())
 		case api_v1.Message_EXPIRED_OPERATION:
 			defer s.onExpiredOperation(msg.GetOperation())
+		case api_v1.Message_EXPIRED_RELATION:
+			defer s.onExpiredRelation(msg.GetRelation())
+		case api_v1.Message_RELATION_STATS:
+			for _, stats := range msg.GetRelationStats().GetStats() {
+				defer s.onRelationStats(stats)
+			}
+		case api_v1.Message_GRAPH_DELTA:
+			defer s.onGraphDelta(msg.GetGraphDelta())
 		default:
 			g.logger.Error("Unsupported type of message")
 		}
@@ -114,3 +122,14 @@ func (g *grpcHandler) Sync(_ context.Context, msg *api_v1.Message) (*api_v1.Null
 
 	return &api_v1.NullReply{}, nil
 }
+
+// Pull returns the full trace graph of this node for peers which just started.
+func (g *grpcHandler) Pull(_ context.Context, req *api_v1.PullRequest) (*api_v1.GraphState, error) {
+	s := g.seed
+
+	g.logger.Debug("Received pull request", zap.Int64("node id", req.GetNodeId()))
+	if s.onPullGraph == nil {
+		return &api_v1.GraphState{}, nil
+	}
+	return s.onPullGraph(), nil
+}
@@ -17,6 +17,7 @@ package seed
 import (
 	"github.com/houyi-tracing/houyi/idl/api_v1"
 	"github.com/houyi-tracing/houyi/pkg/routing"
+	"time"
 )
 
 type options struct {
@@ -26,6 +27,14 @@ type options struct {
 	onNewRelation      func(rel *api_v1.Relation)
 	onNewOperation     func(op *api_v1.Operation)
 	onExpiredOperation func(op *api_v1.Operation)
+	onExpiredRelation  func(rel *api_v1.Relation)
+	onRelationStats    func(stats *api_v1.RelationStats)
+	onPullGraph        func() *api_v1.GraphState
+	onPulledGraph      func(state *api_v1.GraphState)
+	onGraphDelta       func(delta *api_v1.GraphDelta)
+
+	// antiEntropyInterval is the interval to pull trace graph from a random peer to repair changes lost in gossip.
+	antiEntropyInterval time.Duration
 }
 
 type Option func(opts *options)
@@ -68,6 +77,42 @@ func (options) OnExpiredOperation(f func(op *api_v1.Operation)) Option {
 	}
 }
 
+func (options) OnExpiredRelation(f func(rel *api_v1.Relation)) Option {
+	return func(opts *options) {
+		opts.onExpiredRelation = f
+	}
+}
+
+func (options) OnRelationStats(f func(stats *api_v1.RelationStats)) Option {
+	return func(opts *options) {
+		opts.onRelationStats = f
+	}
+}
+
+func (options) OnPullGraph(f func() *api_v1.GraphState) Option {
+	return func(opts *options) {
+		opts.onPullGraph = f
+	}
+}
+
+func (options) OnPulledGraph(f func(state *api_v1.GraphState)) Option {
+	return func(opts *options) {
+		opts.onPulledGraph = f
+	}
+}
+
+func (options) OnGraphDelta(f func(delta *api_v1.GraphDelta)) Option {
+	return func(opts *options) {
+		opts.onGraphDelta = f
+	}
+}
+
+func (options) AntiEntropyInterval(interval time.Duration) Option {
+	return func(opts *options) {
+		opts.antiEntropyInterval = interval
+	}
+}
+
 func (o options) apply(opts ...Option) options {
 	ret := options{}
 	for _, op := range opts {
@@ -93,11 +138,21 @@ func (o options) apply(opts ...Option) options {
 			// do nothing
 		}
 	}
+	if ret.onExpiredRelation == nil {
+		ret.onExpiredRelation = func(rel *api_v1.Relation) {
+			// do nothing
+		}
+	}
 	if ret.onNewOperation == nil {
 		ret.onNewOperation = func(op *api_v1.Operation) {
 			// do nothing
 		}
 	}
+	if ret.onGraphDelta == nil {
+		ret.onGraphDelta = func(delta *api_v1.GraphDelta) {
+			// do nothing
+		}
+	}
 
 	return ret
 }
@@ -0,0 +1,74 @@
+// Copyright (c) 2021 The Houyi Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package seed
+
+import (
+	"github.com/houyi-tracing/houyi/idl/api_v1"
+	"github.com/houyi-tracing/houyi/pkg/routing"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"net"
+	"testing"
+)
+
+func TestPullGraphFromPeer(t *testing.T) {
+	logger := zap.NewNop()
+	state := &api_v1.GraphState{
+		Operations: []*api_v1.Operation{
+			{Service: "svcA", Operation: "opA"},
+			{Service: "svcB", Operation: "opB"},
+		},
+		Relations: []*api_v1.Relation{
+			{
+				From: &api_v1.Operation{Service: "svcA", Operation: "opA"},
+				To:   &api_v1.Operation{Service: "svcB", Operation: "opB"},
+			},
+		},
+	}
+
+	peer := NewSeed(logger,
+		Options.ConfigServerEndpoint(&routing.Endpoint{Addr: "localhost", Port: 0}),
+		Options.OnPullGraph(func() *api_v1.GraphState {
+			return state
+		})).(*seed)
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	server := grpc.NewServer()
+	api_v1.RegisterSeedServer(server, newGrpcHandler(logger, peer.lruSize, peer))
+	go func() {
+		_ = server.Serve(lis)
+	}()
+	defer server.Stop()
+
+	var pulled *api_v1.GraphState
+	s := NewSeed(logger,
+		Options.ConfigServerEndpoint(&routing.Endpoint{Addr: "localhost", Port: 0}),
+		Options.OnPulledGraph(func(state *api_v1.GraphState) {
+			pulled = state
+		})).(*seed)
+	s.randomPick = 2
+	s.peers = []*api_v1.Peer{
+		// the first peer is unreachable and it would be skipped.
+		{Ip: "127.0.0.1", Port: 1},
+		{Ip: "127.0.0.1", Port: int64(lis.Addr().(*net.TCPAddr).Port)},
+	}
+	s.pullGraph()
+
+	assert.NotNil(t, pulled)
+	assert.Equal(t, 2, len(pulled.GetOperations()))
+	assert.Equal(t, 1, len(pulled.GetRelations()))
+	assert.Equal(t, "svcB", pulled.GetRelations()[0].GetTo().GetService())
+}
@@ -29,6 +29,11 @@ import (
 	"time"
 )
 
+const (
+	// pullTimeout is the timeout of pulling trace graph from a peer.
+	pullTimeout = time.Second * 5
+)
+
 const (
 	Susceptible = iota
 	Infected
@@ -56,6 +61,7 @@ type seed struct {
 	msgSender         chan *api_v1.Message
 	stopMsgSender     chan *sync.WaitGroup
 	stopTimer         chan *sync.WaitGroup
+	timers            int
 }
 
 func NewSeed(logger *zap.Logger, opts ...Option) gossip.Seed {
@@ -92,48 +98,67 @@ func (s *seed) OnNewOperation(f func(op *api_v1.Operation)) {
 	s.onNewOperation = f
 }
 
-func (s *seed) MongerExpiredOperation(op *api_v1.Operation) {
-	msg := &api_v1.Message{
-		MsgId:   s.msgIdGenerator.Generate().Int64(),
-		MsgType: api_v1.Message_EXPIRED_OPERATION,
-		Msg: &api_v1.Message_Operation{
-			Operation: op,
-		},
-	}
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
-	if s.grpcHandler != nil {
-		_, _ = s.grpcHandler.Sync(ctx, msg)
-	} else {
-		s.logger.Error("Grpc handler does not ready")
-	}
+func (s *seed) OnExpiredRelation(f func(rel *api_v1.Relation)) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.onExpiredRelation = f
 }
 
-func (s *seed) MongerNewRelation(rel *api_v1.Relation) {
-	msg := &api_v1.Message{
-		MsgId:   s.msgIdGenerator.Generate().Int64(),
-		MsgType: api_v1.Message_NEW_RELATION,
-		Msg: &api_v1.Message_Relation{
-			Relation: rel,
-		},
-	}
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
-	if s.grpcHandler != nil {
-		_, _ = s.grpcHandler.Sync(ctx, msg)
-	} else {
-		s.logger.Error("Grpc handler does not ready")
-	}
+func (s *seed) OnRelationStats(f func(stats *api_v1.RelationStats)) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.onRelationStats = f
 }
 
-func (s *seed) MongerNewOperation(op *api_v1.Operation) {
-	msg := &api_v1.Message{
-		MsgId:   s.msgIdGenerator.Generate().Int64(),
-		MsgType: api_v1.Message_NEW_OPERATION,
-		Msg: &api_v1.Message_Operation{
-			Operation: op,
+func (s *seed) OnPullGraph(f func() *api_v1.GraphState) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.onPullGraph = f
+}
+
+func (s *seed) OnPulledGraph(f func(state *api_v1.GraphState)) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.onPulledGraph = f
+}
+
+func (s *seed) OnGraphDelta(f func(delta *api_v1.GraphDelta)) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.onGraphDelta = f
+}
+
+func (s *seed) MongerRelationStats(stats []*api_v1.RelationStats) {
+	s.monger(&api_v1.Message{
+		MsgType: api_v1.Message_RELATION_STATS,
+		Msg: &api_v1.Message_RelationStats{
+			RelationStats: &api_v1.RelationStatsBatch{
+				Stats: stats,
+			},
 		},
+	})
+}
+
+func (s *seed) MongerGraphDelta(delta *api_v1.GraphDelta) {
+	if delta == nil {
+		return
 	}
+	s.monger(&api_v1.Message{
+		MsgType: api_v1.Message_GRAPH_DELTA,
+		Msg: &api_v1.Message_GraphDelta{
+			GraphDelta: delta,
+		},
+	})
+}
+
+// monger assigns a new ID to msg and activates message mongering of it.
+func (s *seed) monger(msg *api_v1.Message) {
+	msg.MsgId = s.msgIdGenerator.Generate().Int64()
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 	if s.grpcHandler != nil {
@@ -160,9 +185,17 @@ func (s *seed) Start() error {
 		s.msgIdGenerator = idGenerator
 	}
 
+	// warm up before serving, so that peers would not pull an empty trace graph from this node.
+	s.pullGraph()
+
 	go s.startGrpcServer()
 	go s.msgMonger()
 	go s.timer(s.heartbeat, s.heartbeatInterval)
+	s.timers = 1
+	if s.antiEntropyInterval > 0 {
+		go s.timer(s.antiEntropy, s.antiEntropyInterval)
+		s.timers++
+	}
 	return nil
 }
 
@@ -188,8 +221,10 @@ func (s *seed) Stop() error {
 	}
 
 	var wg sync.WaitGroup
-	wg.Add(2)
-	s.stopTimer <- &wg
+	wg.Add(s.timers + 1)
+	for i := 0; i < s.timers; i++ {
+		s.stopTimer <- &wg
+	}
 	s.stopMsgSender <- &wg
 	wg.Wait()
 	return nil
@@ -248,6 +283,55 @@ func (s *seed) msgMonger() {
 	}
 }
 
+// pullGraph pulls the full trace graph from randomly picked peers until one of them replies.
+func (s *seed) pullGraph() {
+	s.lock.Lock()
+	peers, onPulled := s.peers, s.onPulledGraph
+	s.lock.Unlock()
+
+	if onPulled == nil {
+		return
+	}
+	for _, peerIdx := range randomlyPick(len(peers), s.randomPick) {
+		ip, port := peers[peerIdx].GetIp(), peers[peerIdx].GetPort()
+		state, err := s.pull(ip, port)
+		if err != nil {
+			s.logger.Warn("Failed to pull trace graph from peer",
+				zap.String("ip", ip), zap.Int64("port", port), zap.Error(err))
+			continue
+		}
+		onPulled(state)
+		s.logger.Info("Pulled trace graph from peer",
+			zap.String("ip", ip),
+			zap.Int64("port", port),
+			zap.Int("operations", len(state.GetOperations())),
+			zap.Int("relations", len(state.GetRelations())))
+		return
+	}
+}
+
+// antiEntropy pulls trace graph from a random peer. Merging it repairs changes of trace graph lost in gossip, such as
+// messages evicted from LRU or never sent to this node.
+func (s *seed) antiEntropy() error {
+	s.pullGraph()
+	return nil
+}
+
+func (s *seed) pull(ip string, port int64) (*api_v1.GraphState, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), pullTimeout)
+	defer cancel()
+
+	conn, err := grpc.DialContext(ctx, formatEndpoint(ip, port),
+		grpc.WithInsecure(), grpc.WithBlock(), grpc.FailOnNonTempDialError(true))
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	c := api_v1.NewSeedClient(conn)
+	return c.Pull(ctx, &api_v1.PullRequest{NodeId: int64(s.nodeId)})
+}
+
 func (s *seed) register() error {
 	var conn *grpc.ClientConn
 	var err error
@@ -108,19 +108,29 @@ func TestMessageMongering(t *testing.T) {
 	time.Sleep(time.Second * 5)
 
 	startTime := time.Now()
-	s.MongerNewRelation(&api_v1.Relation{
-		From: &api_v1.Operation{
-			Service:   "svcA",
-			Operation: "op1",
-		},
-		To: &api_v1.Operation{
-			Service:   "svcB",
-			Operation: "op7",
+	s.(*seed).monger(&api_v1.Message{
+		MsgType: api_v1.Message_NEW_RELATION,
+		Msg: &api_v1.Message_Relation{
+			Relation: &api_v1.Relation{
+				From: &api_v1.Operation{
+					Service:   "svcA",
+					Operation: "op1",
+				},
+				To: &api_v1.Operation{
+					Service:   "svcB",
+					Operation: "op7",
+				},
+			},
 		},
 	})
-	s.MongerExpiredOperation(&api_v1.Operation{
-		Service:   "s",
-		Operation: "o",
+	s.(*seed).monger(&api_v1.Message{
+		MsgType: api_v1.Message_EXPIRED_OPERATION,
+		Msg: &api_v1.Message_Operation{
+			Operation: &api_v1.Operation{
+				Service:   "s",
+				Operation: "o",
+			},
+		},
 	})
 
 	isConv := false
@@ -200,14 +210,19 @@ func TestMongerNewRelation(t *testing.T) {
 	// wait goroutines to get ready
 	time.Sleep(time.Second * 3)
 
-	s.MongerNewRelation(&api_v1.Relation{
-		From: &api_v1.Operation{
-			Service:   "svcA",
-			Operation: "opA",
-		},
-		To: &api_v1.Operation{
-			Service:   "svcB",
-			Operation: "opB",
+	s.(*seed).monger(&api_v1.Message{
+		MsgType: api_v1.Message_NEW_RELATION,
+		Msg: &api_v1.Message_Relation{
+			Relation: &api_v1.Relation{
+				From: &api_v1.Operation{
+					Service:   "svcA",
+					Operation: "opA",
+				},
+				To: &api_v1.Operation{
+					Service:   "svcB",
+					Operation: "opB",
+				},
+			},
 		},
 	})
 
@@ -21,6 +21,7 @@ import (
 	"github.com/houyi-tracing/houyi/pkg/routing"
 	"github.com/houyi-tracing/houyi/pkg/tg"
 	"go.uber.org/zap"
+	"time"
 )
 
 type SeedParams struct {
@@ -29,6 +30,7 @@ type SeedParams struct {
 	LruSize              int
 	ConfigServerEndpoint *routing.Endpoint
 	TraceGraph           tg.TraceGraph
+	AntiEntropyInterval  time.Duration
 }
 
 func BuildSeed(params *SeedParams) (gossip.Seed, error) {
@@ -36,13 +38,19 @@ func BuildSeed(params *SeedParams) (gossip.Seed, error) {
 		params.Logger,
 		seed.Options.ListenPort(params.ListenPort),
 		seed.Options.LruSize(params.LruSize),
-		seed.Options.ConfigServerEndpoint(params.ConfigServerEndpoint))
+		seed.Options.ConfigServerEndpoint(params.ConfigServerEndpoint),
+		seed.Options.AntiEntropyInterval(params.AntiEntropyInterval))
 
 	gHandler := handler.NewHandler(params.Logger, params.TraceGraph)
 
 	s.OnNewOperation(gHandler.NewOperationHandler)
 	s.OnExpiredOperation(gHandler.ExpiredOperationHandler)
 	s.OnNewRelation(gHandler.RelationHandler)
+	s.OnExpiredRelation(gHandler.ExpiredRelationHandler)
+	s.OnRelationStats(gHandler.RelationStatsHandler)
+	s.OnPullGraph(gHandler.PullGraphHandler)
+	s.OnPulledGraph(gHandler.PulledGraphHandler)
+	s.OnGraphDelta(gHandler.GraphDeltaHandler)
 
 	return s, nil
 }
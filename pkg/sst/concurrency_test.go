@@ -0,0 +1,150 @@
+// Copyright (c) 2021 The Houyi Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sst
+
+import (
+	"fmt"
+	"github.com/houyi-tracing/houyi/idl/api_v1"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"math/rand"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Run these tests with "go test -race" to detect data races.
+
+func TestConcurrentAddPromotePruneGenerate(t *testing.T) {
+	tree := NewSamplingStrategyTree(maxN)
+
+	opsN := 200
+	ops := make([]*api_v1.Operation, 0, opsN)
+	for i := 0; i < opsN; i++ {
+		ops = append(ops, &api_v1.Operation{
+			Service:   fmt.Sprintf("svc_%d", i%5),
+			Operation: fmt.Sprintf("op_%d", i),
+		})
+	}
+
+	workers, times := 8, 2000
+	wg := &sync.WaitGroup{}
+	wg.Add(workers * 4)
+	for w := 0; w < workers; w++ {
+		r := rand.New(rand.NewSource(int64(w)))
+		go func(r *rand.Rand) {
+			defer wg.Done()
+			for i := 0; i < times; i++ {
+				_ = tree.Add(ops[r.Intn(opsN)])
+			}
+		}(rand.New(rand.NewSource(r.Int63())))
+		go func(r *rand.Rand) {
+			defer wg.Done()
+			for i := 0; i < times; i++ {
+				_ = tree.Promote(ops[r.Intn(opsN)], 1)
+			}
+		}(rand.New(rand.NewSource(r.Int63())))
+		go func(r *rand.Rand) {
+			defer wg.Done()
+			for i := 0; i < times; i++ {
+				_ = tree.Prune(ops[r.Intn(opsN)])
+			}
+		}(rand.New(rand.NewSource(r.Int63())))
+		go func(r *rand.Rand) {
+			defer wg.Done()
+			for i := 0; i < times; i++ {
+				if sr, err := tree.Generate(ops[r.Intn(opsN)]); err == nil {
+					assert.True(t, sr > 0 && sr <= 1)
+				}
+			}
+		}(rand.New(rand.NewSource(r.Int63())))
+	}
+	wg.Wait()
+
+	root := tree.(*sst).root
+	check(root, root, t)
+
+	sum := 0.0
+	for _, op := range tree.Operations() {
+		sr, err := tree.Generate(op)
+		assert.Nil(t, err)
+		sum += sr
+	}
+	if len(tree.Operations()) != 0 {
+		assert.Less(t, math.Abs(1.0-sum), 1e-10)
+	}
+}
+
+func TestConcurrentSnapshotAndPromote(t *testing.T) {
+	tree := NewSamplingStrategyTree(maxN)
+
+	opsN := 100
+	ops := make([]*api_v1.Operation, 0, opsN)
+	for i := 0; i < opsN; i++ {
+		op := &api_v1.Operation{
+			Service:   "svc",
+			Operation: fmt.Sprintf("op_%d", i),
+		}
+		assert.Nil(t, tree.Add(op))
+		ops = append(ops, op)
+	}
+
+	wg := &sync.WaitGroup{}
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 2000; i++ {
+			_ = tree.Promote(ops[rand.Intn(opsN)], 1)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			data, err := tree.Snapshot()
+			assert.Nil(t, err)
+			restored := NewSamplingStrategyTree(maxN)
+			assert.Nil(t, restored.Restore(data))
+		}
+	}()
+	wg.Wait()
+}
+
+func TestGenerateMustNotWaitForWriters(t *testing.T) {
+	op := &api_v1.Operation{Service: "svc", Operation: "op"}
+	for _, tree := range []SamplingStrategyTree{NewSamplingStrategyTree(maxN), NewServiceGroupedTree(maxN)} {
+		assert.Nil(t, tree.Add(op))
+		assert.Nil(t, tree.Add(&api_v1.Operation{Service: "svc", Operation: "other"}))
+
+		// hold locks of tree as a pending writer would
+		locker := tree.(sync.Locker)
+		locker.Lock()
+		if st, ok := tree.(*serviceTree); ok {
+			st.services["svc"].Lock()
+		}
+
+		done := make(chan float64)
+		go func() {
+			sr, err := tree.Generate(op)
+			assert.Nil(t, err)
+			done <- sr
+		}()
+		select {
+		case sr := <-done:
+			assert.Equal(t, 0.5, sr)
+		case <-time.After(time.Second):
+			t.Fatal("Generate is blocked by writer")
+		}
+	}
+}
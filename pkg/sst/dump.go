@@ -0,0 +1,105 @@
+// Copyright (c) 2021 The Houyi Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sst
+
+import (
+	"fmt"
+	"strings"
+)
+
+func (t *sst) Dump() *NodeInfo {
+	t.RLock()
+	defer t.RUnlock()
+
+	return dumpNode(t.root, 0, 0, 1.0)
+}
+
+func (t *sst) Walk(visit func(node *NodeInfo) bool) {
+	// Visit a copy so that visit could call methods of this tree without deadlock.
+	walk(t.Dump(), visit)
+}
+
+// dumpNode copies n and its descendants. rate is the sampling rate of n, which is the same as what Generate returns
+// for leaf nodes.
+func dumpNode(n *treeNode, depth, lruPosition int, rate float64) *NodeInfo {
+	info := &NodeInfo{
+		IsLeaf:       n.isLeaf(),
+		Depth:        depth,
+		LeafCount:    n.leafCnt,
+		LruPosition:  lruPosition,
+		SamplingRate: rate,
+	}
+	if n.isLeaf() {
+		info.Service = n.op.GetService()
+		info.Operation = n.op.GetOperation()
+		info.Boost = n.boost
+		return info
+	}
+
+	children := n.childNodes.all()
+	info.Children = make([]*NodeInfo, 0, len(children))
+	for i, c := range children {
+		info.Children = append(info.Children, dumpNode(c, depth+1, i, rate/float64(len(children))))
+	}
+	return info
+}
+
+func walk(node *NodeInfo, visit func(node *NodeInfo) bool) {
+	if !visit(node) {
+		return
+	}
+	for _, c := range node.Children {
+		walk(c, visit)
+	}
+}
+
+// Dot renders the tree rooted at root in Graphviz DOT language.
+func Dot(root *NodeInfo) string {
+	sb := &strings.Builder{}
+	sb.WriteString("digraph sst {\n")
+	sb.WriteString("  node [shape=box];\n")
+
+	id := 0
+	var render func(node *NodeInfo) int
+	render = func(node *NodeInfo) int {
+		curr := id
+		id++
+
+		var label string
+		if node.IsLeaf {
+			label = fmt.Sprintf("%s\\n%s\\nrate=%.6f lru=%d boost=%d",
+				escapeDot(node.Service), escapeDot(node.Operation), node.SamplingRate, node.LruPosition, node.Boost)
+			sb.WriteString(fmt.Sprintf("  n%d [label=\"%s\"];\n", curr, label))
+		} else {
+			label = fmt.Sprintf("depth=%d leaves=%d\\nrate=%.6f lru=%d",
+				node.Depth, node.LeafCount, node.SamplingRate, node.LruPosition)
+			sb.WriteString(fmt.Sprintf("  n%d [shape=ellipse, label=\"%s\"];\n", curr, label))
+		}
+
+		for _, c := range node.Children {
+			child := render(c)
+			sb.WriteString(fmt.Sprintf("  n%d -> n%d;\n", curr, child))
+		}
+		return curr
+	}
+	render(root)
+
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+func escapeDot(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s)
+}
@@ -0,0 +1,68 @@
+// Copyright (c) 2021 The Houyi Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sst
+
+import (
+	"fmt"
+	"github.com/houyi-tracing/houyi/idl/api_v1"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestDumpedRatesMustEqualToGeneratedRates(t *testing.T) {
+	tree := NewSamplingStrategyTree(maxN)
+
+	opsN := 100
+	for i := 0; i < opsN; i++ {
+		assert.Nil(t, tree.Add(&api_v1.Operation{
+			Service:   "svc",
+			Operation: fmt.Sprintf("op_%d", i),
+		}))
+	}
+	assert.Nil(t, tree.Promote(&api_v1.Operation{Service: "svc", Operation: "op_0"}, 2))
+
+	root := tree.Dump()
+	assert.Equal(t, opsN, root.LeafCount)
+	assert.Equal(t, 0, root.Depth)
+	assert.Equal(t, 1.0, root.SamplingRate)
+
+	leaves := 0
+	tree.Walk(func(node *NodeInfo) bool {
+		for i, c := range node.Children {
+			assert.Equal(t, i, c.LruPosition)
+			assert.Equal(t, node.Depth+1, c.Depth)
+		}
+		if node.IsLeaf {
+			leaves++
+			sr, err := tree.Generate(&api_v1.Operation{Service: node.Service, Operation: node.Operation})
+			assert.Nil(t, err)
+			assert.Equal(t, sr, node.SamplingRate)
+		}
+		return true
+	})
+	assert.Equal(t, opsN, leaves)
+
+	visited := 0
+	tree.Walk(func(node *NodeInfo) bool {
+		visited++
+		return false
+	})
+	assert.Equal(t, 1, visited)
+
+	dot := Dot(root)
+	assert.True(t, strings.HasPrefix(dot, "digraph sst {"))
+	assert.Contains(t, dot, "op_0")
+}
@@ -17,23 +17,52 @@ package sst
 import (
 	"flag"
 	"github.com/spf13/viper"
+	"time"
 )
 
 const (
-	order = "sampling.sst.order"
+	order            = "sampling.sst.order"
+	mode             = "sampling.sst.mode"
+	snapshotPath     = "sampling.sst.snapshot.path"
+	snapshotInterval = "sampling.sst.snapshot.interval"
+	halfLife         = "sampling.sst.half.life"
 
-	DefaultOrder = 4
+	DefaultOrder            = 4
+	DefaultMode             = ModeGlobal
+	DefaultSnapshotPath     = ""
+	DefaultSnapshotInterval = time.Minute
+	DefaultHalfLife         = time.Duration(0)
 )
 
 type Flags struct {
-	Order int
+	Order            int
+	Mode             string
+	SnapshotPath     string
+	SnapshotInterval time.Duration
+	HalfLife         time.Duration
 }
 
 func AddFlags(flags *flag.FlagSet) {
 	flags.Int(order, DefaultOrder, "[Sampling] Order of sampling strategy tree.")
+	flags.String(mode, DefaultMode,
+		"[Sampling] Mode of sampling strategy tree, global or service. All operations are put into one tree in "+
+			"global mode, while sampling rates are fairly shared by services at first and then by operations of "+
+			"each service in service mode.")
+	flags.String(snapshotPath, DefaultSnapshotPath,
+		"[Sampling] File to save snapshots of sampling strategy tree. Snapshots are disabled if it is empty.")
+	flags.Duration(snapshotInterval, DefaultSnapshotInterval,
+		"[Sampling] Interval for taking snapshots of sampling strategy tree. Only the last snapshot is taken on exit "+
+			"if it is not positive.")
+	flags.Duration(halfLife, DefaultHalfLife,
+		"[Sampling] Period after which one step of promotion of an operation would be taken back if it has not been "+
+			"promoted again. Decay of promotions is disabled if it is 0.")
 }
 
 func (f *Flags) InitFromViper(v *viper.Viper) *Flags {
 	f.Order = v.GetInt(order)
+	f.Mode = v.GetString(mode)
+	f.SnapshotPath = v.GetString(snapshotPath)
+	f.SnapshotInterval = v.GetDuration(snapshotInterval)
+	f.HalfLife = v.GetDuration(halfLife)
 	return f
 }
@@ -0,0 +1,76 @@
+// Copyright (c) 2021 The Houyi Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build go1.18
+// +build go1.18
+
+package sst
+
+import (
+	"fmt"
+	"github.com/houyi-tracing/houyi/idl/api_v1"
+	"testing"
+)
+
+const (
+	fuzzOpsN = 32
+)
+
+// FuzzOperationSequence interprets every 2 bytes of input as an operation (Add, Promote, Demote, Prune, Decay or
+// Restore from snapshot) and its target, and validates invariants of trees after every step.
+func FuzzOperationSequence(f *testing.F) {
+	f.Add(uint8(2), []byte{0, 1, 0, 2, 0, 3, 0, 4, 1, 4, 2, 1, 3, 2})
+	f.Add(uint8(4), []byte{0, 0, 0, 1, 0, 2, 0, 3, 0, 4, 0, 5, 1, 5, 1, 5, 4, 0, 5, 0, 3, 5})
+	f.Add(uint8(3), []byte{0, 7, 0, 8, 0, 9, 0, 10, 1, 10, 2, 10, 2, 10, 3, 9, 3, 8})
+
+	f.Fuzz(func(t *testing.T, order uint8, seq []byte) {
+		maxN := int(order%6) + 2
+		trees := []SamplingStrategyTree{NewSamplingStrategyTree(maxN), NewServiceGroupedTree(maxN)}
+
+		for _, tree := range trees {
+			for i := 0; i+1 < len(seq); i += 2 {
+				target := int(seq[i+1]) % fuzzOpsN
+				op := &api_v1.Operation{
+					Service:   fmt.Sprintf("svc_%d", target%3),
+					Operation: fmt.Sprintf("op_%d", target),
+				}
+
+				switch seq[i] % 6 {
+				case 0:
+					_ = tree.Add(op)
+				case 1:
+					_ = tree.Promote(op, int(seq[i+1]%4))
+				case 2:
+					_ = tree.Demote(op)
+				case 3:
+					_ = tree.Prune(op)
+				case 4:
+					tree.Decay(0)
+				case 5:
+					data, err := tree.Snapshot()
+					if err != nil {
+						t.Fatal(err)
+					}
+					if err = tree.Restore(data); err != nil {
+						t.Fatal(err)
+					}
+				}
+
+				if err := tree.Validate(); err != nil {
+					t.Fatalf("step %d: %v", i/2, err)
+				}
+			}
+		}
+	})
+}
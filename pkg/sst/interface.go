@@ -15,7 +15,24 @@
 // Sampling strategy tree.
 package sst
 
-import "github.com/houyi-tracing/houyi/idl/api_v1"
+import (
+	"github.com/houyi-tracing/houyi/idl/api_v1"
+	"time"
+)
+
+// NodeInfo describes a node in sampling strategy tree. Branch nodes have no service and operation.
+type NodeInfo struct {
+	Service   string `json:"service,omitempty"`
+	Operation string `json:"operation,omitempty"`
+	IsLeaf    bool   `json:"isLeaf"`
+	Depth     int    `json:"depth"`
+	LeafCount int    `json:"leafCount"`
+	// LruPosition is the position of this node among its siblings and 0 means the most recently used one.
+	LruPosition  int         `json:"lruPosition"`
+	SamplingRate float64     `json:"samplingRate"`
+	Boost        int         `json:"boost,omitempty"`
+	Children     []*NodeInfo `json:"children,omitempty"`
+}
 
 type SamplingStrategyTree interface {
 	// Add adds a new operation into this tree.
@@ -28,8 +45,34 @@ type SamplingStrategyTree interface {
 	Generate(op *api_v1.Operation) (float64, error)
 
 	// Promote promotes a operation in this tree. As a result, the sampling rate of inputted operation will increase.
-	Promote(op *api_v1.Operation) error
+	// Weight is the number of levels to move the operation up, and weights less than 1 are treated as 1.
+	Promote(op *api_v1.Operation, weight int) error
+
+	// Demote demotes a operation in this tree. As a result, the sampling rate of inputted operation will decrease.
+	Demote(op *api_v1.Operation) error
+
+	// Decay demotes operations which have not been promoted for longer than halfLife once for each of them, until
+	// all their promotions have been taken back.
+	Decay(halfLife time.Duration)
 
 	// Prune removes inputted operation from this tree.
 	Prune(op *api_v1.Operation) error
+
+	// Operations returns all operations in this tree.
+	Operations() []*api_v1.Operation
+
+	// Dump returns a copy of this tree as nested nodes, starting from root.
+	Dump() *NodeInfo
+
+	// Walk visits all nodes of a copy of this tree in pre-order. Children are not visited if visit returns false.
+	Walk(visit func(node *NodeInfo) bool)
+
+	// Validate checks structural invariants of this tree and returns the first violation found.
+	Validate() error
+
+	// Snapshot serializes the full shape of this tree, including the LRU order of child nodes of every node.
+	Snapshot() ([]byte, error)
+
+	// Restore replaces this tree with the one serialized by Snapshot.
+	Restore(data []byte) error
 }
@@ -14,7 +14,11 @@
 
 package sst
 
-import "github.com/houyi-tracing/houyi/idl/api_v1"
+import (
+	"github.com/houyi-tracing/houyi/idl/api_v1"
+	"sync/atomic"
+	"time"
+)
 
 type treeNode struct {
 	op         *api_v1.Operation
@@ -22,6 +26,11 @@ type treeNode struct {
 	leafCnt    int
 	parent     *treeNode
 	childNodes *nodeSet
+	boost      int       // number of promotions which have not been demoted yet
+	promotedAt time.Time // time of the last promotion or decay
+
+	// up mirrors parent for readers which do not hold the lock of tree. It must be set by setParent.
+	up atomic.Value
 }
 
 func newRoot(maxN int) *treeNode {
@@ -35,23 +44,36 @@ func newRoot(maxN int) *treeNode {
 }
 
 func newBranchNode(maxN int, parent *treeNode) *treeNode {
-	return &treeNode{
+	n := &treeNode{
 		op:         &api_v1.Operation{},
 		maxN:       maxN,
 		leafCnt:    0,
-		parent:     parent,
 		childNodes: newNodeSet(maxN),
 	}
+	n.setParent(parent)
+	return n
 }
 
 func newLeafNode(maxN int, parent *treeNode, op *api_v1.Operation) *treeNode {
-	return &treeNode{
+	n := &treeNode{
 		op:         op,
 		maxN:       maxN,
 		leafCnt:    1,
-		parent:     parent,
 		childNodes: nil,
 	}
+	n.setParent(parent)
+	return n
+}
+
+func (n *treeNode) setParent(parent *treeNode) {
+	n.parent = parent
+	n.up.Store(parent)
+}
+
+// loadParent returns the parent of n without the lock of tree.
+func (n *treeNode) loadParent() *treeNode {
+	parent, _ := n.up.Load().(*treeNode)
+	return parent
 }
 
 func (n *treeNode) addChild(child *treeNode) {
@@ -60,7 +82,7 @@ func (n *treeNode) addChild(child *treeNode) {
 	} else {
 		if n.hasRoom() {
 			n.childNodes.add(child)
-			child.parent = n
+			child.setParent(n)
 		} else {
 			next := findNext(n.childNodes.all())
 			next.addChild(child)
@@ -114,7 +136,7 @@ func (n *treeNode) shrink() {
 		parent := n.parent
 		parent.childNodes.remove(n)
 		parent.childNodes.add(onlyChild)
-		onlyChild.parent = parent
+		onlyChild.setParent(parent)
 	}
 }
 
@@ -122,8 +144,8 @@ func (n *treeNode) splitSelfAndMerge(other *treeNode) {
 	grandParent := n.parent
 	parent := newBranchNode(n.maxN, grandParent)
 
-	n.parent = parent
-	other.parent = parent
+	n.setParent(parent)
+	other.setParent(parent)
 	parent.childNodes.add(n)
 	parent.childNodes.add(other) // to make OTHER newer, we should add it after adding N.
 
@@ -175,6 +197,9 @@ type nodeSet struct {
 	head     *doubleListNode
 	tail     *doubleListNode
 	itemMap  map[*treeNode]*doubleListNode
+
+	// width mirrors the size of this nodeSet for readers which do not hold the lock of tree.
+	width int32
 }
 
 // newNodeSet returns a new nodeSet pointer.
@@ -194,6 +219,16 @@ func (s *nodeSet) size() int {
 	return len(s.itemMap)
 }
 
+// loadSize returns the size of this cache without the lock of tree.
+func (s *nodeSet) loadSize() int {
+	return int(atomic.LoadInt32(&s.width))
+}
+
+// syncSize updates the size mirrored for readers which do not hold the lock of tree.
+func (s *nodeSet) syncSize() {
+	atomic.StoreInt32(&s.width, int32(len(s.itemMap)))
+}
+
 // cap returns the capacity of this cache.
 func (s *nodeSet) cap() int {
 	return s.capacity
@@ -205,6 +240,7 @@ func (s *nodeSet) resize(capacity int) {
 		delete(s.itemMap, removeTail(s.head, s.tail).val)
 	}
 	s.capacity = capacity
+	s.syncSize()
 }
 
 // clear clears this cache.
@@ -212,6 +248,7 @@ func (s *nodeSet) clear() {
 	s.head.right = s.tail
 	s.tail.left = s.head
 	s.itemMap = make(map[*treeNode]*doubleListNode)
+	s.syncSize()
 }
 
 // has returns true if k already exist in this cache, else false.
@@ -226,6 +263,8 @@ func (s *nodeSet) has(tn *treeNode) bool {
 // add adds a new val into this nodeSet.
 // If the size of this nodeSet exceed capacity after this operation, the Cache val would be removed and be returned.
 func (s *nodeSet) add(tn *treeNode) interface{} {
+	defer s.syncSize()
+
 	if nPtr, has := s.itemMap[tn]; has {
 		moveToHead(s.head, nPtr)
 	} else {
@@ -251,6 +290,7 @@ func (s *nodeSet) remove(tn *treeNode) {
 	if nPtr, has := s.itemMap[tn]; has {
 		removeNode(nPtr)
 		delete(s.itemMap, tn)
+		s.syncSize()
 	}
 }
 
@@ -0,0 +1,260 @@
+// Copyright (c) 2021 The Houyi Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sst
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/houyi-tracing/houyi/idl/api_v1"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// ModeGlobal puts all operations into a single tree.
+	ModeGlobal = "global"
+
+	// ModeService partitions the first level of tree by service. Every service has the same share of sampling rates
+	// no matter how many operations it has, and the share is divided among its operations by a tree of its own.
+	ModeService = "service"
+)
+
+// serviceTree is a sampling strategy tree whose first level is partitioned by service. Like sst, Generate does not
+// take the lock but reads the immutable copy of services published by the last writer which adds or removes a
+// service.
+type serviceTree struct {
+	sync.RWMutex
+
+	maxN     int
+	services map[string]*sst
+
+	// published holds a copy of services which must not be modified.
+	published atomic.Value
+}
+
+// NewTree returns a sampling strategy tree of inputted mode.
+func NewTree(mode string, maxN int) (SamplingStrategyTree, error) {
+	switch mode {
+	case ModeGlobal, "":
+		return NewSamplingStrategyTree(maxN), nil
+	case ModeService:
+		return NewServiceGroupedTree(maxN), nil
+	default:
+		return nil, fmt.Errorf("unknown mode of sampling strategy tree: %s", mode)
+	}
+}
+
+func NewServiceGroupedTree(maxN int) SamplingStrategyTree {
+	t := &serviceTree{
+		maxN:     maxN,
+		services: make(map[string]*sst),
+	}
+	t.publish()
+	return t
+}
+
+func (t *serviceTree) Add(op *api_v1.Operation) error {
+	t.Lock()
+	defer t.Unlock()
+
+	sub, has := t.services[op.GetService()]
+	if !has {
+		sub = NewSamplingStrategyTree(t.maxN).(*sst)
+		if err := sub.Add(op); err != nil {
+			return err
+		}
+		t.services[op.GetService()] = sub
+		t.publish()
+		return nil
+	}
+	return sub.Add(op)
+}
+
+func (t *serviceTree) Has(op *api_v1.Operation) bool {
+	t.RLock()
+	defer t.RUnlock()
+
+	if sub, has := t.services[op.GetService()]; has {
+		return sub.Has(op)
+	}
+	return false
+}
+
+func (t *serviceTree) Generate(op *api_v1.Operation) (float64, error) {
+	services := t.published.Load().(map[string]*sst)
+	if sub, has := services[op.GetService()]; has {
+		sr, err := sub.Generate(op)
+		if err != nil {
+			return 0.0, err
+		}
+		return sr / float64(len(services)), nil
+	}
+	return 0.0, fmt.Errorf(notExistErr)
+}
+
+func (t *serviceTree) Promote(op *api_v1.Operation, weight int) error {
+	t.RLock()
+	defer t.RUnlock()
+
+	if sub, has := t.services[op.GetService()]; has {
+		return sub.Promote(op, weight)
+	}
+	return fmt.Errorf(notExistErr)
+}
+
+func (t *serviceTree) Demote(op *api_v1.Operation) error {
+	t.RLock()
+	defer t.RUnlock()
+
+	if sub, has := t.services[op.GetService()]; has {
+		return sub.Demote(op)
+	}
+	return fmt.Errorf(notExistErr)
+}
+
+func (t *serviceTree) Decay(halfLife time.Duration) {
+	t.RLock()
+	defer t.RUnlock()
+
+	for _, sub := range t.services {
+		sub.Decay(halfLife)
+	}
+}
+
+func (t *serviceTree) Prune(op *api_v1.Operation) error {
+	t.Lock()
+	defer t.Unlock()
+
+	svc := op.GetService()
+	if sub, has := t.services[svc]; has {
+		if err := sub.Prune(op); err != nil {
+			return err
+		}
+		if sub.size() == 0 {
+			delete(t.services, svc)
+			t.publish()
+		}
+		return nil
+	}
+	return fmt.Errorf(notExistErr)
+}
+
+func (t *serviceTree) Operations() []*api_v1.Operation {
+	t.RLock()
+	defer t.RUnlock()
+
+	ret := make([]*api_v1.Operation, 0)
+	for _, sub := range t.services {
+		ret = append(ret, sub.Operations()...)
+	}
+	return ret
+}
+
+// Dump returns a root whose children are subtrees of services ordered by service name. Service and LeafCount of
+// those children are set, and LruPosition of them is their position in this order.
+func (t *serviceTree) Dump() *NodeInfo {
+	t.RLock()
+	defer t.RUnlock()
+
+	services := t.serviceNames()
+	root := &NodeInfo{
+		SamplingRate: 1.0,
+		Children:     make([]*NodeInfo, 0, len(services)),
+	}
+	for i, svc := range services {
+		sub := t.services[svc]
+		sub.RLock()
+		info := dumpNode(sub.root, 1, i, 1.0/float64(len(services)))
+		sub.RUnlock()
+
+		info.Service = svc
+		root.LeafCount += info.LeafCount
+		root.Children = append(root.Children, info)
+	}
+	return root
+}
+
+func (t *serviceTree) Walk(visit func(node *NodeInfo) bool) {
+	walk(t.Dump(), visit)
+}
+
+func (t *serviceTree) Snapshot() ([]byte, error) {
+	t.RLock()
+	snapshot := &treeSnapshot{
+		Version:  SnapshotVersion,
+		Mode:     ModeService,
+		Order:    t.maxN,
+		Services: make(map[string]*nodeSnapshot, len(t.services)),
+	}
+	for svc, sub := range t.services {
+		sub.RLock()
+		snapshot.Services[svc] = snapshotNode(sub.root)
+		sub.RUnlock()
+	}
+	t.RUnlock()
+
+	return json.Marshal(snapshot)
+}
+
+func (t *serviceTree) Restore(data []byte) error {
+	snapshot, err := parseSnapshot(data, ModeService, t.maxN)
+	if err != nil {
+		return err
+	}
+
+	services := make(map[string]*sst, len(snapshot.Services))
+	for svc, root := range snapshot.Services {
+		if root == nil || root.Leaf {
+			return fmt.Errorf("invalid root of service %s in snapshot", svc)
+		}
+		sub := NewSamplingStrategyTree(t.maxN).(*sst)
+		if err = restoreChildren(sub.root, root.Children, sub.nodes, sub.boosted); err != nil {
+			return err
+		}
+		sub.indexLeaves()
+		if err = sub.validateSubtree(svc); err != nil {
+			return fmt.Errorf("invalid subtree of service %s in snapshot: %w", svc, err)
+		}
+		services[svc] = sub
+	}
+
+	t.Lock()
+	defer t.Unlock()
+
+	t.services = services
+	t.publish()
+	return nil
+}
+
+// publish publishes a copy of services for Generate. It must be called with the write lock held after adding or
+// removing a service.
+func (t *serviceTree) publish() {
+	services := make(map[string]*sst, len(t.services))
+	for svc, sub := range t.services {
+		services[svc] = sub
+	}
+	t.published.Store(services)
+}
+
+func (t *serviceTree) serviceNames() []string {
+	ret := make([]string, 0, len(t.services))
+	for svc := range t.services {
+		ret = append(ret, svc)
+	}
+	sort.Strings(ret)
+	return ret
+}
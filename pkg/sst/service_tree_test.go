@@ -0,0 +1,87 @@
+// Copyright (c) 2021 The Houyi Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sst
+
+import (
+	"fmt"
+	"github.com/houyi-tracing/houyi/idl/api_v1"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+)
+
+func TestServicesMustShareSamplingRatesFairly(t *testing.T) {
+	tree, err := NewTree(ModeService, maxN)
+	assert.Nil(t, err)
+
+	// a big service with many operations and a small service with only one operation.
+	for i := 0; i < 100; i++ {
+		assert.Nil(t, tree.Add(&api_v1.Operation{Service: "big", Operation: fmt.Sprintf("op_%d", i)}))
+	}
+	small := &api_v1.Operation{Service: "small", Operation: "op"}
+	assert.Nil(t, tree.Add(small))
+
+	sr, err := tree.Generate(small)
+	assert.Nil(t, err)
+	assert.Equal(t, 0.5, sr)
+
+	sum := 0.0
+	for _, op := range tree.Operations() {
+		sr, err := tree.Generate(op)
+		assert.Nil(t, err)
+		sum += sr
+	}
+	assert.Less(t, math.Abs(1.0-sum), 1e-10)
+
+	root := tree.Dump()
+	assert.Equal(t, 101, root.LeafCount)
+	assert.Equal(t, "big", root.Children[0].Service)
+	assert.Equal(t, "small", root.Children[1].Service)
+
+	assert.Nil(t, tree.Prune(small))
+	assert.False(t, tree.Has(small))
+	assert.Len(t, tree.Dump().Children, 1)
+}
+
+func TestServiceGroupedTreeMustBeRestored(t *testing.T) {
+	tree := NewServiceGroupedTree(maxN)
+	ops := make([]*api_v1.Operation, 0)
+	for i := 0; i < 50; i++ {
+		op := &api_v1.Operation{Service: fmt.Sprintf("svc_%d", i%3), Operation: fmt.Sprintf("op_%d", i)}
+		assert.Nil(t, tree.Add(op))
+		ops = append(ops, op)
+	}
+	assert.Nil(t, tree.Promote(ops[10], 2))
+
+	data, err := tree.Snapshot()
+	assert.Nil(t, err)
+
+	restored := NewServiceGroupedTree(maxN)
+	assert.Nil(t, restored.Restore(data))
+	for _, op := range ops {
+		expected, _ := tree.Generate(op)
+		actual, err := restored.Generate(op)
+		assert.Nil(t, err)
+		assert.Equal(t, expected, actual)
+	}
+
+	// snapshots of different modes must not be mixed up.
+	assert.Error(t, NewSamplingStrategyTree(maxN).Restore(data))
+	globalData, _ := NewSamplingStrategyTree(maxN).Snapshot()
+	assert.Error(t, restored.Restore(globalData))
+
+	_, err = NewTree("unknown", maxN)
+	assert.Error(t, err)
+}
@@ -0,0 +1,168 @@
+// Copyright (c) 2021 The Houyi Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sst
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/houyi-tracing/houyi/idl/api_v1"
+	"time"
+)
+
+const (
+	// SnapshotVersion is the version of format of snapshots. It must be increased once the format changes.
+	SnapshotVersion = 1
+)
+
+type treeSnapshot struct {
+	Version int    `json:"version"`
+	Mode    string `json:"mode,omitempty"` // empty means ModeGlobal
+	Order   int    `json:"order"`
+	// Root is set in ModeGlobal and Services is set in ModeService.
+	Root     *nodeSnapshot            `json:"root,omitempty"`
+	Services map[string]*nodeSnapshot `json:"services,omitempty"`
+}
+
+// nodeSnapshot is the serialized form of a tree node. Children are ordered from the most recently used one to the
+// least recently used one, which is the same as the order of nodeSet.
+type nodeSnapshot struct {
+	Leaf       bool            `json:"leaf,omitempty"`
+	Service    string          `json:"service,omitempty"`
+	Operation  string          `json:"operation,omitempty"`
+	Boost      int             `json:"boost,omitempty"`
+	PromotedAt int64           `json:"promotedAt,omitempty"` // in Unix nanoseconds
+	Children   []*nodeSnapshot `json:"children,omitempty"`
+}
+
+func (t *sst) Snapshot() ([]byte, error) {
+	t.RLock()
+	root := snapshotNode(t.root)
+	t.RUnlock()
+
+	return json.Marshal(&treeSnapshot{
+		Version: SnapshotVersion,
+		Order:   t.maxN,
+		Root:    root,
+	})
+}
+
+func (t *sst) Restore(data []byte) error {
+	snapshot, err := parseSnapshot(data, ModeGlobal, t.maxN)
+	if err != nil {
+		return err
+	}
+	if snapshot.Root == nil || snapshot.Root.Leaf {
+		return fmt.Errorf("invalid root of snapshot")
+	}
+
+	// The snapshot is restored into a temporary tree which replaces this tree only if it is valid.
+	tmp := NewSamplingStrategyTree(t.maxN).(*sst)
+	if err = restoreChildren(tmp.root, snapshot.Root.Children, tmp.nodes, tmp.boosted); err != nil {
+		return err
+	}
+	tmp.indexLeaves()
+	if err = tmp.validate(); err != nil {
+		return fmt.Errorf("invalid snapshot: %w", err)
+	}
+
+	t.Lock()
+	defer t.Unlock()
+	t.beginWrite()
+	defer t.endWrite()
+
+	t.root, t.nodes, t.boosted = tmp.root, tmp.nodes, tmp.boosted
+	t.leaves.Store(tmp.loadLeaves())
+	return nil
+}
+
+// parseSnapshot parses a snapshot and checks whether it could be restored into a tree of inputted mode and order.
+func parseSnapshot(data []byte, mode string, order int) (*treeSnapshot, error) {
+	snapshot := &treeSnapshot{}
+	if err := json.Unmarshal(data, snapshot); err != nil {
+		return nil, err
+	}
+	if snapshot.Version != SnapshotVersion {
+		return nil, fmt.Errorf("unsupported snapshot version: %d", snapshot.Version)
+	}
+	if snapshot.Mode == "" {
+		snapshot.Mode = ModeGlobal
+	}
+	if snapshot.Mode != mode {
+		return nil, fmt.Errorf("mode of snapshot %s does not match mode of tree %s", snapshot.Mode, mode)
+	}
+	if snapshot.Order != order {
+		return nil, fmt.Errorf("order of snapshot %d does not match order of tree %d", snapshot.Order, order)
+	}
+	return snapshot, nil
+}
+
+func snapshotNode(n *treeNode) *nodeSnapshot {
+	if n.isLeaf() {
+		ret := &nodeSnapshot{
+			Leaf:      true,
+			Service:   n.op.GetService(),
+			Operation: n.op.GetOperation(),
+			Boost:     n.boost,
+		}
+		if n.boost > 0 {
+			ret.PromotedAt = n.promotedAt.UnixNano()
+		}
+		return ret
+	}
+	ret := &nodeSnapshot{
+		Children: make([]*nodeSnapshot, 0, n.childN()),
+	}
+	for _, c := range n.childNodes.all() {
+		ret.Children = append(ret.Children, snapshotNode(c))
+	}
+	return ret
+}
+
+// restoreChildren rebuilds child nodes of parent. Children are added from the least recently used one because
+// nodeSet always adds a node as the newest one.
+func restoreChildren(parent *treeNode, children []*nodeSnapshot, nodes nodeMap, boosted map[*treeNode]struct{}) error {
+	if len(children) > parent.maxN {
+		return fmt.Errorf("number of child nodes %d exceeds order of tree %d", len(children), parent.maxN)
+	}
+	for i := len(children) - 1; i >= 0; i-- {
+		c := children[i]
+		var child *treeNode
+		if c.Leaf {
+			if nodes.has(c.Service, c.Operation) {
+				return fmt.Errorf("duplicate operation in snapshot: %s %s", c.Service, c.Operation)
+			}
+			child = newLeafNode(parent.maxN, parent, &api_v1.Operation{
+				Service:   c.Service,
+				Operation: c.Operation,
+			})
+			nodes.add(c.Service, c.Operation, child)
+			if c.Boost > 0 {
+				child.boost, child.promotedAt = c.Boost, time.Unix(0, c.PromotedAt)
+				boosted[child] = struct{}{}
+			}
+		} else {
+			if len(c.Children) == 0 {
+				return fmt.Errorf("branch node without child nodes in snapshot")
+			}
+			child = newBranchNode(parent.maxN, parent)
+			if err := restoreChildren(child, c.Children, nodes, boosted); err != nil {
+				return err
+			}
+		}
+		parent.childNodes.add(child)
+		parent.leafCnt += child.leafCnt
+	}
+	return nil
+}
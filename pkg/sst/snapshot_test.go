@@ -0,0 +1,111 @@
+// Copyright (c) 2021 The Houyi Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sst
+
+import (
+	"fmt"
+	"github.com/houyi-tracing/houyi/idl/api_v1"
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"testing"
+)
+
+func TestRestoredTreeMustBeIdentical(t *testing.T) {
+	tree := NewSamplingStrategyTree(maxN)
+
+	opsN := 1000
+	ops := make([]*api_v1.Operation, 0, opsN)
+	for i := 0; i < opsN; i++ {
+		op := &api_v1.Operation{
+			Service:   fmt.Sprintf("svc_%d", i%10),
+			Operation: fmt.Sprintf("op_%d", i),
+		}
+		assert.Nil(t, tree.Add(op))
+		ops = append(ops, op)
+	}
+	for i := 0; i < opsN*10; i++ {
+		assert.Nil(t, tree.Promote(ops[rand.Intn(opsN)], 1))
+	}
+
+	data, err := tree.Snapshot()
+	assert.Nil(t, err)
+
+	restored := NewSamplingStrategyTree(maxN)
+	assert.Nil(t, restored.Restore(data))
+	root := restored.(*sst).root
+	check(root, root, t)
+
+	restoredData, err := restored.Snapshot()
+	assert.Nil(t, err)
+	assert.Equal(t, data, restoredData)
+
+	for _, op := range ops {
+		expected, err := tree.Generate(op)
+		assert.Nil(t, err)
+		actual, err := restored.Generate(op)
+		assert.Nil(t, err)
+		assert.Equal(t, expected, actual)
+	}
+
+	// both trees must evolve in the same way after restoring.
+	for i := 0; i < opsN; i++ {
+		op := ops[rand.Intn(opsN)]
+		assert.Nil(t, tree.Promote(op, 1))
+		assert.Nil(t, restored.Promote(op, 1))
+	}
+	for _, op := range ops {
+		expected, _ := tree.Generate(op)
+		actual, _ := restored.Generate(op)
+		assert.Equal(t, expected, actual)
+	}
+}
+
+func TestRestoreMustRejectMismatchedOrder(t *testing.T) {
+	tree := NewSamplingStrategyTree(maxN)
+	assert.Nil(t, tree.Add(&api_v1.Operation{Service: "svc", Operation: "op"}))
+
+	data, err := tree.Snapshot()
+	assert.Nil(t, err)
+
+	other := NewSamplingStrategyTree(maxN + 1)
+	assert.Error(t, other.Restore(data))
+	assert.Error(t, other.Restore([]byte(`{"version":0}`)))
+}
+
+func TestRestoreMustRejectInvalidSnapshot(t *testing.T) {
+	op := &api_v1.Operation{Service: "svc", Operation: "op"}
+
+	tree := NewSamplingStrategyTree(maxN)
+	assert.Nil(t, tree.Add(op))
+	// branch with only one child node
+	data := fmt.Sprintf(`{"version":%d,"order":%d,"root":{"children":[{"children":[`+
+		`{"leaf":true,"service":"svc","operation":"other"}]}]}}`, SnapshotVersion, maxN)
+	assert.Error(t, tree.Restore([]byte(data)))
+	assert.True(t, tree.Has(op))
+	assert.Nil(t, tree.Validate())
+
+	serviceTree := NewServiceGroupedTree(maxN)
+	assert.Nil(t, serviceTree.Add(op))
+	// subtree of service svc holding operation of another service
+	data = fmt.Sprintf(`{"version":%d,"mode":"%s","order":%d,"services":{"svc":{"children":[`+
+		`{"leaf":true,"service":"other","operation":"op"}]}}}`, SnapshotVersion, ModeService, maxN)
+	assert.Error(t, serviceTree.Restore([]byte(data)))
+	// empty subtree
+	data = fmt.Sprintf(`{"version":%d,"mode":"%s","order":%d,"services":{"svc":{}}}`,
+		SnapshotVersion, ModeService, maxN)
+	assert.Error(t, serviceTree.Restore([]byte(data)))
+	assert.True(t, serviceTree.Has(op))
+	assert.Nil(t, serviceTree.Validate())
+}
@@ -17,6 +17,10 @@ package sst
 import (
 	"fmt"
 	"github.com/houyi-tracing/houyi/idl/api_v1"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
 const (
@@ -24,26 +28,62 @@ const (
 	alreadyExistErr = "operation already exist"
 )
 
+// sst is guarded by a read-write lock, except for Generate which is on the hot path of pulling strategies and never
+// takes the lock, so that it does not wait for Promote, Demote, Decay or Prune. Generate finds leaves by an index
+// which is safe for concurrent use and walks up to root through the parents and numbers of child nodes mirrored in
+// atomic fields. Writers increase version before and after every modification like a sequence lock, and Generate
+// retries if the tree has been modified during its walk.
 type sst struct {
+	sync.RWMutex
+
 	root  *treeNode
 	nodes nodeMap
 	maxN  int
+
+	// boosted contains leaf nodes which have been promoted and not been demoted back yet.
+	boosted map[*treeNode]struct{}
+
+	// leaves holds a *sync.Map from leafKey to leaf nodes for Generate.
+	leaves atomic.Value
+	// version is odd while the tree is being modified.
+	version uint64
+}
+
+type leafKey struct {
+	service   string
+	operation string
 }
 
+const (
+	// maxGenerateRetries bounds the retries of Generate while the tree keeps being modified. After that, Generate
+	// returns the sampling rate it computed last time, which may be slightly inaccurate but is still in (0, 1].
+	maxGenerateRetries = 16
+)
+
 func NewSamplingStrategyTree(maxN int) SamplingStrategyTree {
-	return &sst{
-		root:  newRoot(maxN),
-		nodes: newNodeMap(),
-		maxN:  maxN,
+	t := &sst{
+		root:    newRoot(maxN),
+		nodes:   newNodeMap(),
+		maxN:    maxN,
+		boosted: make(map[*treeNode]struct{}),
 	}
+	t.leaves.Store(&sync.Map{})
+	return t
 }
 
 func (t *sst) Add(op *api_v1.Operation) error {
+	t.Lock()
+	defer t.Unlock()
+
 	if !t.hasOp(op) {
+		t.beginWrite()
+		defer t.endWrite()
+
 		svcName, opName := op.GetService(), op.GetOperation()
 		newNode := newLeafNode(t.maxN, nil, op)
 		t.nodes.add(svcName, opName, newNode)
 		t.root.addChild(newNode)
+		t.loadLeaves().Store(leafKey{service: svcName, operation: opName}, newNode)
 		return nil
 	} else {
 		return fmt.Errorf(alreadyExistErr)
@@ -51,40 +91,132 @@ func (t *sst) Add(op *api_v1.Operation) error {
 }
 
 func (t *sst) Has(op *api_v1.Operation) bool {
+	t.RLock()
+	defer t.RUnlock()
+
 	return t.hasOp(op)
 }
 
-func (t *sst) Promote(op *api_v1.Operation) error {
+func (t *sst) Promote(op *api_v1.Operation, weight int) error {
+	t.Lock()
+	defer t.Unlock()
+
 	if t.hasOp(op) {
+		t.beginWrite()
+		defer t.endWrite()
+
 		node := t.getNode(op)
-		if node.parent == t.root {
-			t.root.childNodes.upToDate(node)
-			return nil
+		if weight < 1 {
+			weight = 1
+		}
+		// Every step moves node up a level until it becomes a child of root.
+		for i := 0; i < weight; i++ {
+			if node.parent == t.root {
+				t.root.childNodes.upToDate(node)
+				if node.boost > 0 {
+					node.promotedAt = time.Now()
+				}
+				return nil
+			}
+			grandParent, parent := node.parent.parent, node.parent
+			t.promote(grandParent, parent, node)
+			node.boost++
+			node.promotedAt = time.Now()
+			t.boosted[node] = struct{}{}
 		}
-		grandParent, parent := node.parent.parent, node.parent
-		t.promote(grandParent, parent, node)
 		return nil
 	} else {
 		return fmt.Errorf(notExistErr)
 	}
 }
 
-func (t *sst) Generate(op *api_v1.Operation) (float64, error) {
+func (t *sst) Demote(op *api_v1.Operation) error {
+	t.Lock()
+	defer t.Unlock()
+
 	if t.hasOp(op) {
+		t.beginWrite()
+		defer t.endWrite()
+
 		node := t.getNode(op)
-		sr, parent := 1.0, node.parent
-		for parent != nil {
-			sr *= 1.0 / float64(parent.childNodes.size())
-			parent = parent.parent
+		if t.demote(node) && node.boost > 0 {
+			node.boost--
+			if node.boost == 0 {
+				delete(t.boosted, node)
+			}
 		}
-		return sr, nil
+		return nil
 	} else {
+		return fmt.Errorf(notExistErr)
+	}
+}
+
+func (t *sst) Decay(halfLife time.Duration) {
+	t.Lock()
+	defer t.Unlock()
+	t.beginWrite()
+	defer t.endWrite()
+
+	now := time.Now()
+	for node := range t.boosted {
+		// Each half-life elapsed without promotion takes back one step of promotion.
+		if now.Sub(node.promotedAt) < halfLife {
+			continue
+		}
+		// A promotion is taken back only if node could actually be moved down, otherwise it is retried later.
+		if !t.demote(node) {
+			continue
+		}
+		node.boost--
+		node.promotedAt = now
+		if node.boost <= 0 {
+			node.boost = 0
+			delete(t.boosted, node)
+		}
+	}
+}
+
+func (t *sst) Generate(op *api_v1.Operation) (float64, error) {
+	key := leafKey{service: op.GetService(), operation: op.GetOperation()}
+	sr, has := 0.0, false
+	for i := 0; i < maxGenerateRetries; i++ {
+		version := atomic.LoadUint64(&t.version)
+		sr, has = t.generate(key)
+		if version%2 == 0 && atomic.LoadUint64(&t.version) == version {
+			break
+		}
+		runtime.Gosched()
+	}
+	if !has {
 		return 0.0, fmt.Errorf(notExistErr)
 	}
+	return sr, nil
+}
+
+// generate computes the sampling rate of leaf of key without the lock of tree. The result is consistent only if the
+// tree is not modified meanwhile.
+func (t *sst) generate(key leafKey) (float64, bool) {
+	leaf, has := t.loadLeaves().Load(key)
+	if !has {
+		return 0.0, false
+	}
+	sr := 1.0
+	for parent := leaf.(*treeNode).loadParent(); parent != nil; parent = parent.loadParent() {
+		if size := parent.childNodes.loadSize(); size > 0 {
+			sr /= float64(size)
+		}
+	}
+	return sr, true
 }
 
 func (t *sst) Prune(op *api_v1.Operation) error {
+	t.Lock()
+	defer t.Unlock()
+
 	if t.hasOp(op) {
+		t.beginWrite()
+		defer t.endWrite()
+
 		node := t.getNode(op)
 		currP := node.parent
 		currP.childNodes.remove(node)
@@ -99,12 +231,53 @@ func (t *sst) Prune(op *api_v1.Operation) error {
 			currP.shrink()
 		}
 		t.nodes.remove(op.GetService(), op.GetOperation())
+		t.loadLeaves().Delete(leafKey{service: op.GetService(), operation: op.GetOperation()})
+		delete(t.boosted, node)
 		return nil
 	} else {
 		return fmt.Errorf(notExistErr)
 	}
 }
 
+func (t *sst) Operations() []*api_v1.Operation {
+	t.RLock()
+	defer t.RUnlock()
+
+	return t.nodes.allOperations()
+}
+
+// size returns the number of operations in this tree.
+func (t *sst) size() int {
+	t.RLock()
+	defer t.RUnlock()
+
+	return t.root.leafCnt
+}
+
+// beginWrite and endWrite must enclose every modification of tree with the write lock held.
+func (t *sst) beginWrite() {
+	atomic.AddUint64(&t.version, 1)
+}
+
+func (t *sst) endWrite() {
+	atomic.AddUint64(&t.version, 1)
+}
+
+func (t *sst) loadLeaves() *sync.Map {
+	return t.leaves.Load().(*sync.Map)
+}
+
+// indexLeaves rebuilds the index of leaves from nodes.
+func (t *sst) indexLeaves() {
+	leaves := &sync.Map{}
+	for svc, ops := range t.nodes {
+		for op, node := range ops {
+			leaves.Store(leafKey{service: svc, operation: op}, node)
+		}
+	}
+	t.leaves.Store(leaves)
+}
+
 func (t *sst) hasOp(op *api_v1.Operation) bool {
 	return t.nodes.has(op.GetService(), op.GetOperation())
 }
@@ -119,7 +292,7 @@ func (t *sst) promote(gp, p, node *treeNode) {
 	if gp.hasRoom() {
 		gp.childNodes.add(node)
 
-		node.parent = gp
+		node.setParent(gp)
 		p.leafCnt -= node.leafCnt
 
 		p.shrink()
@@ -130,13 +303,37 @@ func (t *sst) promote(gp, p, node *treeNode) {
 			p.leafCnt -= node.leafCnt
 		} else {
 			gp.childNodes.remove(lruNode)
-			lruNode.parent = p
+			lruNode.setParent(p)
 
 			p.childNodes.add(lruNode)
 			gp.childNodes.add(node)
-			node.parent = gp
+			node.setParent(gp)
 
 			p.leafCnt = p.leafCnt - node.leafCnt + lruNode.leafCnt
 		}
 	}
 }
+
+// demote moves node into the subtree of its least recently used sibling, which lowers its sampling rate. It returns
+// false without modifying tree if node has no sibling, or if its only sibling is a leaf under a branch other than
+// root, because the new branch of node and sibling would just take the place of parent and node would keep its
+// sampling rate.
+func (t *sst) demote(node *treeNode) bool {
+	parent := node.parent
+	sibling := parent.lruChild(node)
+	if sibling == nil {
+		return false
+	}
+	if parent != t.root && parent.childN() == 2 && sibling.isLeaf() {
+		return false
+	}
+
+	// The number of leaves under parent does not change because node is still a descendant of it.
+	parent.childNodes.remove(node)
+	sibling.addChild(node)
+
+	if parent != t.root {
+		parent.shrink()
+	}
+	return true
+}
@@ -21,6 +21,7 @@ import (
 	"math"
 	"math/rand"
 	"testing"
+	"time"
 )
 
 const (
@@ -103,7 +104,7 @@ func TestSumOfSamplingRatesMustEqualToOne(t *testing.T) {
 	for _, op := range ops {
 		s, err := tree.Generate(op)
 		assert.Nil(t, err)
-		sum += s.SamplingRate
+		sum += s
 	}
 	absErr := 1e-10
 	assert.Less(t, math.Abs(1.0-sum), absErr)
@@ -133,16 +134,163 @@ func TestSamplingRateMustBeGreaterAfterPromoting(t *testing.T) {
 		if tree.Has(promoteOp) {
 			s, err := tree.Generate(promoteOp)
 			assert.Nil(t, err)
-			err = tree.Promote(promoteOp)
+			err = tree.Promote(promoteOp, 1)
 			assert.Nil(t, err)
 			newS, _ := tree.Generate(promoteOp)
-			assert.LessOrEqual(t, s.SamplingRate, newS.SamplingRate)
+			assert.LessOrEqual(t, s, newS)
 		}
 	}
 
 	check(root, root, t)
 }
 
+func TestHeavyPromotionMustMoveFurther(t *testing.T) {
+	light, heavy := NewSamplingStrategyTree(maxN), NewSamplingStrategyTree(maxN)
+
+	opsN := 1000
+	for i := 0; i < opsN; i++ {
+		op := &api_v1.Operation{
+			Service:   "",
+			Operation: fmt.Sprintf("%d", i),
+		}
+		assert.Nil(t, light.Add(op))
+		assert.Nil(t, heavy.Add(op))
+	}
+
+	op := &api_v1.Operation{Service: "", Operation: "0"}
+	assert.Nil(t, light.Promote(op, 1))
+	assert.Nil(t, heavy.Promote(op, 3))
+	lightS, _ := light.Generate(op)
+	heavyS, _ := heavy.Generate(op)
+	assert.Less(t, lightS, heavyS)
+
+	root := heavy.(*sst).root
+	check(root, root, t)
+	assert.Equal(t, 3, heavy.(*sst).getNode(op).boost)
+}
+
+func TestSamplingRateMustBeLessAfterDemoting(t *testing.T) {
+	tree := NewSamplingStrategyTree(maxN)
+
+	opsN := 1000
+	ops := make([]*api_v1.Operation, 0, opsN)
+	for i := 0; i < opsN; i++ {
+		op := &api_v1.Operation{
+			Service:   "",
+			Operation: fmt.Sprintf("%d", i),
+		}
+		assert.Nil(t, tree.Add(op))
+		ops = append(ops, op)
+	}
+
+	st := tree.(*sst)
+	root := st.root
+	demoted := 0
+	for i := 0; i < opsN*10; i++ {
+		op := ops[rand.Intn(opsN)]
+		s, err := tree.Generate(op)
+		assert.Nil(t, err)
+		st.Lock()
+		ok := st.demote(st.getNode(op))
+		st.Unlock()
+		newS, _ := tree.Generate(op)
+		if ok {
+			demoted++
+			assert.Less(t, newS, s)
+		} else {
+			assert.Equal(t, s, newS)
+		}
+	}
+	assert.Greater(t, demoted, 0)
+	check(root, root, t)
+
+	sum := 0.0
+	for _, op := range ops {
+		s, _ := tree.Generate(op)
+		sum += s
+	}
+	assert.Less(t, math.Abs(1.0-sum), 1e-10)
+}
+
+func TestDemotionMustNotConsumePromotionWithoutLoweringRate(t *testing.T) {
+	tree := NewSamplingStrategyTree(2)
+	ops := make([]*api_v1.Operation, 0, 3)
+	for i := 0; i < 3; i++ {
+		op := &api_v1.Operation{
+			Service:   "",
+			Operation: fmt.Sprintf("%d", i),
+		}
+		assert.Nil(t, tree.Add(op))
+		ops = append(ops, op)
+	}
+
+	// With order 2, one of the three operations is a child of root and the other two share a branch.
+	st := tree.(*sst)
+	var op *api_v1.Operation
+	for _, o := range ops {
+		if st.getNode(o).parent != st.root {
+			op = o
+			break
+		}
+	}
+	assert.NotNil(t, op)
+
+	node := st.getNode(op)
+	node.boost = 1
+	st.boosted[node] = struct{}{}
+
+	before, _ := tree.Generate(op)
+	assert.Nil(t, tree.Demote(op))
+	tree.Decay(0)
+	after, _ := tree.Generate(op)
+	assert.Equal(t, before, after)
+	assert.Equal(t, 1, node.boost)
+	assert.Nil(t, st.Validate())
+}
+
+func TestPromotionMustDecay(t *testing.T) {
+	tree := NewSamplingStrategyTree(maxN)
+
+	opsN := 100
+	ops := make([]*api_v1.Operation, 0, opsN)
+	for i := 0; i < opsN; i++ {
+		op := &api_v1.Operation{
+			Service:   "",
+			Operation: fmt.Sprintf("%d", i),
+		}
+		assert.Nil(t, tree.Add(op))
+		ops = append(ops, op)
+	}
+
+	op := ops[0]
+	before, _ := tree.Generate(op)
+	for i := 0; i < 10; i++ {
+		assert.Nil(t, tree.Promote(op, 1))
+	}
+	promoted, _ := tree.Generate(op)
+	assert.Less(t, before, promoted)
+
+	// promotions must not decay before half-life elapses.
+	tree.Decay(time.Hour)
+	s, _ := tree.Generate(op)
+	assert.Equal(t, promoted, s)
+
+	for i := 0; i < 10; i++ {
+		tree.Decay(0)
+	}
+	decayed, _ := tree.Generate(op)
+	assert.Less(t, decayed, promoted)
+
+	// promotions are kept for later decays only if they could not be taken back without lowering sampling rates.
+	st := tree.(*sst)
+	for node := range st.boosted {
+		assert.False(t, st.demote(node))
+	}
+
+	root := st.root
+	check(root, root, t)
+}
+
 func Test(t *testing.T) {
 	order := 4
 	N := 50
@@ -163,7 +311,7 @@ func Test(t *testing.T) {
 
 	for k := 0; k < N; k++ {
 		sr, _ := tree.Generate(ops[k])
-		fmt.Printf("%f", sr.SamplingRate)
+		fmt.Printf("%f", sr)
 		if k != N-1 {
 			fmt.Printf("\t")
 		} else {
@@ -173,11 +321,11 @@ func Test(t *testing.T) {
 
 	for i := 0; i < N*2; i++ {
 		for j := 0; j < times; j++ {
-			tree.Promote(ops[i%N])
+			tree.Promote(ops[i%N], 1)
 		}
 		for k := 0; k < N; k++ {
 			sr, _ := tree.Generate(ops[k])
-			fmt.Printf("%.12f", sr.SamplingRate)
+			fmt.Printf("%.12f", sr)
 			if k != N-1 {
 				fmt.Printf("\t")
 			} else {
@@ -203,3 +351,32 @@ func check(root, currNode *treeNode, t *testing.T) int {
 	assert.Equal(t, sum, currNode.leafCnt)
 	return sum
 }
+
+func TestValidateMustCheckWhatGenerateReads(t *testing.T) {
+	tree := NewSamplingStrategyTree(maxN)
+	for i := 0; i < 20; i++ {
+		assert.Nil(t, tree.Add(&api_v1.Operation{
+			Service:   "",
+			Operation: fmt.Sprintf("%d", i),
+		}))
+	}
+	st := tree.(*sst)
+	assert.Nil(t, tree.Validate())
+
+	key := leafKey{service: "", operation: "0"}
+	node, _ := st.loadLeaves().Load(key)
+	st.loadLeaves().Delete(key)
+	assert.Error(t, tree.Validate())
+	st.loadLeaves().Store(key, node)
+	assert.Nil(t, tree.Validate())
+
+	// a leaf which mirrors a wrong parent would be generated a wrong sampling rate.
+	leaf := node.(*treeNode)
+	leaf.up.Store(st.root)
+	assert.Error(t, tree.Validate())
+	leaf.up.Store(leaf.parent)
+	assert.Nil(t, tree.Validate())
+
+	st.loadLeaves().Store(leafKey{service: "", operation: "unknown"}, leaf)
+	assert.Error(t, tree.Validate())
+}
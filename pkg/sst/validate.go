@@ -0,0 +1,209 @@
+// Copyright (c) 2021 The Houyi Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sst
+
+import (
+	"fmt"
+	"math"
+)
+
+const (
+	// rateSumTolerance is the tolerance of floating point errors when summing sampling rates of all leaves.
+	rateSumTolerance = 1e-9
+	// rateTolerance is the relative tolerance of floating point errors when computing the sampling rate of a leaf.
+	rateTolerance = 1e-9
+)
+
+func (t *sst) Validate() error {
+	t.RLock()
+	defer t.RUnlock()
+
+	return t.validate()
+}
+
+// validate checks invariants of this tree. The sampling rates of all leaves must sum to 1 if there is any leaf.
+func (t *sst) validate() error {
+	if t.root.parent != nil {
+		return fmt.Errorf("root has a parent")
+	}
+
+	leaves := make(map[*treeNode]float64)
+	if _, err := t.validateNode(t.root, 1.0, leaves); err != nil {
+		return err
+	}
+
+	cnt := 0
+	for svc, ops := range t.nodes {
+		for op, node := range ops {
+			if _, has := leaves[node]; !has {
+				return fmt.Errorf("operation %s %s is in node map but not in tree", svc, op)
+			}
+			if node.op.GetService() != svc || node.op.GetOperation() != op {
+				return fmt.Errorf("operation %s %s is mapped to node of %s", svc, op, node.op.String())
+			}
+			cnt++
+		}
+	}
+	if cnt != len(leaves) {
+		return fmt.Errorf("tree has %d leaves but node map has %d operations", len(leaves), cnt)
+	}
+	if err := t.validateLeaves(leaves); err != nil {
+		return err
+	}
+
+	for node := range t.boosted {
+		if _, has := leaves[node]; !has {
+			return fmt.Errorf("boosted operation %s is not in tree", node.op.String())
+		}
+		if node.boost <= 0 {
+			return fmt.Errorf("boosted operation %s has boost %d", node.op.String(), node.boost)
+		}
+	}
+
+	if len(leaves) != 0 {
+		sum := 0.0
+		for _, rate := range leaves {
+			sum += rate
+		}
+		if math.Abs(sum-1.0) > rateSumTolerance {
+			return fmt.Errorf("sampling rates of all operations sum to %v", sum)
+		}
+	}
+	return nil
+}
+
+// validateLeaves checks that the index of leaves and the atomic mirrors of tree, which Generate reads without the lock
+// of tree, agree with node map and the structure of tree.
+func (t *sst) validateLeaves(leaves map[*treeNode]float64) error {
+	indexed := 0
+	var err error
+	t.loadLeaves().Range(func(k, v interface{}) bool {
+		key := k.(leafKey)
+		if node := t.nodes.get(key.service, key.operation); node == nil {
+			err = fmt.Errorf("operation %s %s is indexed but not in node map", key.service, key.operation)
+		} else if node != v.(*treeNode) {
+			err = fmt.Errorf("operation %s %s is indexed to another node", key.service, key.operation)
+		}
+		indexed++
+		return err == nil
+	})
+	if err != nil {
+		return err
+	}
+	if indexed != len(leaves) {
+		return fmt.Errorf("index has %d operations but tree has %d leaves", indexed, len(leaves))
+	}
+
+	for svc, ops := range t.nodes {
+		for op, node := range ops {
+			rate, has := t.generate(leafKey{service: svc, operation: op})
+			if !has {
+				return fmt.Errorf("operation %s %s is not indexed", svc, op)
+			}
+			if expected := leaves[node]; math.Abs(rate-expected) > expected*rateTolerance {
+				return fmt.Errorf("operation %s %s is generated sampling rate %v instead of %v", svc, op, rate, expected)
+			}
+		}
+	}
+	return nil
+}
+
+// validateNode checks n and its descendants, returns the number of leaves under n and collects the sampling rates
+// of leaves.
+func (t *sst) validateNode(n *treeNode, rate float64, leaves map[*treeNode]float64) (int, error) {
+	if n.maxN != t.maxN {
+		return 0, fmt.Errorf("node has order %d instead of %d", n.maxN, t.maxN)
+	}
+	if n.isLeaf() {
+		if n == t.root {
+			return 0, fmt.Errorf("root is a leaf")
+		}
+		if n.leafCnt != 1 {
+			return 0, fmt.Errorf("leaf %s has leaf count %d", n.op.String(), n.leafCnt)
+		}
+		if _, has := leaves[n]; has {
+			return 0, fmt.Errorf("leaf %s appears more than once", n.op.String())
+		}
+		leaves[n] = rate
+		return 1, nil
+	}
+
+	children := n.childNodes.all()
+	if len(children) != n.childNodes.size() {
+		return 0, fmt.Errorf("linked list of node set has %d nodes but map has %d",
+			len(children), n.childNodes.size())
+	}
+	if len(children) > t.maxN {
+		return 0, fmt.Errorf("branch has %d children which exceeds order %d", len(children), t.maxN)
+	}
+	if n != t.root && len(children) < 2 {
+		return 0, fmt.Errorf("branch has %d children", len(children))
+	}
+
+	if n.childNodes.loadSize() != len(children) {
+		return 0, fmt.Errorf("branch has %d children but mirrors %d", len(children), n.childNodes.loadSize())
+	}
+
+	sum := 0
+	for _, c := range children {
+		if c.parent != n {
+			return 0, fmt.Errorf("child node does not point to its parent")
+		}
+		if c.loadParent() != n {
+			return 0, fmt.Errorf("child node does not mirror its parent")
+		}
+		cnt, err := t.validateNode(c, rate/float64(len(children)), leaves)
+		if err != nil {
+			return 0, err
+		}
+		sum += cnt
+	}
+	if sum != n.leafCnt {
+		return 0, fmt.Errorf("branch has leaf count %d but %d leaves actually", n.leafCnt, sum)
+	}
+	return sum, nil
+}
+
+func (t *serviceTree) Validate() error {
+	t.RLock()
+	defer t.RUnlock()
+
+	for svc, sub := range t.services {
+		sub.RLock()
+		err := sub.validateSubtree(svc)
+		sub.RUnlock()
+		if err != nil {
+			return fmt.Errorf("invalid subtree of service %s: %w", svc, err)
+		}
+	}
+	return nil
+}
+
+// validateSubtree checks invariants of t as the subtree of service svc, which must not be empty and must contain
+// operations of svc only.
+func (t *sst) validateSubtree(svc string) error {
+	if err := t.validate(); err != nil {
+		return err
+	}
+	if t.root.leafCnt == 0 {
+		return fmt.Errorf("subtree is empty")
+	}
+	for s := range t.nodes {
+		if s != svc {
+			return fmt.Errorf("subtree contains operations of service %s", s)
+		}
+	}
+	return nil
+}
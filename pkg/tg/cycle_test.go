@@ -0,0 +1,160 @@
+// Copyright (c) 2021 The Houyi Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tg
+
+import (
+	"fmt"
+	"github.com/houyi-tracing/houyi/idl/api_v1"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"testing"
+)
+
+func TestCyclicCalls(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	tg := NewTraceGraph(logger)
+
+	ops := make([]*api_v1.Operation, 0)
+	for i := 0; i < 6; i++ {
+		op := &api_v1.Operation{
+			Service:   fmt.Sprintf("%d", i),
+			Operation: fmt.Sprintf("%d", i),
+		}
+		assert.Nil(t, tg.Add(op))
+		ops = append(ops, op)
+	}
+
+	// 0->1
+	// 1->2, 2->1
+	// 2->3, 3->4, 4->2
+	// 4->5
+	for _, r := range [][2]int{{0, 1}, {1, 2}, {2, 1}, {2, 3}, {3, 4}, {4, 2}, {4, 5}} {
+		assert.Nil(t, tg.AddRelation(&api_v1.Relation{
+			From: ops[r[0]],
+			To:   ops[r[1]],
+		}))
+	}
+
+	cycles := tg.Cycles()
+	assert.Equal(t, 1, len(cycles))
+	assert.Equal(t, []*api_v1.Operation{ops[1], ops[2], ops[3], ops[4]}, cycles[0])
+
+	for _, op := range ops {
+		ingresses, err := tg.GetIngresses(op)
+		assert.Nil(t, err)
+		assert.Equal(t, []*api_v1.Operation{ops[0]}, ingresses)
+	}
+
+	traces, err := tg.Dependencies(ops[5], 0)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(traces))
+	assert.Equal(t, 6, countDistinct(traces[0], make(map[string]struct{})))
+	assertCutAtCycles(t, traces[0], make(map[string]struct{}))
+
+	// break the cycle of 1 and 2, and 2->3->4->2 is still a cycle.
+	assert.Nil(t, tg.RemoveRelation(&api_v1.Relation{
+		From: ops[2],
+		To:   ops[1],
+	}))
+	cycles = tg.Cycles()
+	assert.Equal(t, 1, len(cycles))
+	assert.Equal(t, []*api_v1.Operation{ops[2], ops[3], ops[4]}, cycles[0])
+
+	assert.Nil(t, tg.RemoveRelation(&api_v1.Relation{
+		From: ops[4],
+		To:   ops[2],
+	}))
+	assert.Equal(t, 0, len(tg.Cycles()))
+}
+
+func TestCyclicCallsWithoutIngress(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	tg := NewTraceGraph(logger)
+
+	op1 := &api_v1.Operation{Service: "1", Operation: "1"}
+	op2 := &api_v1.Operation{Service: "2", Operation: "2"}
+	assert.Nil(t, tg.Add(op1))
+	assert.Nil(t, tg.Add(op2))
+	assert.Nil(t, tg.AddRelation(&api_v1.Relation{From: op1, To: op2}))
+	assert.Nil(t, tg.AddRelation(&api_v1.Relation{From: op2, To: op1}))
+
+	// operations calling each other without any other caller are all ingresses.
+	ingresses, err := tg.GetIngresses(op1)
+	assert.Nil(t, err)
+	assert.ElementsMatch(t, []*api_v1.Operation{op1, op2}, ingresses)
+	assert.True(t, tg.IsIngress(op1))
+	assert.True(t, tg.IsIngress(op2))
+	assert.Equal(t, 1, len(tg.Cycles()))
+
+	// they are not ingresses while they are called by another operation, and become ingresses again after the
+	// relation from it is removed.
+	op0 := &api_v1.Operation{Service: "0", Operation: "0"}
+	assert.Nil(t, tg.Add(op0))
+	assert.Nil(t, tg.AddRelation(&api_v1.Relation{From: op0, To: op1}))
+	assert.ElementsMatch(t, []*api_v1.Operation{op0}, tg.AllIngresses())
+
+	assert.Nil(t, tg.RemoveRelation(&api_v1.Relation{From: op0, To: op1}))
+	assert.ElementsMatch(t, []*api_v1.Operation{op0, op1, op2}, tg.AllIngresses())
+	ingresses, err = tg.GetIngresses(op2)
+	assert.Nil(t, err)
+	assert.ElementsMatch(t, []*api_v1.Operation{op1, op2}, ingresses)
+}
+
+func TestLongCallChainMustNotOverflowStack(t *testing.T) {
+	logger := zap.NewNop()
+	tg := NewTraceGraph(logger)
+
+	n := 100000
+	ops := make([]*api_v1.Operation, 0, n)
+	for i := 0; i < n; i++ {
+		op := &api_v1.Operation{Service: "svc", Operation: fmt.Sprintf("%d", i)}
+		assert.Nil(t, tg.Add(op))
+		ops = append(ops, op)
+		if i > 0 {
+			assert.Nil(t, tg.AddRelation(&api_v1.Relation{From: ops[i-1], To: op}))
+		}
+	}
+	assert.Nil(t, tg.AddRelation(&api_v1.Relation{From: ops[n-1], To: ops[1]}))
+
+	cycles := tg.Cycles()
+	assert.Equal(t, 1, len(cycles))
+	assert.Equal(t, n-1, len(cycles[0]))
+
+	ingresses, err := tg.GetIngresses(ops[n-1])
+	assert.Nil(t, err)
+	assert.Equal(t, []*api_v1.Operation{ops[0]}, ingresses)
+}
+
+func countDistinct(tn *TraceNode, seen map[string]struct{}) int {
+	seen[tn.Name] = struct{}{}
+	for _, c := range tn.Children {
+		countDistinct(c, seen)
+	}
+	return len(seen)
+}
+
+func assertCutAtCycles(t *testing.T, tn *TraceNode, onPath map[string]struct{}) {
+	_, has := onPath[tn.Name]
+	assert.Equal(t, has, tn.Cycle)
+	if tn.Cycle {
+		assert.Equal(t, 0, len(tn.Children))
+		return
+	}
+	onPath[tn.Name] = struct{}{}
+	for _, c := range tn.Children {
+		assertCutAtCycles(t, c, onPath)
+	}
+	delete(onPath, tn.Name)
+}
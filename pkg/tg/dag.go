@@ -0,0 +1,225 @@
+// Copyright (c) 2021 The Houyi Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tg
+
+import (
+	"fmt"
+	"github.com/houyi-tracing/houyi/idl/api_v1"
+	"math"
+	"sort"
+	"time"
+)
+
+// DependencyGraph is the graph of operations reachable from ingresses of an operation.
+type DependencyGraph struct {
+	Ingresses []*api_v1.Operation `json:"ingresses"`
+	Nodes     []*DependencyNode   `json:"nodes"`
+	Edges     []*DependencyEdge   `json:"edges"`
+
+	// Paths is the number of distinct call paths from ingresses, which is the number of leaves of the trees returned
+	// by Dependencies. It saturates at the maximum of uint64.
+	Paths uint64 `json:"paths"`
+}
+
+type DependencyNode struct {
+	Service   string `json:"service"`
+	Operation string `json:"operation"`
+
+	// Depth is the length of the shortest path from ingresses to this node.
+	Depth int `json:"depth"`
+
+	// Paths is the number of distinct call paths from ingresses to this node.
+	Paths uint64 `json:"paths"`
+
+	// Truncated is true if operations called by this node are omitted because of the limit of depth.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+type DependencyEdge struct {
+	From  *api_v1.Operation `json:"from"`
+	To    *api_v1.Operation `json:"to"`
+	Stats *RelationStats    `json:"stats"`
+
+	// Cycle is true if this edge closes a cyclic call. Such edges are not counted in Paths.
+	Cycle bool `json:"cycle,omitempty"`
+}
+
+func (t *traceGraph) DependencyGraph(op *api_v1.Operation, maxDepth int) (*DependencyGraph, error) {
+	t.RLock()
+	defer t.RUnlock()
+
+	if !t.has(op) {
+		return nil, fmt.Errorf(OperationDoesNotExistErr)
+	}
+
+	ingresses := t.searchIngresses(t.get(op))
+	sortOperations(ingresses)
+	roots := make([]*node, 0, len(ingresses))
+	for _, e := range ingresses {
+		roots = append(roots, t.get(e))
+	}
+
+	depths := shortestDepths(roots, maxDepth)
+	included := func(n *node) bool {
+		_, has := depths[n]
+		return has
+	}
+	expanded := func(n *node) bool {
+		return maxDepth <= 0 || depths[n] < maxDepth
+	}
+
+	postOrder, backEdges := classifyEdges(roots, func(n *node) []*node {
+		if !expanded(n) {
+			return nil
+		}
+		ret := make([]*node, 0, n.OutCnt())
+		for _, next := range sortedOut(n) {
+			if included(next) {
+				ret = append(ret, next)
+			}
+		}
+		return ret
+	})
+
+	// count paths in topological order of the graph without back edges, which is the reverse post order, so paths of
+	// a node are final once it is visited.
+	paths := make(map[*node]uint64, len(postOrder))
+	for _, r := range roots {
+		paths[r] = 1
+	}
+	g := &DependencyGraph{
+		Ingresses: ingresses,
+		Nodes:     make([]*DependencyNode, 0, len(postOrder)),
+		Edges:     make([]*DependencyEdge, 0),
+	}
+	now := time.Now()
+	for i := len(postOrder) - 1; i >= 0; i-- {
+		n := postOrder[i]
+		leaf := true
+		if expanded(n) {
+			for _, next := range sortedOut(n) {
+				if !included(next) {
+					continue
+				}
+				e := &DependencyEdge{
+					From: n.operation,
+					To:   next.operation,
+				}
+				if stats, has := n.stats[next]; has {
+					e.Stats = stats.summary(now)
+				}
+				if _, isBack := backEdges[[2]*node{n, next}]; isBack {
+					e.Cycle = true
+				} else {
+					paths[next] = addSaturating(paths[next], paths[n])
+					leaf = false
+				}
+				g.Edges = append(g.Edges, e)
+			}
+		}
+		if leaf {
+			g.Paths = addSaturating(g.Paths, paths[n])
+		}
+		g.Nodes = append(g.Nodes, &DependencyNode{
+			Service:   n.operation.GetService(),
+			Operation: n.operation.GetOperation(),
+			Depth:     depths[n],
+			Paths:     paths[n],
+			Truncated: !expanded(n) && n.OutCnt() > 0,
+		})
+	}
+	return g, nil
+}
+
+// shortestDepths returns the lengths of shortest paths from roots to nodes reachable from roots within maxDepth.
+func shortestDepths(roots []*node, maxDepth int) map[*node]int {
+	depths := make(map[*node]int)
+	queue := make([]*node, 0, len(roots))
+	for _, r := range roots {
+		depths[r] = 0
+		queue = append(queue, r)
+	}
+	for len(queue) > 0 {
+		curr := queue[0]
+		queue = queue[1:]
+		if maxDepth > 0 && depths[curr] >= maxDepth {
+			continue
+		}
+		for _, next := range curr.out.All() {
+			if _, has := depths[next]; !has {
+				depths[next] = depths[curr] + 1
+				queue = append(queue, next)
+			}
+		}
+	}
+	return depths
+}
+
+// classifyEdges walks the graph from roots by depth first search without recursion. It returns nodes in post order
+// and edges pointing to nodes on the current path, which are back edges closing cycles.
+func classifyEdges(roots []*node, successors func(n *node) []*node) ([]*node, map[[2]*node]struct{}) {
+	type frame struct {
+		n    *node
+		succ []*node
+		next int
+	}
+
+	postOrder := make([]*node, 0)
+	backEdges := make(map[[2]*node]struct{})
+	visited := make(map[*node]struct{})
+	onPath := make(map[*node]struct{})
+	for _, r := range roots {
+		if _, has := visited[r]; has {
+			continue
+		}
+		visited[r] = struct{}{}
+		onPath[r] = struct{}{}
+		frames := []*frame{{n: r, succ: successors(r)}}
+		for len(frames) > 0 {
+			f := frames[len(frames)-1]
+			if f.next < len(f.succ) {
+				w := f.succ[f.next]
+				f.next++
+				if _, has := onPath[w]; has {
+					backEdges[[2]*node{f.n, w}] = struct{}{}
+				} else if _, has := visited[w]; !has {
+					visited[w] = struct{}{}
+					onPath[w] = struct{}{}
+					frames = append(frames, &frame{n: w, succ: successors(w)})
+				}
+				continue
+			}
+			frames = frames[:len(frames)-1]
+			delete(onPath, f.n)
+			postOrder = append(postOrder, f.n)
+		}
+	}
+	return postOrder, backEdges
+}
+
+func sortedOut(n *node) []*node {
+	ret := n.out.All()
+	sort.Slice(ret, func(i, j int) bool {
+		return lessOperation(ret[i].operation, ret[j].operation)
+	})
+	return ret
+}
+
+func addSaturating(a, b uint64) uint64 {
+	if a > math.MaxUint64-b {
+		return math.MaxUint64
+	}
+	return a + b
+}
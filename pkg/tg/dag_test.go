@@ -0,0 +1,115 @@
+// Copyright (c) 2021 The Houyi Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tg
+
+import (
+	"fmt"
+	"github.com/houyi-tracing/houyi/idl/api_v1"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"math"
+	"testing"
+)
+
+func TestDependencyGraph(t *testing.T) {
+	tg, ops := newDiamond(t)
+
+	g, err := tg.DependencyGraph(ops["c1"], 0)
+	assert.Nil(t, err)
+	assert.Equal(t, []*api_v1.Operation{ops["a1"]}, g.Ingresses)
+	assert.Equal(t, 4, len(g.Nodes))
+	assert.Equal(t, 5, len(g.Edges))
+	assert.Equal(t, uint64(3), g.Paths)
+
+	paths := make(map[string]uint64)
+	for _, n := range g.Nodes {
+		paths[n.Service+n.Operation] = n.Paths
+	}
+	assert.Equal(t, map[string]uint64{"a1": 1, "b1": 1, "b2": 2, "c1": 3}, paths)
+
+	// the number of paths must be the same as the number of leaves of trees.
+	traces, err := tg.Dependencies(ops["c1"], 0)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, countLeaves(traces[0]))
+
+	g, err = tg.DependencyGraph(ops["c1"], 1)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(g.Nodes))
+	assert.Equal(t, 2, len(g.Edges))
+	assert.Equal(t, uint64(2), g.Paths)
+	for _, n := range g.Nodes {
+		assert.Equal(t, n.Depth == 1, n.Truncated)
+	}
+
+	traces, err = tg.Dependencies(ops["c1"], 1)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, countLeaves(traces[0]))
+	assert.True(t, traces[0].Children[0].Truncated)
+}
+
+func TestDependencyGraphWithCycles(t *testing.T) {
+	tg, ops := newDiamond(t)
+	assert.Nil(t, tg.AddRelation(&api_v1.Relation{From: ops["c1"], To: ops["b1"]}))
+
+	g, err := tg.DependencyGraph(ops["c1"], 0)
+	assert.Nil(t, err)
+	assert.Equal(t, 4, len(g.Nodes))
+	assert.Equal(t, 6, len(g.Edges))
+	assert.Equal(t, uint64(3), g.Paths)
+
+	cycles := 0
+	for _, e := range g.Edges {
+		if e.Cycle {
+			cycles++
+		}
+	}
+	assert.Equal(t, 1, cycles)
+}
+
+func TestDependencyGraphMustNotExplode(t *testing.T) {
+	tg := NewTraceGraph(zap.NewNop())
+
+	// a ladder of 100 diamonds has 2^100 paths.
+	var prev *api_v1.Operation
+	for i := 0; i <= 100; i++ {
+		join := &api_v1.Operation{Service: "join", Operation: fmt.Sprintf("%d", i)}
+		assert.Nil(t, tg.Add(join))
+		if prev != nil {
+			for _, side := range []string{"left", "right"} {
+				op := &api_v1.Operation{Service: side, Operation: fmt.Sprintf("%d", i)}
+				assert.Nil(t, tg.Add(op))
+				assert.Nil(t, tg.AddRelation(&api_v1.Relation{From: prev, To: op}))
+				assert.Nil(t, tg.AddRelation(&api_v1.Relation{From: op, To: join}))
+			}
+		}
+		prev = join
+	}
+
+	g, err := tg.DependencyGraph(prev, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, 301, len(g.Nodes))
+	assert.Equal(t, uint64(math.MaxUint64), g.Paths)
+}
+
+func countLeaves(tn *TraceNode) int {
+	if len(tn.Children) == 0 {
+		return 1
+	}
+	ret := 0
+	for _, c := range tn.Children {
+		ret += countLeaves(c)
+	}
+	return ret
+}
@@ -0,0 +1,126 @@
+// Copyright (c) 2021 The Houyi Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tg
+
+import (
+	"github.com/houyi-tracing/houyi/idl/api_v1"
+	"sort"
+)
+
+// GraphDiff is the changes of topology from one state of trace graph to another.
+type GraphDiff struct {
+	AddedOperations   []*api_v1.Operation `json:"addedOperations"`
+	RemovedOperations []*api_v1.Operation `json:"removedOperations"`
+	AddedRelations    []*api_v1.Relation  `json:"addedRelations"`
+	RemovedRelations  []*api_v1.Relation  `json:"removedRelations"`
+
+	// AddedIngresses are operations which become ingresses, including new ingress operations.
+	AddedIngresses []*api_v1.Operation `json:"addedIngresses"`
+
+	// RemovedIngresses are operations which are no longer ingresses, including removed ingress operations.
+	RemovedIngresses []*api_v1.Operation `json:"removedIngresses"`
+}
+
+// Empty returns true if there is no change in d.
+func (d *GraphDiff) Empty() bool {
+	return len(d.AddedOperations) == 0 && len(d.RemovedOperations) == 0 &&
+		len(d.AddedRelations) == 0 && len(d.RemovedRelations) == 0 &&
+		len(d.AddedIngresses) == 0 && len(d.RemovedIngresses) == 0
+}
+
+// Diff returns changes from state from to state to, such as states of snapshots taken at different time. Operations
+// of relations are regarded as existing even if they are not in operations of a state, and operations not called by
+// other operations are regarded as ingresses, which is the same as merging a state into an empty trace graph.
+// Elements of every slice of returned diff are sorted.
+func Diff(from, to *api_v1.GraphState) *GraphDiff {
+	fromOps, fromRels := indexState(from)
+	toOps, toRels := indexState(to)
+	fromIngresses, toIngresses := ingressesOf(fromOps, fromRels), ingressesOf(toOps, toRels)
+
+	return &GraphDiff{
+		AddedOperations:   subtractOperations(toOps, fromOps),
+		RemovedOperations: subtractOperations(fromOps, toOps),
+		AddedRelations:    subtractRelations(toRels, fromRels),
+		RemovedRelations:  subtractRelations(fromRels, toRels),
+		AddedIngresses:    subtractOperations(toIngresses, fromIngresses),
+		RemovedIngresses:  subtractOperations(fromIngresses, toIngresses),
+	}
+}
+
+func indexState(state *api_v1.GraphState) (map[string]*api_v1.Operation, map[string]*api_v1.Relation) {
+	ops := make(map[string]*api_v1.Operation)
+	rels := make(map[string]*api_v1.Relation)
+	addOp := func(op *api_v1.Operation) {
+		ops[nodeKey(op.GetService(), op.GetOperation())] = op
+	}
+	for _, op := range state.GetOperations() {
+		addOp(op)
+	}
+	for _, rel := range state.GetRelations() {
+		if operationEqual(rel.GetFrom(), rel.GetTo()) {
+			continue
+		}
+		addOp(rel.GetFrom())
+		addOp(rel.GetTo())
+		rels[relationKey(rel)] = rel
+	}
+	return ops, rels
+}
+
+func ingressesOf(ops map[string]*api_v1.Operation, rels map[string]*api_v1.Relation) map[string]*api_v1.Operation {
+	called := make(map[string]struct{}, len(rels))
+	for _, rel := range rels {
+		called[nodeKey(rel.GetTo().GetService(), rel.GetTo().GetOperation())] = struct{}{}
+	}
+	ret := make(map[string]*api_v1.Operation)
+	for key, op := range ops {
+		if _, has := called[key]; !has {
+			ret[key] = op
+		}
+	}
+	return ret
+}
+
+func subtractOperations(a, b map[string]*api_v1.Operation) []*api_v1.Operation {
+	ret := make([]*api_v1.Operation, 0)
+	for key, op := range a {
+		if _, has := b[key]; !has {
+			ret = append(ret, op)
+		}
+	}
+	sortOperations(ret)
+	return ret
+}
+
+func subtractRelations(a, b map[string]*api_v1.Relation) []*api_v1.Relation {
+	ret := make([]*api_v1.Relation, 0)
+	for key, rel := range a {
+		if _, has := b[key]; !has {
+			ret = append(ret, rel)
+		}
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		if !operationEqual(ret[i].GetFrom(), ret[j].GetFrom()) {
+			return lessOperation(ret[i].GetFrom(), ret[j].GetFrom())
+		}
+		return lessOperation(ret[i].GetTo(), ret[j].GetTo())
+	})
+	return ret
+}
+
+func relationKey(rel *api_v1.Relation) string {
+	from, to := rel.GetFrom(), rel.GetTo()
+	return nodeKey(from.GetService(), from.GetOperation()) + "\x00" + nodeKey(to.GetService(), to.GetOperation())
+}
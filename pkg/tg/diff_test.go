@@ -0,0 +1,78 @@
+// Copyright (c) 2021 The Houyi Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tg
+
+import (
+	"github.com/houyi-tracing/houyi/idl/api_v1"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	a := &api_v1.Operation{Service: "a", Operation: "a1"}
+	b := &api_v1.Operation{Service: "b", Operation: "b1"}
+	c := &api_v1.Operation{Service: "c", Operation: "c1"}
+	ab := &api_v1.Relation{From: a, To: b}
+	ac := &api_v1.Relation{From: a, To: c}
+	bc := &api_v1.Relation{From: b, To: c}
+
+	from := &api_v1.GraphState{
+		Operations: []*api_v1.Operation{a, b},
+		Relations:  []*api_v1.Relation{ab},
+	}
+	// a calls c instead of b, so b becomes an ingress.
+	to := &api_v1.GraphState{
+		Operations: []*api_v1.Operation{a, b},
+		Relations:  []*api_v1.Relation{ac},
+	}
+
+	d := Diff(from, to)
+	assert.Equal(t, []*api_v1.Operation{c}, d.AddedOperations)
+	assert.Empty(t, d.RemovedOperations)
+	assert.Equal(t, []*api_v1.Relation{ac}, d.AddedRelations)
+	assert.Equal(t, []*api_v1.Relation{ab}, d.RemovedRelations)
+	assert.Equal(t, []*api_v1.Operation{b}, d.AddedIngresses)
+	assert.Empty(t, d.RemovedIngresses)
+	assert.False(t, d.Empty())
+
+	d = Diff(to, &api_v1.GraphState{Relations: []*api_v1.Relation{ab, bc}})
+	assert.Empty(t, d.AddedOperations)
+	assert.Empty(t, d.RemovedOperations)
+	assert.Equal(t, []*api_v1.Relation{ab, bc}, d.AddedRelations)
+	assert.Equal(t, []*api_v1.Relation{ac}, d.RemovedRelations)
+	assert.Empty(t, d.AddedIngresses)
+	assert.Equal(t, []*api_v1.Operation{b}, d.RemovedIngresses)
+
+	assert.True(t, Diff(from, from).Empty())
+}
+
+func TestDiffSnapshots(t *testing.T) {
+	g, ops := newDiamond(t)
+	data, err := g.Snapshot()
+	assert.Nil(t, err)
+	before, err := ParseSnapshot(data)
+	assert.Nil(t, err)
+
+	assert.Nil(t, g.Remove(ops["b1"]))
+	d := Diff(before, g.State())
+	assert.Len(t, d.RemovedOperations, 1)
+	assert.True(t, operationEqual(ops["b1"], d.RemovedOperations[0]))
+	assert.Len(t, d.RemovedRelations, 3)
+	assert.Empty(t, d.AddedRelations)
+	assert.Empty(t, d.AddedIngresses)
+
+	_, err = ParseSnapshot([]byte(`{"version":0}`))
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,137 @@
+// Copyright (c) 2021 The Houyi Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tg
+
+import (
+	"github.com/houyi-tracing/houyi/idl/api_v1"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Subscription receives changes of a trace graph as events in the order they happened.
+type Subscription struct {
+	id      int
+	events  chan *api_v1.GraphEvent
+	dropped uint64
+	pub     *publisher
+}
+
+// Events returns the channel of events, which would be closed after the subscription is closed. The subscription is
+// also closed once an event is dropped because the buffer is full, so that subscribers never miss changes silently
+// and could resynchronize with trace graph instead.
+func (s *Subscription) Events() <-chan *api_v1.GraphEvent {
+	return s.events
+}
+
+// Dropped returns the number of events dropped because the buffer of this subscription was full. It is not 0 only if
+// the subscription was closed because of it.
+func (s *Subscription) Dropped() uint64 {
+	return atomic.LoadUint64(&s.dropped)
+}
+
+// Close stops receiving events. It is safe to close a subscription more than once.
+func (s *Subscription) Close() {
+	s.pub.unsubscribe(s)
+}
+
+// publisher delivers events to subscriptions without blocking, so that slow subscribers would never block changes of
+// trace graph. Subscriptions which could not keep up are closed.
+type publisher struct {
+	sync.Mutex
+
+	nextId int
+	subs   map[int]*Subscription
+}
+
+func newPublisher() *publisher {
+	return &publisher{
+		subs: make(map[int]*Subscription),
+	}
+}
+
+func (p *publisher) subscribe(bufferSize int) *Subscription {
+	p.Lock()
+	defer p.Unlock()
+
+	if bufferSize < 0 {
+		bufferSize = 0
+	}
+	p.nextId++
+	s := &Subscription{
+		id:     p.nextId,
+		events: make(chan *api_v1.GraphEvent, bufferSize),
+		pub:    p,
+	}
+	p.subs[s.id] = s
+	return s
+}
+
+func (p *publisher) unsubscribe(s *Subscription) {
+	p.Lock()
+	defer p.Unlock()
+
+	if _, has := p.subs[s.id]; has {
+		delete(p.subs, s.id)
+		close(s.events)
+	}
+}
+
+func (p *publisher) publish(e *api_v1.GraphEvent) {
+	p.Lock()
+	defer p.Unlock()
+
+	for _, s := range p.subs {
+		select {
+		case s.events <- e:
+		default:
+			atomic.AddUint64(&s.dropped, 1)
+			delete(p.subs, s.id)
+			close(s.events)
+		}
+	}
+}
+
+func (t *traceGraph) Subscribe(bufferSize int) *Subscription {
+	return t.pub.subscribe(bufferSize)
+}
+
+func (t *traceGraph) publishOperation(typ api_v1.GraphEvent_EventType, n *node) {
+	t.pub.publish(&api_v1.GraphEvent{
+		Type:      typ,
+		Operation: n.operation,
+		Timestamp: time.Now().UnixNano(),
+	})
+}
+
+func (t *traceGraph) publishRelation(typ api_v1.GraphEvent_EventType, from, to *node) {
+	t.pub.publish(&api_v1.GraphEvent{
+		Type: typ,
+		Relation: &api_v1.Relation{
+			From: from.operation,
+			To:   to.operation,
+		},
+		Timestamp: time.Now().UnixNano(),
+	})
+}
+
+// EventRelatesTo returns true if the operation or either operation of the relation of e belongs to service svc.
+func EventRelatesTo(e *api_v1.GraphEvent, svc string) bool {
+	if op := e.GetOperation(); op != nil {
+		return op.GetService() == svc
+	}
+	rel := e.GetRelation()
+	return rel.GetFrom().GetService() == svc || rel.GetTo().GetService() == svc
+}
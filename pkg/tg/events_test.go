@@ -0,0 +1,118 @@
+// Copyright (c) 2021 The Houyi Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tg
+
+import (
+	"github.com/houyi-tracing/houyi/idl/api_v1"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"testing"
+)
+
+type change struct {
+	typ api_v1.GraphEvent_EventType
+	key string
+}
+
+func drain(sub *Subscription) []change {
+	ret := make([]change, 0)
+	for {
+		select {
+		case e, ok := <-sub.Events():
+			if !ok {
+				return ret
+			}
+			c := change{typ: e.GetType()}
+			if op := e.GetOperation(); op != nil {
+				c.key = op.GetOperation()
+			} else {
+				c.key = e.GetRelation().GetFrom().GetOperation() + "->" + e.GetRelation().GetTo().GetOperation()
+			}
+			ret = append(ret, c)
+		default:
+			return ret
+		}
+	}
+}
+
+func TestSubscribe(t *testing.T) {
+	tg := NewTraceGraph(zap.NewNop())
+	sub := tg.Subscribe(100)
+
+	op1 := &api_v1.Operation{Service: "1", Operation: "1"}
+	op2 := &api_v1.Operation{Service: "2", Operation: "2"}
+	rel12 := &api_v1.Relation{From: op1, To: op2}
+	assert.Nil(t, tg.Add(op1))
+	assert.Nil(t, tg.Add(op2))
+	assert.Equal(t, []change{
+		{api_v1.GraphEvent_OPERATION_ADDED, "1"},
+		{api_v1.GraphEvent_INGRESS_ADDED, "1"},
+		{api_v1.GraphEvent_OPERATION_ADDED, "2"},
+		{api_v1.GraphEvent_INGRESS_ADDED, "2"},
+	}, drain(sub))
+
+	assert.Nil(t, tg.AddRelation(rel12))
+	assert.Equal(t, []change{
+		{api_v1.GraphEvent_RELATION_ADDED, "1->2"},
+		{api_v1.GraphEvent_INGRESS_REMOVED, "2"},
+	}, drain(sub))
+
+	// adding an existing relation changes nothing.
+	assert.Nil(t, tg.AddRelation(rel12))
+	assert.Empty(t, drain(sub))
+
+	assert.Nil(t, tg.Remove(op1))
+	assert.Equal(t, []change{
+		{api_v1.GraphEvent_INGRESS_REMOVED, "1"},
+		{api_v1.GraphEvent_RELATION_REMOVED, "1->2"},
+		{api_v1.GraphEvent_INGRESS_ADDED, "2"},
+		{api_v1.GraphEvent_OPERATION_REMOVED, "1"},
+	}, drain(sub))
+
+	sub.Close()
+	sub.Close()
+	_, ok := <-sub.Events()
+	assert.False(t, ok)
+	assert.Nil(t, tg.Add(op1))
+}
+
+func TestSubscriptionDropsEvents(t *testing.T) {
+	tg := NewTraceGraph(zap.NewNop())
+	sub := tg.Subscribe(1)
+	defer sub.Close()
+
+	assert.Nil(t, tg.Add(&api_v1.Operation{Service: "1", Operation: "1"}))
+	assert.Equal(t, uint64(1), sub.Dropped())
+	assert.Len(t, drain(sub), 1)
+
+	// the subscription is closed once an event is dropped.
+	_, ok := <-sub.Events()
+	assert.False(t, ok)
+	assert.Nil(t, tg.Add(&api_v1.Operation{Service: "2", Operation: "2"}))
+	assert.Equal(t, uint64(1), sub.Dropped())
+}
+
+func TestEventRelatesTo(t *testing.T) {
+	op1 := &api_v1.Operation{Service: "1", Operation: "1"}
+	op2 := &api_v1.Operation{Service: "2", Operation: "2"}
+	opEvent := &api_v1.GraphEvent{Operation: op1}
+	relEvent := &api_v1.GraphEvent{Relation: &api_v1.Relation{From: op1, To: op2}}
+
+	assert.True(t, EventRelatesTo(opEvent, "1"))
+	assert.False(t, EventRelatesTo(opEvent, "2"))
+	assert.True(t, EventRelatesTo(relEvent, "1"))
+	assert.True(t, EventRelatesTo(relEvent, "2"))
+	assert.False(t, EventRelatesTo(relEvent, "3"))
+}
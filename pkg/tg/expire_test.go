@@ -0,0 +1,62 @@
+// Copyright (c) 2021 The Houyi Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tg
+
+import (
+	"github.com/houyi-tracing/houyi/idl/api_v1"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"testing"
+	"time"
+)
+
+func TestRemoveExpiredRelations(t *testing.T) {
+	tg := NewTraceGraph(zap.NewNop())
+
+	op1 := &api_v1.Operation{Service: "1", Operation: "1"}
+	op2 := &api_v1.Operation{Service: "2", Operation: "2"}
+	op3 := &api_v1.Operation{Service: "3", Operation: "3"}
+	rel12 := &api_v1.Relation{From: op1, To: op2}
+	rel32 := &api_v1.Relation{From: op3, To: op2}
+	for _, op := range []*api_v1.Operation{op1, op2, op3} {
+		assert.Nil(t, tg.Add(op))
+	}
+	assert.Nil(t, tg.AddRelation(rel12))
+	assert.Nil(t, tg.AddRelation(rel32))
+	assert.False(t, tg.IsIngress(op2))
+
+	ttl := 50 * time.Millisecond
+	assert.Equal(t, 0, len(tg.RemoveExpiredRelations(ttl)))
+
+	time.Sleep(ttl * 2)
+	assert.Nil(t, tg.RecordCall(rel32, time.Millisecond, false, time.Now()))
+
+	expired := tg.RemoveExpiredRelations(ttl)
+	assert.Equal(t, []*api_v1.Relation{rel12}, expired)
+	assert.False(t, tg.HasRelation(rel12))
+	assert.True(t, tg.HasRelation(rel32))
+	assert.False(t, tg.IsIngress(op2))
+
+	ingresses, err := tg.GetIngresses(op2)
+	assert.Nil(t, err)
+	assert.Equal(t, []*api_v1.Operation{op3}, ingresses)
+
+	// op2 becomes an ingress once its last caller disappears.
+	assert.Nil(t, tg.Remove(op3))
+	assert.True(t, tg.IsIngress(op2))
+	ingresses, err = tg.GetIngresses(op2)
+	assert.Nil(t, err)
+	assert.Equal(t, []*api_v1.Operation{op2}, ingresses)
+}
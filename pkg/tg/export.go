@@ -0,0 +1,295 @@
+// Copyright (c) 2021 The Houyi Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tg
+
+import (
+	"encoding/xml"
+	"fmt"
+	"github.com/houyi-tracing/houyi/idl/api_v1"
+	"github.com/jaegertracing/jaeger/model"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// DependencyLinkSource is the source of dependency links exported by trace graph.
+	DependencyLinkSource = "houyi"
+)
+
+// Graph is a copy of trace graph or a part of it.
+type Graph struct {
+	Nodes []*GraphNode `json:"nodes"`
+	Edges []*GraphEdge `json:"edges"`
+}
+
+type GraphNode struct {
+	Service   string `json:"service"`
+	Operation string `json:"operation"`
+	Ingress   bool   `json:"ingress"`
+}
+
+type GraphEdge struct {
+	From  *api_v1.Operation `json:"from"`
+	To    *api_v1.Operation `json:"to"`
+	Stats *RelationStats    `json:"stats"`
+}
+
+func (t *traceGraph) Graph(op *api_v1.Operation) (*Graph, error) {
+	t.RLock()
+	defer t.RUnlock()
+
+	var nodes []*node
+	if op == nil {
+		nodes = t.nodes.All()
+	} else if t.has(op) {
+		nodes = reachable(t.get(op))
+	} else {
+		return nil, fmt.Errorf(OperationDoesNotExistErr)
+	}
+
+	included := make(map[*node]struct{}, len(nodes))
+	for _, n := range nodes {
+		included[n] = struct{}{}
+	}
+
+	now := time.Now()
+	g := &Graph{
+		Nodes: make([]*GraphNode, 0, len(nodes)),
+		Edges: make([]*GraphEdge, 0),
+	}
+	for _, n := range nodes {
+		g.Nodes = append(g.Nodes, &GraphNode{
+			Service:   n.operation.GetService(),
+			Operation: n.operation.GetOperation(),
+			Ingress:   n.HasIn(t.globalRoot),
+		})
+		for to, stats := range n.stats {
+			if _, has := included[to]; has {
+				g.Edges = append(g.Edges, &GraphEdge{
+					From:  n.operation,
+					To:    to.operation,
+					Stats: stats.summary(now),
+				})
+			}
+		}
+	}
+
+	sort.Slice(g.Nodes, func(i, j int) bool {
+		if g.Nodes[i].Service != g.Nodes[j].Service {
+			return g.Nodes[i].Service < g.Nodes[j].Service
+		}
+		return g.Nodes[i].Operation < g.Nodes[j].Operation
+	})
+	sort.Slice(g.Edges, func(i, j int) bool {
+		if !operationEqual(g.Edges[i].From, g.Edges[j].From) {
+			return lessOperation(g.Edges[i].From, g.Edges[j].From)
+		}
+		return lessOperation(g.Edges[i].To, g.Edges[j].To)
+	})
+	return g, nil
+}
+
+func operationEqual(a, b *api_v1.Operation) bool {
+	return a.GetService() == b.GetService() && a.GetOperation() == b.GetOperation()
+}
+
+// Dot renders g in Graphviz DOT language. Operations of the same service are grouped into a cluster.
+func Dot(g *Graph) string {
+	ids := make(map[string]string, len(g.Nodes))
+	services := make([]string, 0)
+	byService := make(map[string][]*GraphNode)
+	for i, n := range g.Nodes {
+		ids[nodeKey(n.Service, n.Operation)] = fmt.Sprintf("n%d", i)
+		if _, has := byService[n.Service]; !has {
+			services = append(services, n.Service)
+		}
+		byService[n.Service] = append(byService[n.Service], n)
+	}
+
+	sb := &strings.Builder{}
+	sb.WriteString("digraph tg {\n")
+	sb.WriteString("  node [shape=box];\n")
+	for i, svc := range services {
+		sb.WriteString(fmt.Sprintf("  subgraph cluster_%d {\n", i))
+		sb.WriteString(fmt.Sprintf("    label=\"%s\";\n", escapeDot(svc)))
+		for _, n := range byService[svc] {
+			attrs := fmt.Sprintf("label=\"%s\"", escapeDot(n.Operation))
+			if n.Ingress {
+				attrs += ", style=bold"
+			}
+			sb.WriteString(fmt.Sprintf("    %s [%s];\n", ids[nodeKey(n.Service, n.Operation)], attrs))
+		}
+		sb.WriteString("  }\n")
+	}
+	for _, e := range g.Edges {
+		from := ids[nodeKey(e.From.GetService(), e.From.GetOperation())]
+		to := ids[nodeKey(e.To.GetService(), e.To.GetOperation())]
+		if e.Stats != nil {
+			sb.WriteString(fmt.Sprintf("  %s -> %s [label=\"%.2f/s err=%.2f%%\\np50=%s p99=%s\"];\n",
+				from, to, e.Stats.CallsPerSecond, e.Stats.ErrorRatio*100, e.Stats.P50, e.Stats.P99))
+		} else {
+			sb.WriteString(fmt.Sprintf("  %s -> %s;\n", from, to))
+		}
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+func nodeKey(svc, op string) string {
+	return svc + "\x00" + op
+}
+
+func escapeDot(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s)
+}
+
+type graphML struct {
+	XMLName xml.Name     `xml:"graphml"`
+	Xmlns   string       `xml:"xmlns,attr"`
+	Keys    []graphMLKey `xml:"key"`
+	Graph   graphMLGraph `xml:"graph"`
+}
+
+type graphMLKey struct {
+	ID   string `xml:"id,attr"`
+	For  string `xml:"for,attr"`
+	Name string `xml:"attr.name,attr"`
+	Type string `xml:"attr.type,attr"`
+}
+
+type graphMLGraph struct {
+	ID          string        `xml:"id,attr"`
+	EdgeDefault string        `xml:"edgedefault,attr"`
+	Nodes       []graphMLNode `xml:"node"`
+	Edges       []graphMLEdge `xml:"edge"`
+}
+
+type graphMLNode struct {
+	ID   string        `xml:"id,attr"`
+	Data []graphMLData `xml:"data"`
+}
+
+type graphMLEdge struct {
+	ID     string        `xml:"id,attr"`
+	Source string        `xml:"source,attr"`
+	Target string        `xml:"target,attr"`
+	Data   []graphMLData `xml:"data"`
+}
+
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+// GraphML renders g in GraphML. Statistics of relations are attributes of edges and durations are in nanoseconds.
+func GraphML(g *Graph) ([]byte, error) {
+	doc := &graphML{
+		Xmlns: "http://graphml.graphdrawing.org/xmlns",
+		Keys: []graphMLKey{
+			{ID: "service", For: "node", Name: "service", Type: "string"},
+			{ID: "operation", For: "node", Name: "operation", Type: "string"},
+			{ID: "ingress", For: "node", Name: "ingress", Type: "boolean"},
+			{ID: "calls", For: "edge", Name: "calls", Type: "long"},
+			{ID: "callsPerSecond", For: "edge", Name: "callsPerSecond", Type: "double"},
+			{ID: "errorRatio", For: "edge", Name: "errorRatio", Type: "double"},
+			{ID: "p50", For: "edge", Name: "p50", Type: "long"},
+			{ID: "p99", For: "edge", Name: "p99", Type: "long"},
+		},
+		Graph: graphMLGraph{
+			ID:          "tg",
+			EdgeDefault: "directed",
+			Nodes:       make([]graphMLNode, 0, len(g.Nodes)),
+			Edges:       make([]graphMLEdge, 0, len(g.Edges)),
+		},
+	}
+
+	ids := make(map[string]string, len(g.Nodes))
+	for i, n := range g.Nodes {
+		id := fmt.Sprintf("n%d", i)
+		ids[nodeKey(n.Service, n.Operation)] = id
+		doc.Graph.Nodes = append(doc.Graph.Nodes, graphMLNode{
+			ID: id,
+			Data: []graphMLData{
+				{Key: "service", Value: n.Service},
+				{Key: "operation", Value: n.Operation},
+				{Key: "ingress", Value: fmt.Sprintf("%t", n.Ingress)},
+			},
+		})
+	}
+	for i, e := range g.Edges {
+		edge := graphMLEdge{
+			ID:     fmt.Sprintf("e%d", i),
+			Source: ids[nodeKey(e.From.GetService(), e.From.GetOperation())],
+			Target: ids[nodeKey(e.To.GetService(), e.To.GetOperation())],
+		}
+		if e.Stats != nil {
+			edge.Data = []graphMLData{
+				{Key: "calls", Value: fmt.Sprintf("%d", e.Stats.Calls)},
+				{Key: "callsPerSecond", Value: fmt.Sprintf("%g", e.Stats.CallsPerSecond)},
+				{Key: "errorRatio", Value: fmt.Sprintf("%g", e.Stats.ErrorRatio)},
+				{Key: "p50", Value: fmt.Sprintf("%d", e.Stats.P50.Nanoseconds())},
+				{Key: "p99", Value: fmt.Sprintf("%d", e.Stats.P99.Nanoseconds())},
+			}
+		}
+		doc.Graph.Edges = append(doc.Graph.Edges, edge)
+	}
+
+	data, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), data...), nil
+}
+
+// DependencyLinks aggregates relations of g between different services into dependency links of Jaeger. CallCount of
+// a link is the number of calls in the window of rolling statistics.
+func DependencyLinks(g *Graph) []model.DependencyLink {
+	type pair struct {
+		parent, child string
+	}
+	counts := make(map[pair]uint64)
+	pairs := make([]pair, 0)
+	for _, e := range g.Edges {
+		p := pair{parent: e.From.GetService(), child: e.To.GetService()}
+		if p.parent == p.child {
+			continue
+		}
+		if _, has := counts[p]; !has {
+			pairs = append(pairs, p)
+			counts[p] = 0
+		}
+		if e.Stats != nil {
+			counts[p] += uint64(e.Stats.Calls)
+		}
+	}
+
+	ret := make([]model.DependencyLink, 0, len(pairs))
+	for _, p := range pairs {
+		ret = append(ret, model.DependencyLink{
+			Parent:    p.parent,
+			Child:     p.child,
+			CallCount: counts[p],
+			Source:    DependencyLinkSource,
+		})
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].Parent != ret[j].Parent {
+			return ret[i].Parent < ret[j].Parent
+		}
+		return ret[i].Child < ret[j].Child
+	})
+	return ret
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2021 The Houyi Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tg
+
+import (
+	"encoding/xml"
+	"github.com/houyi-tracing/houyi/idl/api_v1"
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newDiamond returns a trace graph of a.1->b.1, a.1->b.2, b.1->c.1, b.2->c.1 and b.1->b.2.
+func newDiamond(t *testing.T) (TraceGraph, map[string]*api_v1.Operation) {
+	tg := NewTraceGraph(zap.NewNop())
+	ops := map[string]*api_v1.Operation{
+		"a1": {Service: "a", Operation: "1"},
+		"b1": {Service: "b", Operation: "1"},
+		"b2": {Service: "b", Operation: "2"},
+		"c1": {Service: "c", Operation: "1"},
+		"d1": {Service: "d", Operation: "1"},
+	}
+	for _, op := range ops {
+		assert.Nil(t, tg.Add(op))
+	}
+	for _, r := range [][2]string{{"a1", "b1"}, {"a1", "b2"}, {"b1", "c1"}, {"b2", "c1"}, {"b1", "b2"}} {
+		rel := &api_v1.Relation{From: ops[r[0]], To: ops[r[1]]}
+		assert.Nil(t, tg.AddRelation(rel))
+		assert.Nil(t, tg.RecordCall(rel, time.Millisecond, false, time.Now()))
+	}
+	return tg, ops
+}
+
+func TestGraph(t *testing.T) {
+	tg, ops := newDiamond(t)
+
+	g, err := tg.Graph(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 5, len(g.Nodes))
+	assert.Equal(t, 5, len(g.Edges))
+	assert.True(t, g.Nodes[0].Ingress)
+
+	g, err = tg.Graph(ops["b2"])
+	assert.Nil(t, err)
+	assert.Equal(t, []*GraphNode{
+		{Service: "b", Operation: "2"},
+		{Service: "c", Operation: "1"},
+	}, g.Nodes)
+	assert.Equal(t, 1, len(g.Edges))
+	assert.Equal(t, int64(1), g.Edges[0].Stats.Calls)
+
+	_, err = tg.Graph(&api_v1.Operation{Service: "x", Operation: "x"})
+	assert.Error(t, err)
+}
+
+func TestExporters(t *testing.T) {
+	tg, _ := newDiamond(t)
+	g, err := tg.Graph(nil)
+	assert.Nil(t, err)
+
+	dot := Dot(g)
+	assert.True(t, strings.HasPrefix(dot, "digraph tg {"))
+	assert.Equal(t, 4, strings.Count(dot, "subgraph cluster_"))
+	assert.Equal(t, 5, strings.Count(dot, "->"))
+
+	data, err := GraphML(g)
+	assert.Nil(t, err)
+	doc := &graphML{}
+	assert.Nil(t, xml.Unmarshal(data, doc))
+	assert.Equal(t, 5, len(doc.Graph.Nodes))
+	assert.Equal(t, 5, len(doc.Graph.Edges))
+
+	// b.1->b.2 is inside service b, and b.1->c.1 and b.2->c.1 are aggregated.
+	assert.Equal(t, []model.DependencyLink{
+		{Parent: "a", Child: "b", CallCount: 2, Source: DependencyLinkSource},
+		{Parent: "b", Child: "c", CallCount: 2, Source: DependencyLinkSource},
+	}, DependencyLinks(g))
+}
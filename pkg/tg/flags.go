@@ -0,0 +1,49 @@
+// Copyright (c) 2021 The Houyi Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tg
+
+import (
+	"flag"
+	"github.com/spf13/viper"
+	"time"
+)
+
+const (
+	snapshotPath     = "trace.graph.snapshot.path"
+	snapshotInterval = "trace.graph.snapshot.interval"
+
+	DefaultSnapshotPath     = ""
+	DefaultSnapshotInterval = time.Minute
+)
+
+type Flags struct {
+	SnapshotPath     string
+	SnapshotInterval time.Duration
+}
+
+func AddFlags(flags *flag.FlagSet) {
+	flags.String(snapshotPath, DefaultSnapshotPath,
+		"[Gossip] File to save snapshots of trace graph. Snapshots also keep the identity of this node in version "+
+			"metadata of trace graph across restarts. Snapshots are disabled if it is empty.")
+	flags.Duration(snapshotInterval, DefaultSnapshotInterval,
+		"[Gossip] Interval for taking snapshots of trace graph. Only the last snapshot is taken on exit if it is "+
+			"not positive.")
+}
+
+func (f *Flags) InitFromViper(v *viper.Viper) *Flags {
+	f.SnapshotPath = v.GetString(snapshotPath)
+	f.SnapshotInterval = v.GetDuration(snapshotInterval)
+	return f
+}
@@ -0,0 +1,143 @@
+// Copyright (c) 2021 The Houyi Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tg
+
+import (
+	"fmt"
+	"github.com/houyi-tracing/houyi/idl/api_v1"
+	"sort"
+)
+
+func (t *traceGraph) Upstream(op *api_v1.Operation) ([]*api_v1.Operation, error) {
+	t.RLock()
+	defer t.RUnlock()
+
+	if !t.has(op) {
+		return nil, fmt.Errorf(OperationDoesNotExistErr)
+	}
+	return operationsOf(closure(t.get(op), t.callers)[1:]), nil
+}
+
+func (t *traceGraph) Downstream(op *api_v1.Operation) ([]*api_v1.Operation, error) {
+	t.RLock()
+	defer t.RUnlock()
+
+	if !t.has(op) {
+		return nil, fmt.Errorf(OperationDoesNotExistErr)
+	}
+	return operationsOf(reachable(t.get(op))[1:]), nil
+}
+
+func (t *traceGraph) ShortestPath(from, to *api_v1.Operation) ([]*api_v1.Operation, error) {
+	t.RLock()
+	defer t.RUnlock()
+
+	if !t.has(from) || !t.has(to) {
+		return nil, fmt.Errorf(OperationDoesNotExistErr)
+	}
+
+	src, dst := t.get(from), t.get(to)
+	prev := map[*node]*node{src: nil}
+	queue := []*node{src}
+	for len(queue) > 0 && dst != src {
+		curr := queue[0]
+		queue = queue[1:]
+		for _, next := range sortedOut(curr) {
+			if _, has := prev[next]; has {
+				continue
+			}
+			prev[next] = curr
+			if next == dst {
+				queue = nil
+				break
+			}
+			queue = append(queue, next)
+		}
+	}
+
+	if _, has := prev[dst]; !has {
+		return []*api_v1.Operation{}, nil
+	}
+	ret := make([]*api_v1.Operation, 0)
+	for n := dst; n != nil; n = prev[n] {
+		ret = append(ret, n.operation)
+	}
+	for i, j := 0, len(ret)-1; i < j; i, j = i+1, j-1 {
+		ret[i], ret[j] = ret[j], ret[i]
+	}
+	return ret, nil
+}
+
+func (t *traceGraph) DependentServices(op *api_v1.Operation) ([]string, error) {
+	t.RLock()
+	defer t.RUnlock()
+
+	if !t.has(op) {
+		return nil, fmt.Errorf(OperationDoesNotExistErr)
+	}
+
+	services := make(map[string]struct{})
+	for _, n := range closure(t.get(op), t.callers)[1:] {
+		services[n.operation.GetService()] = struct{}{}
+	}
+	ret := make([]string, 0, len(services))
+	for svc := range services {
+		ret = append(ret, svc)
+	}
+	sort.Strings(ret)
+	return ret, nil
+}
+
+// callers returns nodes calling n, which excludes globalRoot.
+func (t *traceGraph) callers(n *node) []*node {
+	ret := make([]*node, 0, n.InCnt())
+	for _, in := range n.in.All() {
+		if in != t.globalRoot {
+			ret = append(ret, in)
+		}
+	}
+	return ret
+}
+
+// reachable returns nodes reachable from n including n itself.
+func reachable(n *node) []*node {
+	return closure(n, func(n *node) []*node {
+		return n.out.All()
+	})
+}
+
+// closure returns n and nodes reachable from n by walking to neighbors in breadth first order, in which n is the first.
+func closure(n *node, neighbors func(n *node) []*node) []*node {
+	ret := []*node{n}
+	visited := map[*node]struct{}{n: {}}
+	for i := 0; i < len(ret); i++ {
+		for _, next := range neighbors(ret[i]) {
+			if _, has := visited[next]; !has {
+				visited[next] = struct{}{}
+				ret = append(ret, next)
+			}
+		}
+	}
+	return ret
+}
+
+func operationsOf(nodes []*node) []*api_v1.Operation {
+	ret := make([]*api_v1.Operation, 0, len(nodes))
+	for _, n := range nodes {
+		ret = append(ret, n.operation)
+	}
+	sortOperations(ret)
+	return ret
+}
@@ -0,0 +1,72 @@
+// Copyright (c) 2021 The Houyi Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tg
+
+import (
+	"github.com/houyi-tracing/houyi/idl/api_v1"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestImpactQueries(t *testing.T) {
+	tg, ops := newDiamond(t)
+
+	upstream, err := tg.Upstream(ops["c1"])
+	assert.Nil(t, err)
+	assert.Equal(t, []*api_v1.Operation{ops["a1"], ops["b1"], ops["b2"]}, upstream)
+
+	upstream, err = tg.Upstream(ops["a1"])
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(upstream))
+
+	downstream, err := tg.Downstream(ops["a1"])
+	assert.Nil(t, err)
+	assert.Equal(t, []*api_v1.Operation{ops["b1"], ops["b2"], ops["c1"]}, downstream)
+
+	path, err := tg.ShortestPath(ops["a1"], ops["c1"])
+	assert.Nil(t, err)
+	assert.Equal(t, []*api_v1.Operation{ops["a1"], ops["b1"], ops["c1"]}, path)
+
+	path, err = tg.ShortestPath(ops["c1"], ops["a1"])
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(path))
+
+	path, err = tg.ShortestPath(ops["b2"], ops["b2"])
+	assert.Nil(t, err)
+	assert.Equal(t, []*api_v1.Operation{ops["b2"]}, path)
+
+	services, err := tg.DependentServices(ops["c1"])
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"a", "b"}, services)
+
+	missing := &api_v1.Operation{Service: "x", Operation: "x"}
+	_, err = tg.Upstream(missing)
+	assert.Error(t, err)
+	_, err = tg.ShortestPath(ops["a1"], missing)
+	assert.Error(t, err)
+}
+
+func TestImpactQueriesWithCycles(t *testing.T) {
+	tg, ops := newDiamond(t)
+	assert.Nil(t, tg.AddRelation(&api_v1.Relation{From: ops["c1"], To: ops["b1"]}))
+
+	upstream, err := tg.Upstream(ops["b1"])
+	assert.Nil(t, err)
+	assert.Equal(t, []*api_v1.Operation{ops["a1"], ops["b2"], ops["c1"]}, upstream)
+
+	path, err := tg.ShortestPath(ops["c1"], ops["b2"])
+	assert.Nil(t, err)
+	assert.Equal(t, []*api_v1.Operation{ops["c1"], ops["b1"], ops["b2"]}, path)
+}
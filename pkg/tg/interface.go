@@ -14,11 +14,24 @@
 
 package tg
 
-import "github.com/houyi-tracing/houyi/idl/api_v1"
+import (
+	"github.com/houyi-tracing/houyi/idl/api_v1"
+	"time"
+)
 
 type TraceNode struct {
 	Name     string       `json:"name"`
 	Children []*TraceNode `json:"children"`
+
+	// Cycle is true if this node is an operation already on the path from root, which means a cyclic call. Children
+	// of such nodes are omitted.
+	Cycle bool `json:"cycle,omitempty"`
+
+	// Truncated is true if children of this node are omitted because of the limit of depth.
+	Truncated bool `json:"truncated,omitempty"`
+
+	// Stats is the statistics of calls of the relation from the parent of this node to this node.
+	Stats *RelationStats `json:"stats,omitempty"`
 }
 
 type TraceGraph interface {
@@ -39,12 +52,17 @@ type TraceGraph interface {
 	// exist in trace graph.
 	RemoveRelation(rel *api_v1.Relation) error
 
+	// RemoveExpiredRelations removes relations which have not been added, observed or reported for ttl and returns
+	// them. Operations no longer called by any other operation become ingress operations.
+	RemoveExpiredRelations(ttl time.Duration) []*api_v1.Relation
+
 	// HasRelation returns true if there is a relation between inputted operations, else false.
 	// If one of operations of this relation does not exist, this function would return false.
 	HasRelation(rel *api_v1.Relation) bool
 
 	// IsIngress returns true if operation is an ingress operation that received requests from users instead of other
-	// operations in this application system.
+	// operations in this application system. Operations calling each other without any other caller are all ingress
+	// operations.
 	IsIngress(op *api_v1.Operation) bool
 
 	// GetIngresses returns all ingress operations relate to inputted operation.
@@ -53,14 +71,83 @@ type TraceGraph interface {
 	// AllIngresses returns all ingress operations.
 	AllIngresses() []*api_v1.Operation
 
-	// Traces returns static call relationships relate to inputted operation.
-	// Each element of returned slice is an entry operation.
-	Dependencies(op *api_v1.Operation) ([]*TraceNode, error)
+	// Dependencies returns static call relationships relate to inputted operation as trees.
+	// Each element of returned slice is an entry operation. Operations deeper than maxDepth are omitted if maxDepth is
+	// greater than 0.
+	Dependencies(op *api_v1.Operation, maxDepth int) ([]*TraceNode, error)
+
+	// DependencyGraph returns static call relationships relate to inputted operation as a graph, in which operations
+	// shared by multiple callers appear only once. Operations deeper than maxDepth are omitted if maxDepth is greater
+	// than 0.
+	DependencyGraph(op *api_v1.Operation, maxDepth int) (*DependencyGraph, error)
+
+	// RecordCall records a call of an existing relation observed at time at.
+	RecordCall(rel *api_v1.Relation, duration time.Duration, isError bool, at time.Time) error
+
+	// MergeRelationStats merges statistics of calls reported by other nodes into statistics of an existing relation.
+	// Merged calls would not be returned by FlushRelationStats.
+	MergeRelationStats(stats *api_v1.RelationStats) error
+
+	// FlushRelationStats returns statistics of calls recorded by RecordCall since last flushing.
+	FlushRelationStats() []*api_v1.RelationStats
+
+	// RelationStats returns rolling statistics of calls of an existing relation.
+	RelationStats(rel *api_v1.Relation) (*RelationStats, error)
+
+	// Upstream returns operations calling inputted operation directly or indirectly, which would be affected if it
+	// breaks.
+	Upstream(op *api_v1.Operation) ([]*api_v1.Operation, error)
+
+	// Downstream returns operations called by inputted operation directly or indirectly, which it depends on.
+	Downstream(op *api_v1.Operation) ([]*api_v1.Operation, error)
+
+	// ShortestPath returns operations on the shortest call path from one operation to another, including both of them.
+	// It returns an empty slice if there is no such path.
+	ShortestPath(from, to *api_v1.Operation) ([]*api_v1.Operation, error)
+
+	// DependentServices returns services having operations which depend on inputted operation directly or indirectly.
+	DependentServices(op *api_v1.Operation) ([]string, error)
+
+	// Graph returns a copy of the subgraph reachable from inputted operation, or the whole trace graph if it is nil.
+	Graph(op *api_v1.Operation) (*Graph, error)
+
+	// State returns all operations and relations of this trace graph with their version metadata.
+	State() *api_v1.GraphState
+
+	// Merge merges version metadata of inputted state into this trace graph. Operations and relations of states without
+	// version metadata, which come from old nodes, are added if they do not exist in this trace graph.
+	Merge(state *api_v1.GraphState)
+
+	// FlushDelta returns version metadata of operations and relations changed locally since last flushing, which
+	// should be sent to other replicas of trace graph. It returns nil if nothing changed.
+	FlushDelta() *api_v1.GraphDelta
+
+	// MergeDelta merges version metadata from another replica of trace graph. An addition of an operation or relation
+	// wins over a removal of it concurrent with it, and replicas merging the same deltas converge in spite of the order
+	// and duplication of deltas.
+	MergeDelta(delta *api_v1.GraphDelta)
+
+	// Snapshot serializes operations and relations of this trace graph. Statistics of relations are not included.
+	Snapshot() ([]byte, error)
+
+	// Restore merges operations and relations of a snapshot taken by Snapshot into this trace graph. It also takes over
+	// the identity in version metadata of the trace graph taking the snapshot if nothing has changed in this trace
+	// graph, so it must only restore snapshots taken by the same node.
+	Restore(data []byte) error
+
+	// Cycles returns operations in cyclic calls. Each element of returned slice is a strongly connected component
+	// containing more than one operation, whose operations call each other directly or indirectly.
+	Cycles() [][]*api_v1.Operation
 
 	Services() []string
 
 	Operations(string) []string
 
+	// Subscribe returns a subscription receiving changes of this trace graph, whose buffer could hold bufferSize events.
+	// Events are dropped instead of blocking changes of trace graph if the buffer is full, and then the subscription is
+	// closed.
+	Subscribe(bufferSize int) *Subscription
+
 	// Size returns the number of operations in this trace graph.
 	Size() int
 }
@@ -22,6 +22,9 @@ type node struct {
 	operation *api_v1.Operation
 	in        nodeMap
 	out       nodeMap
+
+	// stats records calls of relations from this node to nodes in out.
+	stats map[*node]*relationStats
 }
 
 func newNode(op *api_v1.Operation) *node {
@@ -29,6 +32,7 @@ func newNode(op *api_v1.Operation) *node {
 		operation: op,
 		in:        newNodeMap(),
 		out:       newNodeMap(),
+		stats:     make(map[*node]*relationStats),
 	}
 }
 
@@ -54,6 +58,7 @@ func (n *node) RemoveIn(node *node) {
 
 func (n *node) RemoveOut(node *node) {
 	n.out.Remove(node.operation.Service, node.operation.Operation)
+	delete(n.stats, node)
 }
 
 func (n *node) UpdateIn(newNode *node) {
@@ -0,0 +1,188 @@
+// Copyright (c) 2021 The Houyi Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tg
+
+import (
+	"github.com/houyi-tracing/houyi/idl/api_v1"
+)
+
+// dot identifies an addition of an element made by a replica.
+type dot struct {
+	replica string
+	counter uint64
+}
+
+// causalContext is the set of dots seen by a replica. Dots from 1 to versions[replica] are compacted into a vector
+// clock, and others are kept in dots until they become contiguous.
+type causalContext struct {
+	versions map[string]uint64
+	dots     map[dot]struct{}
+}
+
+func newCausalContext() *causalContext {
+	return &causalContext{
+		versions: make(map[string]uint64),
+		dots:     make(map[dot]struct{}),
+	}
+}
+
+func (c *causalContext) seen(d dot) bool {
+	if d.counter <= c.versions[d.replica] {
+		return true
+	}
+	_, has := c.dots[d]
+	return has
+}
+
+// next returns a new dot of replica. Dots of the local replica are always contiguous, so it is the one following its
+// version.
+func (c *causalContext) next(replica string) dot {
+	d := dot{replica: replica, counter: c.versions[replica] + 1}
+	c.versions[replica] = d.counter
+	return d
+}
+
+func (c *causalContext) add(d dot) {
+	if !c.seen(d) {
+		c.dots[d] = struct{}{}
+	}
+}
+
+func (c *causalContext) merge(other *causalContext) {
+	for replica, version := range other.versions {
+		if version > c.versions[replica] {
+			c.versions[replica] = version
+		}
+	}
+	for d := range other.dots {
+		c.add(d)
+	}
+	c.compact()
+}
+
+// compact moves dots following versions into versions and drops dots already covered by versions.
+func (c *causalContext) compact() {
+	for changed := true; changed; {
+		changed = false
+		for d := range c.dots {
+			switch v := c.versions[d.replica]; {
+			case d.counter <= v:
+				delete(c.dots, d)
+			case d.counter == v+1:
+				c.versions[d.replica] = d.counter
+				delete(c.dots, d)
+				changed = true
+			}
+		}
+	}
+}
+
+// element is an operation or a relation with dots of additions which have not been removed yet.
+type element struct {
+	operation *api_v1.Operation
+	relation  *api_v1.Relation
+	dots      map[dot]struct{}
+}
+
+// dotStore is an observed-remove set of operations and relations where additions win over concurrent removals. A
+// removal only removes dots it has observed, so an addition concurrent with it survives. It is used as both the full
+// state of a replica and deltas between replicas, which are merged in the same way.
+type dotStore struct {
+	operations map[string]*element
+	relations  map[string]*element
+	ctx        *causalContext
+}
+
+func newDotStore() *dotStore {
+	return &dotStore{
+		operations: make(map[string]*element),
+		relations:  make(map[string]*element),
+		ctx:        newCausalContext(),
+	}
+}
+
+func (s *dotStore) empty() bool {
+	return len(s.operations) == 0 && len(s.relations) == 0 && len(s.ctx.versions) == 0 && len(s.ctx.dots) == 0
+}
+
+// add adds e with the new dot d, which replaces dots of e observed by now. It returns the replaced dots.
+func add(elements map[string]*element, key string, e *element, d dot) map[dot]struct{} {
+	var replaced map[dot]struct{}
+	if old, has := elements[key]; has {
+		replaced = old.dots
+	}
+	e.dots = map[dot]struct{}{d: {}}
+	elements[key] = e
+	return replaced
+}
+
+// remove removes all observed dots of element key and returns them.
+func remove(elements map[string]*element, key string) map[dot]struct{} {
+	if e, has := elements[key]; has {
+		delete(elements, key)
+		return e.dots
+	}
+	return nil
+}
+
+// merge merges other into s and returns operations and relations which might be changed by their keys.
+func (s *dotStore) merge(other *dotStore) (ops, rels map[string]*element) {
+	ops = mergeElements(s.operations, other.operations, s.ctx, other.ctx)
+	rels = mergeElements(s.relations, other.relations, s.ctx, other.ctx)
+	s.ctx.merge(other.ctx)
+	return ops, rels
+}
+
+// mergeElements keeps a dot if both sides have it, or only one side has it and the other side has never seen it.
+// A dot which one side has seen but does not have any more has been removed by that side.
+func mergeElements(a, b map[string]*element, aCtx, bCtx *causalContext) map[string]*element {
+	changed := make(map[string]*element)
+	for key, e := range a {
+		other, has := b[key]
+		for d := range e.dots {
+			if has {
+				if _, both := other.dots[d]; both {
+					continue
+				}
+			}
+			if bCtx.seen(d) {
+				delete(e.dots, d)
+				changed[key] = e
+			}
+		}
+		if len(e.dots) == 0 {
+			delete(a, key)
+		}
+	}
+	for key, e := range b {
+		for d := range e.dots {
+			if aCtx.seen(d) {
+				continue
+			}
+			curr, has := a[key]
+			if !has {
+				curr = &element{
+					operation: e.operation,
+					relation:  e.relation,
+					dots:      make(map[dot]struct{}),
+				}
+				a[key] = curr
+			}
+			curr.dots[d] = struct{}{}
+			changed[key] = curr
+		}
+	}
+	return changed
+}
@@ -0,0 +1,127 @@
+// Copyright (c) 2021 The Houyi Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tg
+
+import (
+	"github.com/houyi-tracing/houyi/idl/api_v1"
+	"sort"
+)
+
+// sccFrame is a frame of the explicit call stack used by stronglyConnectedComponents.
+type sccFrame struct {
+	n    *node
+	outs []*node
+	next int
+}
+
+// stronglyConnectedComponents finds strongly connected components of the graph consisting of nodes by Tarjan's
+// algorithm. It uses an explicit stack instead of recursion so that long call chains would not overflow the stack.
+func stronglyConnectedComponents(nodes []*node) [][]*node {
+	index := make(map[*node]int, len(nodes))
+	lowLink := make(map[*node]int, len(nodes))
+	onStack := make(map[*node]bool, len(nodes))
+	stack := make([]*node, 0)
+	components := make([][]*node, 0)
+	counter := 0
+
+	visit := func(n *node) *sccFrame {
+		index[n] = counter
+		lowLink[n] = counter
+		counter++
+		stack = append(stack, n)
+		onStack[n] = true
+		return &sccFrame{n: n, outs: n.out.All()}
+	}
+
+	for _, start := range nodes {
+		if _, visited := index[start]; visited {
+			continue
+		}
+
+		frames := []*sccFrame{visit(start)}
+		for len(frames) > 0 {
+			f := frames[len(frames)-1]
+			if f.next < len(f.outs) {
+				w := f.outs[f.next]
+				f.next++
+				if _, visited := index[w]; !visited {
+					frames = append(frames, visit(w))
+				} else if onStack[w] && index[w] < lowLink[f.n] {
+					lowLink[f.n] = index[w]
+				}
+				continue
+			}
+
+			// all successors of f.n have been visited.
+			frames = frames[:len(frames)-1]
+			if len(frames) > 0 {
+				parent := frames[len(frames)-1].n
+				if lowLink[f.n] < lowLink[parent] {
+					lowLink[parent] = lowLink[f.n]
+				}
+			}
+			if lowLink[f.n] == index[f.n] {
+				component := make([]*node, 0)
+				for {
+					w := stack[len(stack)-1]
+					stack = stack[:len(stack)-1]
+					onStack[w] = false
+					component = append(component, w)
+					if w == f.n {
+						break
+					}
+				}
+				components = append(components, component)
+			}
+		}
+	}
+	return components
+}
+
+func (t *traceGraph) Cycles() [][]*api_v1.Operation {
+	t.RLock()
+	defer t.RUnlock()
+
+	ret := make([][]*api_v1.Operation, 0)
+	for _, component := range stronglyConnectedComponents(t.nodes.All()) {
+		// self-loops are not allowed, so only components with more than one operation contain cycles.
+		if len(component) < 2 {
+			continue
+		}
+		ops := make([]*api_v1.Operation, 0, len(component))
+		for _, n := range component {
+			ops = append(ops, n.operation)
+		}
+		sortOperations(ops)
+		ret = append(ret, ops)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return lessOperation(ret[i][0], ret[j][0])
+	})
+	return ret
+}
+
+func sortOperations(ops []*api_v1.Operation) {
+	sort.Slice(ops, func(i, j int) bool {
+		return lessOperation(ops[i], ops[j])
+	})
+}
+
+func lessOperation(a, b *api_v1.Operation) bool {
+	if a.GetService() != b.GetService() {
+		return a.GetService() < b.GetService()
+	}
+	return a.GetOperation() < b.GetOperation()
+}
@@ -0,0 +1,165 @@
+// Copyright (c) 2021 The Houyi Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tg
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/houyi-tracing/houyi/idl/api_v1"
+	"go.uber.org/zap"
+)
+
+const (
+	// SnapshotVersion is the version of format of snapshots. Snapshots of other versions would be rejected.
+	SnapshotVersion = 1
+)
+
+type graphSnapshot struct {
+	Version    int                 `json:"version"`
+	Operations []*api_v1.Operation `json:"operations"`
+	Relations  []*api_v1.Relation  `json:"relations"`
+
+	// Versions is the version metadata of operations and relations. It is absent in snapshots taken by old versions.
+	Versions *api_v1.GraphDelta `json:"versions,omitempty"`
+
+	// Replica is the identity of the trace graph taking this snapshot in version metadata. It is absent in snapshots
+	// taken by old versions.
+	Replica string `json:"replica,omitempty"`
+}
+
+func (t *traceGraph) State() *api_v1.GraphState {
+	t.RLock()
+	defer t.RUnlock()
+
+	nodes := t.nodes.All()
+	state := &api_v1.GraphState{
+		Operations: make([]*api_v1.Operation, 0, len(nodes)),
+		Relations:  make([]*api_v1.Relation, 0),
+		Versions:   t.versions.toProto(),
+	}
+	for _, n := range nodes {
+		state.Operations = append(state.Operations, n.operation)
+		for _, to := range n.out.All() {
+			state.Relations = append(state.Relations, &api_v1.Relation{
+				From: n.operation,
+				To:   to.operation,
+			})
+		}
+	}
+	return state
+}
+
+// Merge merges version metadata of state. For states without version metadata, it adds operations and relations of
+// state which do not exist in this trace graph. Nothing would be removed, so merging such states of multiple nodes
+// results in the union of them.
+func (t *traceGraph) Merge(state *api_v1.GraphState) {
+	if state.GetVersions() != nil {
+		t.MergeDelta(state.GetVersions())
+		return
+	}
+	for _, op := range state.GetOperations() {
+		if !t.Has(op) {
+			_ = t.Add(op)
+		}
+	}
+	for _, rel := range state.GetRelations() {
+		if !t.HasRelation(rel) {
+			if !t.Has(rel.GetFrom()) {
+				_ = t.Add(rel.GetFrom())
+			}
+			if !t.Has(rel.GetTo()) {
+				_ = t.Add(rel.GetTo())
+			}
+			if err := t.AddRelation(rel); err != nil {
+				t.logger.Debug("failed to merge relation", zap.String("relation", rel.String()), zap.Error(err))
+			}
+		}
+	}
+}
+
+func (t *traceGraph) Snapshot() ([]byte, error) {
+	state := t.State()
+	t.RLock()
+	replica := t.replica
+	t.RUnlock()
+	return json.Marshal(&graphSnapshot{
+		Version:    SnapshotVersion,
+		Operations: state.Operations,
+		Relations:  state.Relations,
+		Versions:   state.Versions,
+		Replica:    replica,
+	})
+}
+
+func (t *traceGraph) Restore(data []byte) error {
+	snapshot, err := parseSnapshot(data)
+	if err != nil {
+		return err
+	}
+	if snapshot.Replica != "" {
+		t.restoreReplica(snapshot.Replica)
+	}
+	t.Merge(snapshotState(snapshot))
+	return nil
+}
+
+// restoreReplica makes this trace graph continue making dots as replica, so that a restarted process does not add a
+// new replica into version metadata of every node. Dots of replica made before are restored along with version
+// metadata of the snapshot or merged from peers, after which new dots follow them. It is ignored if this trace graph
+// has made dots already.
+func (t *traceGraph) restoreReplica(replica string) {
+	t.Lock()
+	defer t.Unlock()
+
+	if t.versions.ctx.versions[t.replica] != 0 {
+		t.logger.Warn("ignored replica of snapshot of trace graph made after changes",
+			zap.String("replica", replica))
+		return
+	}
+	t.replica = replica
+}
+
+// ParseSnapshot parses a snapshot taken by TraceGraph.Snapshot into the state of trace graph.
+func ParseSnapshot(data []byte) (*api_v1.GraphState, error) {
+	snapshot, err := parseSnapshot(data)
+	if err != nil {
+		return nil, err
+	}
+	return snapshotState(snapshot), nil
+}
+
+func parseSnapshot(data []byte) (*graphSnapshot, error) {
+	snapshot := &graphSnapshot{}
+	if err := json.Unmarshal(data, snapshot); err != nil {
+		return nil, fmt.Errorf("failed to parse snapshot of trace graph: %w", err)
+	}
+	if snapshot.Version != SnapshotVersion {
+		return nil, fmt.Errorf("unsupported version of snapshot of trace graph: %d", snapshot.Version)
+	}
+	for _, rel := range snapshot.Relations {
+		if rel.GetFrom() == nil || rel.GetTo() == nil {
+			return nil, fmt.Errorf("invalid relation in snapshot of trace graph")
+		}
+	}
+	return snapshot, nil
+}
+
+func snapshotState(snapshot *graphSnapshot) *api_v1.GraphState {
+	return &api_v1.GraphState{
+		Operations: snapshot.Operations,
+		Relations:  snapshot.Relations,
+		Versions:   snapshot.Versions,
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2021 The Houyi Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tg
+
+import (
+	"github.com/houyi-tracing/houyi/idl/api_v1"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRestoredGraphMustBeIdentical(t *testing.T) {
+	tg, ops := newDiamond(t)
+
+	data, err := tg.Snapshot()
+	assert.Nil(t, err)
+
+	restored := NewTraceGraph(zap.NewNop())
+	assert.Nil(t, restored.Restore(data))
+
+	expected, _ := tg.Graph(nil)
+	actual, _ := restored.Graph(nil)
+	assert.Equal(t, expected.Nodes, actual.Nodes)
+	assert.Equal(t, len(expected.Edges), len(actual.Edges))
+	for _, op := range ops {
+		assert.Equal(t, tg.IsIngress(op), restored.IsIngress(op))
+	}
+
+	assert.Error(t, restored.Restore([]byte(`{"version":0}`)))
+	assert.Error(t, restored.Restore([]byte(`{"version":1,"relations":[{}]}`)))
+}
+
+func TestMergeGraphState(t *testing.T) {
+	op1 := &api_v1.Operation{Service: "1", Operation: "1"}
+	op2 := &api_v1.Operation{Service: "2", Operation: "2"}
+	op3 := &api_v1.Operation{Service: "3", Operation: "3"}
+
+	a, b := NewTraceGraph(zap.NewNop()), NewTraceGraph(zap.NewNop())
+	assert.Nil(t, a.Add(op1))
+	assert.Nil(t, a.Add(op2))
+	assert.Nil(t, a.AddRelation(&api_v1.Relation{From: op1, To: op2}))
+	assert.Nil(t, b.Add(op2))
+	assert.Nil(t, b.Add(op3))
+	assert.Nil(t, b.AddRelation(&api_v1.Relation{From: op2, To: op3}))
+
+	a.Merge(b.State())
+	b.Merge(a.State())
+	for _, g := range []TraceGraph{a, b} {
+		assert.Equal(t, 3, g.Size())
+		assert.True(t, g.HasRelation(&api_v1.Relation{From: op1, To: op2}))
+		assert.True(t, g.HasRelation(&api_v1.Relation{From: op2, To: op3}))
+		assert.True(t, g.IsIngress(op1))
+		assert.False(t, g.IsIngress(op2))
+		assert.False(t, g.IsIngress(op3))
+	}
+}
+
+func TestSnapshotter(t *testing.T) {
+	tg, ops := newDiamond(t)
+	path := filepath.Join(t.TempDir(), "tg", "snapshot.json")
+
+	s := NewSnapshotter(zap.NewNop(), path, time.Hour, tg)
+	assert.Nil(t, s.Load())
+	s.Start()
+	s.Stop()
+
+	restored := NewTraceGraph(zap.NewNop())
+	assert.Nil(t, NewSnapshotter(zap.NewNop(), path, time.Hour, restored).Load())
+	assert.True(t, restored.HasRelation(&api_v1.Relation{From: ops["b1"], To: ops["b2"]}))
+	assert.False(t, restored.IsIngress(ops["b1"]))
+}
+
+func TestSnapshotterWithoutInterval(t *testing.T) {
+	tg, ops := newDiamond(t)
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+
+	s := NewSnapshotter(zap.NewNop(), path, 0, tg)
+	s.Start()
+	s.Stop()
+
+	restored := NewTraceGraph(zap.NewNop())
+	assert.Nil(t, NewSnapshotter(zap.NewNop(), path, 0, restored).Load())
+	assert.True(t, restored.Has(ops["b1"]))
+}
@@ -0,0 +1,67 @@
+// Copyright (c) 2021 The Houyi Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tg
+
+import (
+	"fmt"
+	"github.com/houyi-tracing/houyi/pkg/fileutil"
+	"go.uber.org/zap"
+	"time"
+)
+
+// Snapshotter saves snapshots of trace graph into a file periodically.
+type Snapshotter interface {
+	// Load restores trace graph from the last snapshot if it exists.
+	Load() error
+
+	// Start takes snapshots periodically. Only the last snapshot is saved when it stops if interval is not positive.
+	Start()
+
+	// Stop saves the last snapshot and stops taking snapshots.
+	Stop()
+}
+
+type snapshotter struct {
+	*fileutil.PeriodicSnapshot
+
+	logger *zap.Logger
+	graph  TraceGraph
+	path   string
+}
+
+func NewSnapshotter(logger *zap.Logger, path string, interval time.Duration, graph TraceGraph) Snapshotter {
+	return &snapshotter{
+		PeriodicSnapshot: fileutil.NewPeriodicSnapshot(logger, "trace graph", path, interval, graph.Snapshot),
+		logger:           logger,
+		graph:            graph,
+		path:             path,
+	}
+}
+
+func (s *snapshotter) Load() error {
+	data, err := s.Read()
+	if err != nil {
+		return fmt.Errorf("failed to read snapshot of trace graph: %w", err)
+	} else if data == nil {
+		return nil
+	}
+
+	if err = s.graph.Restore(data); err != nil {
+		return fmt.Errorf("failed to restore trace graph: %w", err)
+	}
+	s.logger.Info("Restored trace graph from snapshot",
+		zap.String("path", s.path), zap.Int("services", s.graph.Size()))
+	return nil
+}
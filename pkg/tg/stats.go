@@ -0,0 +1,267 @@
+// Copyright (c) 2021 The Houyi Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tg
+
+import (
+	"github.com/houyi-tracing/houyi/idl/api_v1"
+	"sync"
+	"time"
+)
+
+const (
+	// histBuckets is the number of buckets of duration histograms. The i-th bucket counts durations in
+	// [2^(i-1), 2^i) microseconds and the last one also counts all longer durations.
+	histBuckets = 32
+
+	// statsSlots and statsSlotSpan decide the window of rolling statistics, which is one minute.
+	statsSlots    = 6
+	statsSlotSpan = 10 * time.Second
+)
+
+// RelationStats is the rolling statistics of calls of a relation in the last minute.
+type RelationStats struct {
+	Calls          int64   `json:"calls"`
+	CallsPerSecond float64 `json:"callsPerSecond"`
+	ErrorRatio     float64 `json:"errorRatio"`
+
+	// P50 and P99 are percentiles of durations of calls in nanoseconds.
+	P50 time.Duration `json:"p50"`
+	P99 time.Duration `json:"p99"`
+
+	FirstSeen time.Time `json:"firstSeen"`
+	LastSeen  time.Time `json:"lastSeen"`
+}
+
+type histogram [histBuckets]int64
+
+func (h *histogram) add(d time.Duration) {
+	us := d.Microseconds()
+	i := 0
+	for us > 0 && i < histBuckets-1 {
+		us >>= 1
+		i++
+	}
+	h[i]++
+}
+
+func (h *histogram) merge(other *histogram) {
+	for i := range h {
+		h[i] += other[i]
+	}
+}
+
+// percentile estimates the q-th percentile by interpolating linearly in the bucket that it falls in.
+func (h *histogram) percentile(q float64) time.Duration {
+	total := int64(0)
+	for _, c := range h {
+		total += c
+	}
+	if total == 0 {
+		return 0
+	}
+
+	rank := q * float64(total)
+	cum := int64(0)
+	for i, c := range h {
+		if c == 0 || float64(cum+c) < rank {
+			cum += c
+			continue
+		}
+		lower, upper := bucketBounds(i)
+		ratio := (rank - float64(cum)) / float64(c)
+		return lower + time.Duration(ratio*float64(upper-lower))
+	}
+	lower, _ := bucketBounds(histBuckets - 1)
+	return lower
+}
+
+func bucketBounds(i int) (time.Duration, time.Duration) {
+	if i == 0 {
+		return 0, time.Microsecond
+	}
+	return time.Duration(1<<(i-1)) * time.Microsecond, time.Duration(1<<i) * time.Microsecond
+}
+
+type statsSlot struct {
+	start  time.Time
+	calls  int64
+	errors int64
+	hist   histogram
+}
+
+// relationStats records calls of a relation. Calls are recorded into slots of a ring covering the window of rolling
+// statistics. Calls observed locally are also accumulated into pending until they are flushed to be reported to
+// other nodes, while calls reported by other nodes are only recorded into slots so that they would not be reported
+// again.
+type relationStats struct {
+	sync.Mutex
+
+	slots     [statsSlots]statsSlot
+	firstSeen time.Time
+	lastSeen  time.Time
+
+	// touchedAt is the local time when this relation was added, observed or reported last time, which is used to
+	// age relations instead of lastSeen to avoid clock skew between nodes.
+	touchedAt time.Time
+
+	pending        statsSlot
+	pendingFirst   time.Time
+	pendingLast    time.Time
+	hasPendingCall bool
+}
+
+func newRelationStats() *relationStats {
+	return &relationStats{
+		touchedAt: time.Now(),
+	}
+}
+
+func (s *relationStats) touch() {
+	s.Lock()
+	defer s.Unlock()
+
+	s.touchedAt = time.Now()
+}
+
+func (s *relationStats) expired(ttl time.Duration, now time.Time) bool {
+	s.Lock()
+	defer s.Unlock()
+
+	return s.touchedAt.Add(ttl).Before(now)
+}
+
+// slot returns the slot for time now and resets it if it is out of the window.
+func (s *relationStats) slot(now time.Time) *statsSlot {
+	start := now.Truncate(statsSlotSpan)
+	slot := &s.slots[(start.UnixNano()/int64(statsSlotSpan))%statsSlots]
+	if !slot.start.Equal(start) {
+		*slot = statsSlot{start: start}
+	}
+	return slot
+}
+
+func (s *relationStats) seen(first, last time.Time) {
+	if s.firstSeen.IsZero() || first.Before(s.firstSeen) {
+		s.firstSeen = first
+	}
+	if last.After(s.lastSeen) {
+		s.lastSeen = last
+	}
+}
+
+func (s *relationStats) record(duration time.Duration, isError bool, at time.Time) {
+	s.Lock()
+	defer s.Unlock()
+
+	now := time.Now()
+	s.touchedAt = now
+	for _, slot := range []*statsSlot{s.slot(now), &s.pending} {
+		slot.calls++
+		if isError {
+			slot.errors++
+		}
+		slot.hist.add(duration)
+	}
+
+	s.seen(at, at)
+	if !s.hasPendingCall || at.Before(s.pendingFirst) {
+		s.pendingFirst = at
+	}
+	if at.After(s.pendingLast) {
+		s.pendingLast = at
+	}
+	s.hasPendingCall = true
+}
+
+func (s *relationStats) merge(stats *api_v1.RelationStats) {
+	s.Lock()
+	defer s.Unlock()
+
+	now := time.Now()
+	s.touchedAt = now
+	slot := s.slot(now)
+	slot.calls += stats.GetCalls()
+	slot.errors += stats.GetErrors()
+	for i, c := range stats.GetDurationBuckets() {
+		if i < histBuckets {
+			slot.hist[i] += c
+		}
+	}
+	if stats.GetCalls() > 0 {
+		s.seen(time.Unix(0, stats.GetFirstSeen()), time.Unix(0, stats.GetLastSeen()))
+	}
+}
+
+// flush returns calls observed locally since last flushing, or nil if there is not any.
+func (s *relationStats) flush(rel *api_v1.Relation) *api_v1.RelationStats {
+	s.Lock()
+	defer s.Unlock()
+
+	if !s.hasPendingCall {
+		return nil
+	}
+	ret := &api_v1.RelationStats{
+		Relation:        rel,
+		Calls:           s.pending.calls,
+		Errors:          s.pending.errors,
+		DurationBuckets: make([]int64, histBuckets),
+		FirstSeen:       s.pendingFirst.UnixNano(),
+		LastSeen:        s.pendingLast.UnixNano(),
+	}
+	copy(ret.DurationBuckets, s.pending.hist[:])
+
+	s.pending = statsSlot{}
+	s.hasPendingCall = false
+	return ret
+}
+
+func (s *relationStats) summary(now time.Time) *RelationStats {
+	s.Lock()
+	defer s.Unlock()
+
+	oldest := now.Truncate(statsSlotSpan).Add(-(statsSlots - 1) * statsSlotSpan)
+	calls, errors := int64(0), int64(0)
+	hist := &histogram{}
+	for i := range s.slots {
+		slot := &s.slots[i]
+		if slot.start.Before(oldest) || slot.start.After(now) {
+			continue
+		}
+		calls += slot.calls
+		errors += slot.errors
+		hist.merge(&slot.hist)
+	}
+
+	ret := &RelationStats{
+		Calls:     calls,
+		P50:       hist.percentile(0.5),
+		P99:       hist.percentile(0.99),
+		FirstSeen: s.firstSeen,
+		LastSeen:  s.lastSeen,
+	}
+	if calls > 0 {
+		ret.ErrorRatio = float64(errors) / float64(calls)
+		window := now.Sub(oldest)
+		if !s.firstSeen.IsZero() && s.firstSeen.After(oldest) {
+			// the relation is younger than the window.
+			window = now.Sub(s.firstSeen)
+		}
+		if window < time.Second {
+			window = time.Second
+		}
+		ret.CallsPerSecond = float64(calls) / window.Seconds()
+	}
+	return ret
+}
@@ -0,0 +1,113 @@
+// Copyright (c) 2021 The Houyi Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tg
+
+import (
+	"github.com/houyi-tracing/houyi/idl/api_v1"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"testing"
+	"time"
+)
+
+func TestHistogramPercentile(t *testing.T) {
+	h := &histogram{}
+	assert.Equal(t, time.Duration(0), h.percentile(0.5))
+
+	for i := 0; i < 99; i++ {
+		h.add(3 * time.Millisecond)
+	}
+	h.add(time.Second)
+
+	// 3ms is in [2048us, 4096us) and 1s is in [524288us, 1048576us).
+	p50 := h.percentile(0.5)
+	assert.True(t, p50 >= 2048*time.Microsecond && p50 < 4096*time.Microsecond)
+	p99 := h.percentile(0.99)
+	assert.True(t, p99 >= 2048*time.Microsecond && p99 <= 4096*time.Microsecond)
+	p100 := h.percentile(1)
+	assert.True(t, p100 >= 524288*time.Microsecond && p100 <= 1048576*time.Microsecond)
+}
+
+func TestRecordCall(t *testing.T) {
+	tg := NewTraceGraph(zap.NewNop())
+
+	op1 := &api_v1.Operation{Service: "1", Operation: "1"}
+	op2 := &api_v1.Operation{Service: "2", Operation: "2"}
+	rel := &api_v1.Relation{From: op1, To: op2}
+	assert.Nil(t, tg.Add(op1))
+	assert.Nil(t, tg.Add(op2))
+	assert.Error(t, tg.RecordCall(rel, time.Millisecond, false, time.Now()))
+	assert.Nil(t, tg.AddRelation(rel))
+
+	start := time.Now()
+	for i := 0; i < 100; i++ {
+		assert.Nil(t, tg.RecordCall(rel, time.Millisecond, i%4 == 0, start.Add(time.Duration(i)*time.Millisecond)))
+	}
+
+	stats, err := tg.RelationStats(rel)
+	assert.Nil(t, err)
+	assert.Equal(t, 0.25, stats.ErrorRatio)
+	assert.True(t, stats.CallsPerSecond > 0)
+	assert.True(t, stats.P50 >= 512*time.Microsecond && stats.P50 <= 1024*time.Microsecond)
+	assert.Equal(t, start, stats.FirstSeen)
+	assert.Equal(t, start.Add(99*time.Millisecond), stats.LastSeen)
+
+	traces, err := tg.Dependencies(op2, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(traces))
+	assert.Nil(t, traces[0].Stats)
+	assert.Equal(t, stats.ErrorRatio, traces[0].Children[0].Stats.ErrorRatio)
+
+	assert.Nil(t, tg.RemoveRelation(rel))
+	_, err = tg.RelationStats(rel)
+	assert.Error(t, err)
+}
+
+func TestFlushAndMergeRelationStats(t *testing.T) {
+	local, remote := NewTraceGraph(zap.NewNop()), NewTraceGraph(zap.NewNop())
+
+	op1 := &api_v1.Operation{Service: "1", Operation: "1"}
+	op2 := &api_v1.Operation{Service: "2", Operation: "2"}
+	rel := &api_v1.Relation{From: op1, To: op2}
+	for _, g := range []TraceGraph{local, remote} {
+		assert.Nil(t, g.Add(op1))
+		assert.Nil(t, g.Add(op2))
+		assert.Nil(t, g.AddRelation(rel))
+	}
+
+	now := time.Now()
+	assert.Nil(t, local.RecordCall(rel, time.Millisecond, true, now))
+	assert.Nil(t, local.RecordCall(rel, time.Millisecond, false, now.Add(time.Second)))
+	assert.Nil(t, remote.RecordCall(rel, time.Millisecond, false, now.Add(-time.Second)))
+
+	flushed := local.FlushRelationStats()
+	assert.Equal(t, 1, len(flushed))
+	assert.Equal(t, int64(2), flushed[0].GetCalls())
+	assert.Equal(t, int64(1), flushed[0].GetErrors())
+	assert.Equal(t, now.UnixNano(), flushed[0].GetFirstSeen())
+	assert.Equal(t, 0, len(local.FlushRelationStats()))
+
+	assert.Nil(t, remote.MergeRelationStats(flushed[0]))
+	stats, err := remote.RelationStats(rel)
+	assert.Nil(t, err)
+	assert.InDelta(t, 1.0/3, stats.ErrorRatio, 1e-9)
+	assert.Equal(t, now.Add(-time.Second).UnixNano(), stats.FirstSeen.UnixNano())
+	assert.Equal(t, now.Add(time.Second).UnixNano(), stats.LastSeen.UnixNano())
+
+	// merged calls must not be reported again.
+	remoteFlushed := remote.FlushRelationStats()
+	assert.Equal(t, 1, len(remoteFlushed))
+	assert.Equal(t, int64(1), remoteFlushed[0].GetCalls())
+}
@@ -17,10 +17,9 @@ package tg
 import (
 	"fmt"
 	"github.com/houyi-tracing/houyi/idl/api_v1"
-	"github.com/yan-fuhai/go-ds/set"
 	"go.uber.org/zap"
-	"strings"
 	"sync"
+	"time"
 )
 
 const (
@@ -31,6 +30,7 @@ const (
 const (
 	OperationDoesNotExistErr = "operation does not exist in this trace graph"
 	OperationAlreadyExistErr = "operation already exist in this trace graph"
+	RelationDoesNotExistErr  = "relation does not exist in this trace graph"
 )
 
 type traceGraph struct {
@@ -41,10 +41,19 @@ type traceGraph struct {
 
 	// globalRoot is used to mark some trace graph node as entries.
 	// Entries have below features:
-	//  1. The operation of entries would not be called by other operations.
+	//  1. The operation of entries would not be called by other operations, except ones in the same strongly
+	//     connected component of cyclic calls.
 	//  2. Node of every entry has a relation from globalRoot to itself.
 	// ATTENTION: globalRoot is not in traceGraph.nodes.
 	globalRoot *node
+
+	pub *publisher
+
+	// replica identifies this trace graph in version metadata. versions is the version metadata of all operations
+	// and relations, and delta is the part of it changed locally since last flushing.
+	replica  string
+	versions *dotStore
+	delta    *dotStore
 }
 
 func NewTraceGraph(logger *zap.Logger) TraceGraph {
@@ -57,6 +66,10 @@ func NewTraceGraph(logger *zap.Logger) TraceGraph {
 		logger:     logger,
 		nodes:      newNodeMap(),
 		globalRoot: newNode(fakeRootOp),
+		pub:        newPublisher(),
+		replica:    newReplicaId(),
+		versions:   newDotStore(),
+		delta:      newDotStore(),
 	}
 }
 
@@ -65,13 +78,8 @@ func (t *traceGraph) Add(op *api_v1.Operation) error {
 	defer t.Unlock()
 
 	if !t.has(op) {
-		n := newNode(op)
-		t.nodes.Add(op.GetService(), op.GetOperation(), n)
-
-		// mark a new operation as an ingress operation because there are not other operations calling it.
-		addRelation(t.globalRoot, n)
-		t.logger.Debug("added operation",
-			zap.String("service", op.Service), zap.String("operation", op.Operation))
+		t.addNode(op)
+		t.versionOperation(op)
 		return nil
 	} else {
 		return fmt.Errorf(OperationAlreadyExistErr)
@@ -83,19 +91,8 @@ func (t *traceGraph) Remove(op *api_v1.Operation) error {
 	defer t.Unlock()
 
 	if t.has(op) {
-		rmNode := t.get(op)
-
-		// remove all relations related to this node for garbage collection
-		for _, in := range rmNode.in.All() {
-			in.RemoveOut(rmNode)
-		}
-		for _, out := range rmNode.out.All() {
-			out.RemoveIn(rmNode)
-		}
-
-		t.nodes.Remove(op.Service, op.Operation)
-		t.logger.Debug("removed operation",
-			zap.String("service", op.Service), zap.String("operation", op.Operation))
+		t.removeNode(t.get(op))
+		t.unversionOperation(op)
 		return nil
 	} else {
 		return fmt.Errorf(OperationDoesNotExistErr)
@@ -119,11 +116,10 @@ func (t *traceGraph) AddRelation(rel *api_v1.Relation) error {
 			return fmt.Errorf("can not add relation for two same operations")
 		} else {
 			fromNode, toNode := t.get(from), t.get(to)
-			addRelation(fromNode, toNode)
-
-			if toNode.HasIn(t.globalRoot) {
-				removeRelation(t.globalRoot, toNode)
+			if !fromNode.HasOut(toNode) {
+				t.versionRelation(rel)
 			}
+			t.addEdge(fromNode, toNode)
 
 			t.logger.Debug("added relation", zap.String("relation", rel.String()))
 			return nil
@@ -141,10 +137,9 @@ func (t *traceGraph) RemoveRelation(rel *api_v1.Relation) error {
 	if t.has(from) && t.has(to) {
 		// from -> to
 		fromNode, toNode := t.get(from), t.get(to)
-		removeRelation(fromNode, toNode)
-
-		if toNode.InCnt() == 0 && toNode.OutCnt() != 0 {
-			addRelation(t.globalRoot, toNode)
+		if fromNode.HasOut(toNode) {
+			t.removeEdge(fromNode, toNode)
+			t.unversionRelation(rel)
 		}
 
 		t.logger.Debug("removed relation", zap.String("relation", rel.String()))
@@ -190,28 +185,26 @@ func (t *traceGraph) GetIngresses(op *api_v1.Operation) ([]*api_v1.Operation, er
 	defer t.RUnlock()
 
 	if t.has(op) {
-		entries := make([]*api_v1.Operation, 0)
-		entries = t.searchIngresses(t.get(op), entries, set.NewSet())
-		return entries, nil
+		return t.searchIngresses(t.get(op)), nil
 	} else {
 		return nil, fmt.Errorf(OperationDoesNotExistErr)
 	}
 }
 
-func (t *traceGraph) Dependencies(op *api_v1.Operation) ([]*TraceNode, error) {
+func (t *traceGraph) Dependencies(op *api_v1.Operation, maxDepth int) ([]*TraceNode, error) {
 	t.RLock()
 	defer t.RUnlock()
 
 	if t.has(op) {
 		traces := make([]*TraceNode, 0)
 
-		ingresses := make([]*api_v1.Operation, 0)
-		ingresses = t.searchIngresses(t.get(op), ingresses, set.NewSet())
+		ingresses := t.searchIngresses(t.get(op))
 
 		t.logger.Debug("get dependencies", zap.Any("ingress", ingresses))
 
+		now := time.Now()
 		for _, e := range ingresses {
-			traces = append(traces, generateTrace(t.get(e)))
+			traces = append(traces, generateTrace(t.get(e), now, maxDepth))
 		}
 
 		return traces, nil
@@ -220,6 +213,85 @@ func (t *traceGraph) Dependencies(op *api_v1.Operation) ([]*TraceNode, error) {
 	}
 }
 
+func (t *traceGraph) RecordCall(rel *api_v1.Relation, duration time.Duration, isError bool, at time.Time) error {
+	t.RLock()
+	defer t.RUnlock()
+
+	stats, err := t.relationStats(rel)
+	if err != nil {
+		return err
+	}
+	stats.record(duration, isError, at)
+	return nil
+}
+
+func (t *traceGraph) MergeRelationStats(stats *api_v1.RelationStats) error {
+	t.RLock()
+	defer t.RUnlock()
+
+	rs, err := t.relationStats(stats.GetRelation())
+	if err != nil {
+		return err
+	}
+	rs.merge(stats)
+	return nil
+}
+
+func (t *traceGraph) FlushRelationStats() []*api_v1.RelationStats {
+	t.RLock()
+	defer t.RUnlock()
+
+	ret := make([]*api_v1.RelationStats, 0)
+	for _, from := range t.nodes.All() {
+		for to, stats := range from.stats {
+			rel := &api_v1.Relation{
+				From: from.operation,
+				To:   to.operation,
+			}
+			if flushed := stats.flush(rel); flushed != nil {
+				ret = append(ret, flushed)
+			}
+		}
+	}
+	return ret
+}
+
+func (t *traceGraph) RelationStats(rel *api_v1.Relation) (*RelationStats, error) {
+	t.RLock()
+	defer t.RUnlock()
+
+	stats, err := t.relationStats(rel)
+	if err != nil {
+		return nil, err
+	}
+	return stats.summary(time.Now()), nil
+}
+
+func (t *traceGraph) RemoveExpiredRelations(ttl time.Duration) []*api_v1.Relation {
+	t.Lock()
+	defer t.Unlock()
+
+	now := time.Now()
+	ret := make([]*api_v1.Relation, 0)
+	for _, from := range t.nodes.All() {
+		for to, stats := range from.stats {
+			if !stats.expired(ttl, now) {
+				continue
+			}
+			t.removeEdge(from, to)
+
+			rel := &api_v1.Relation{
+				From: from.operation,
+				To:   to.operation,
+			}
+			t.unversionRelation(rel)
+			ret = append(ret, rel)
+			t.logger.Debug("removed expired relation", zap.String("relation", rel.String()))
+		}
+	}
+	return ret
+}
+
 func (t *traceGraph) Services() []string {
 	t.RLock()
 	defer t.RUnlock()
@@ -256,42 +328,162 @@ func (t *traceGraph) get(op *api_v1.Operation) *node {
 	return t.nodes.Get(op.GetService(), op.GetOperation())
 }
 
-func (t *traceGraph) searchIngresses(n *node, result []*api_v1.Operation, hasSearched set.Set) []*api_v1.Operation {
-	if hasSearched.Has(n) {
-		splitStr := make([]string, len(result))
-		for i, rn := range result {
-			splitStr[len(result)-1-i] = rn.String()
+// addNode adds a node of op, which is an ingress because there are not other operations calling it.
+func (t *traceGraph) addNode(op *api_v1.Operation) *node {
+	n := newNode(op)
+	t.nodes.Add(op.GetService(), op.GetOperation(), n)
+
+	addRelation(t.globalRoot, n)
+	t.publishOperation(api_v1.GraphEvent_OPERATION_ADDED, n)
+	t.publishOperation(api_v1.GraphEvent_INGRESS_ADDED, n)
+	t.logger.Debug("added operation",
+		zap.String("service", op.Service), zap.String("operation", op.Operation))
+	return n
+}
+
+// removeNode removes n and all relations related to it for garbage collection.
+func (t *traceGraph) removeNode(n *node) {
+	for _, in := range n.in.All() {
+		in.RemoveOut(n)
+		if in == t.globalRoot {
+			t.publishOperation(api_v1.GraphEvent_INGRESS_REMOVED, n)
+		} else {
+			t.publishRelation(api_v1.GraphEvent_RELATION_REMOVED, in, n)
 		}
-		t.logger.Fatal("cycled call found",
-			zap.String("call path", strings.Join(splitStr, "->")+n.String()))
-		return result
 	}
+	for _, out := range n.out.All() {
+		out.RemoveIn(n)
+		t.publishRelation(api_v1.GraphEvent_RELATION_REMOVED, n, out)
+		t.refreshIngresses(out)
+	}
+
+	t.nodes.Remove(n.operation.Service, n.operation.Operation)
+	t.publishOperation(api_v1.GraphEvent_OPERATION_REMOVED, n)
+	t.logger.Debug("removed operation",
+		zap.String("service", n.operation.Service), zap.String("operation", n.operation.Operation))
+}
 
-	hasSearched.Add(n)
-	if n.HasIn(t.globalRoot) && t.globalRoot.HasOut(n) {
-		result = append(result, n.operation)
+// addEdge adds the relation from one node to another, or refreshes it if it already exists.
+func (t *traceGraph) addEdge(from, to *node) {
+	if !from.HasOut(to) {
+		addRelation(from, to)
+		t.publishRelation(api_v1.GraphEvent_RELATION_ADDED, from, to)
+		t.refreshIngresses(to)
+	}
+	if stats, has := from.stats[to]; has {
+		stats.touch()
 	} else {
-		for _, next := range n.in.All() {
-			if next.operation.Service != n.operation.Service && next.operation.Operation != n.operation.Operation {
-				result = t.searchIngresses(next, result, hasSearched)
+		from.stats[to] = newRelationStats()
+	}
+}
+
+func (t *traceGraph) removeEdge(from, to *node) {
+	removeRelation(from, to)
+	t.publishRelation(api_v1.GraphEvent_RELATION_REMOVED, from, to)
+	t.refreshIngresses(to)
+}
+
+// refreshIngresses updates ingresses among nodes reachable from n after relations to n changed, which are the only
+// nodes whose strongly connected components or callers could be changed. Nodes of a strongly connected component are
+// all ingresses if no node outside of it calls any of them, so that operations calling each other without any other
+// caller still have ingresses.
+func (t *traceGraph) refreshIngresses(n *node) {
+	for _, component := range stronglyConnectedComponents([]*node{n}) {
+		members := make(map[*node]struct{}, len(component))
+		for _, m := range component {
+			members[m] = struct{}{}
+		}
+		isIngress := true
+		for _, m := range component {
+			for _, in := range m.in.All() {
+				if _, has := members[in]; !has && in != t.globalRoot {
+					isIngress = false
+				}
 			}
 		}
+		for _, m := range component {
+			t.setIngress(m, isIngress)
+		}
+	}
+}
+
+func (t *traceGraph) setIngress(n *node, isIngress bool) {
+	if hasIngress := n.HasIn(t.globalRoot); isIngress && !hasIngress {
+		addRelation(t.globalRoot, n)
+		t.publishOperation(api_v1.GraphEvent_INGRESS_ADDED, n)
+	} else if !isIngress && hasIngress {
+		removeRelation(t.globalRoot, n)
+		t.publishOperation(api_v1.GraphEvent_INGRESS_REMOVED, n)
 	}
-	return result
 }
 
-func generateTrace(root *node) *TraceNode {
-	if root != nil {
-		tn := &TraceNode{
-			Name:     fmt.Sprintf("%s:%s", root.operation.Service, root.operation.Operation),
-			Children: make([]*TraceNode, 0),
+func (t *traceGraph) relationStats(rel *api_v1.Relation) (*relationStats, error) {
+	if !t.has(rel.GetFrom()) || !t.has(rel.GetTo()) {
+		return nil, fmt.Errorf(OperationDoesNotExistErr)
+	}
+	if stats, has := t.get(rel.GetFrom()).stats[t.get(rel.GetTo())]; has {
+		return stats, nil
+	}
+	return nil, fmt.Errorf(RelationDoesNotExistErr)
+}
+
+// searchIngresses searches ingress operations which could reach n by walking against the direction of relations.
+// Ingresses in cyclic calls are called by other ingresses, so the walk goes on through them. Every node is visited at
+// most once, so cyclic calls between operations would not make it loop forever.
+func (t *traceGraph) searchIngresses(n *node) []*api_v1.Operation {
+	result := make([]*api_v1.Operation, 0)
+	visited := map[*node]struct{}{n: {}, t.globalRoot: {}}
+	queue := []*node{n}
+	for len(queue) > 0 {
+		curr := queue[0]
+		queue = queue[1:]
+
+		if curr.HasIn(t.globalRoot) && t.globalRoot.HasOut(curr) {
+			result = append(result, curr.operation)
 		}
-		for _, outNode := range root.out.All() {
-			if ret := generateTrace(outNode); ret != nil {
-				tn.Children = append(tn.Children, ret)
+		for _, prev := range curr.in.All() {
+			if _, has := visited[prev]; !has {
+				visited[prev] = struct{}{}
+				queue = append(queue, prev)
 			}
 		}
+	}
+	return result
+}
+
+// generateTrace generates the trace rooted at root. A relation pointing to an operation which is already on the path
+// from root is a back edge of cyclic calls, and the operation it points to is added as a leaf marked as Cycle.
+// Every node except root carries statistics of the relation from its parent at time now. Nodes deeper than maxDepth
+// are omitted if maxDepth is greater than 0, and their parents are marked as Truncated.
+func generateTrace(root *node, now time.Time, maxDepth int) *TraceNode {
+	if root == nil {
+		return nil
+	}
+	return generateTraceOnPath(root, now, 0, maxDepth, make(map[*node]struct{}))
+}
+
+func generateTraceOnPath(n *node, now time.Time, depth, maxDepth int, onPath map[*node]struct{}) *TraceNode {
+	tn := &TraceNode{
+		Name:     fmt.Sprintf("%s:%s", n.operation.Service, n.operation.Operation),
+		Children: make([]*TraceNode, 0),
+	}
+	if _, has := onPath[n]; has {
+		tn.Cycle = true
 		return tn
 	}
-	return nil
+	if maxDepth > 0 && depth >= maxDepth {
+		tn.Truncated = n.OutCnt() > 0
+		return tn
+	}
+
+	onPath[n] = struct{}{}
+	for _, outNode := range n.out.All() {
+		child := generateTraceOnPath(outNode, now, depth+1, maxDepth, onPath)
+		if stats, has := n.stats[outNode]; has {
+			child.Stats = stats.summary(now)
+		}
+		tn.Children = append(tn.Children, child)
+	}
+	delete(onPath, n)
+	return tn
 }
@@ -0,0 +1,245 @@
+// Copyright (c) 2021 The Houyi Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tg
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"github.com/houyi-tracing/houyi/idl/api_v1"
+	"go.uber.org/zap"
+	"strconv"
+	"time"
+)
+
+var (
+	operationElements = func(s *dotStore) map[string]*element { return s.operations }
+	relationElements  = func(s *dotStore) map[string]*element { return s.relations }
+)
+
+// newReplicaId returns a random identity of trace graph. A restarted process keeps the identity of its last
+// snapshot instead if snapshots are enabled, otherwise every restart adds a new replica into version metadata.
+func newReplicaId() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 16)
+	}
+	return hex.EncodeToString(b)
+}
+
+func (t *traceGraph) FlushDelta() *api_v1.GraphDelta {
+	t.Lock()
+	defer t.Unlock()
+
+	if t.delta.empty() {
+		return nil
+	}
+	ret := t.delta.toProto()
+	t.delta = newDotStore()
+	return ret
+}
+
+func (t *traceGraph) MergeDelta(delta *api_v1.GraphDelta) {
+	if delta == nil {
+		return
+	}
+	other := dotStoreFromProto(delta)
+
+	t.Lock()
+	defer t.Unlock()
+
+	ops, rels := t.versions.merge(other)
+	t.reconcile(ops, rels)
+	t.logger.Debug("merged delta", zap.Int("operations", len(ops)), zap.Int("relations", len(rels)))
+}
+
+// reconcile makes operations and relations of changed elements exist in this trace graph if and only if they exist
+// in version metadata. A relation exists only if both of its operations exist.
+func (t *traceGraph) reconcile(ops, rels map[string]*element) {
+	added := make(map[string]struct{})
+	for key, e := range ops {
+		_, exists := t.versions.operations[key]
+		if exists && !t.has(e.operation) {
+			t.addNode(e.operation)
+			added[key] = struct{}{}
+		} else if !exists && t.has(e.operation) {
+			// relations of it are removed along with it.
+			t.removeNode(t.get(e.operation))
+		}
+	}
+
+	// relations merged before their operations appear along with them.
+	if len(added) > 0 {
+		for key, e := range t.versions.relations {
+			_, fromAdded := added[operationKey(e.relation.GetFrom())]
+			_, toAdded := added[operationKey(e.relation.GetTo())]
+			if fromAdded || toAdded {
+				rels[key] = e
+			}
+		}
+	}
+
+	for key, e := range rels {
+		from, to := e.relation.GetFrom(), e.relation.GetTo()
+		if !t.has(from) || !t.has(to) {
+			continue
+		}
+		fromNode, toNode := t.get(from), t.get(to)
+		_, exists := t.versions.relations[key]
+		if exists && !fromNode.HasOut(toNode) {
+			t.addEdge(fromNode, toNode)
+		} else if !exists && fromNode.HasOut(toNode) {
+			t.removeEdge(fromNode, toNode)
+		}
+	}
+}
+
+// versionOperation records a local addition of op.
+func (t *traceGraph) versionOperation(op *api_v1.Operation) {
+	t.recordAdd(operationElements, operationKey(op), func() *element {
+		return &element{operation: op}
+	})
+}
+
+// unversionOperation records a local removal of op, which removes relations of it as well.
+func (t *traceGraph) unversionOperation(op *api_v1.Operation) {
+	key := operationKey(op)
+	t.recordRemove(operationElements, key)
+	for relKey, e := range t.versions.relations {
+		if operationKey(e.relation.GetFrom()) == key || operationKey(e.relation.GetTo()) == key {
+			t.recordRemove(relationElements, relKey)
+		}
+	}
+}
+
+// versionRelation records a local addition of rel. Operations of rel are added again, so that rel would survive
+// removals of its operations concurrent with it.
+func (t *traceGraph) versionRelation(rel *api_v1.Relation) {
+	t.versionOperation(rel.GetFrom())
+	t.versionOperation(rel.GetTo())
+	t.recordAdd(relationElements, relationKey(rel), func() *element {
+		return &element{relation: rel}
+	})
+}
+
+func (t *traceGraph) unversionRelation(rel *api_v1.Relation) {
+	t.recordRemove(relationElements, relationKey(rel))
+}
+
+func (t *traceGraph) recordAdd(elements func(*dotStore) map[string]*element, key string, newElement func() *element) {
+	d := t.versions.ctx.next(t.replica)
+	replaced := add(elements(t.versions), key, newElement(), d)
+	add(elements(t.delta), key, newElement(), d)
+
+	t.delta.ctx.add(d)
+	for r := range replaced {
+		t.delta.ctx.add(r)
+	}
+}
+
+func (t *traceGraph) recordRemove(elements func(*dotStore) map[string]*element, key string) {
+	removed := remove(elements(t.versions), key)
+	remove(elements(t.delta), key)
+	for r := range removed {
+		t.delta.ctx.add(r)
+	}
+}
+
+func operationKey(op *api_v1.Operation) string {
+	return nodeKey(op.GetService(), op.GetOperation())
+}
+
+func (s *dotStore) toProto() *api_v1.GraphDelta {
+	ret := &api_v1.GraphDelta{
+		Operations: make([]*api_v1.VersionedOperation, 0, len(s.operations)),
+		Relations:  make([]*api_v1.VersionedRelation, 0, len(s.relations)),
+		Context: &api_v1.CausalContext{
+			Versions: make(map[string]uint64, len(s.ctx.versions)),
+			Dots:     dotsToProto(s.ctx.dots),
+		},
+	}
+	for _, e := range s.operations {
+		ret.Operations = append(ret.Operations, &api_v1.VersionedOperation{
+			Operation: e.operation,
+			Dots:      dotsToProto(e.dots),
+		})
+	}
+	for _, e := range s.relations {
+		ret.Relations = append(ret.Relations, &api_v1.VersionedRelation{
+			Relation: e.relation,
+			Dots:     dotsToProto(e.dots),
+		})
+	}
+	for replica, version := range s.ctx.versions {
+		ret.Context.Versions[replica] = version
+	}
+	return ret
+}
+
+// dotStoreFromProto converts delta to a dot store. Elements without operations or dots are ignored.
+func dotStoreFromProto(delta *api_v1.GraphDelta) *dotStore {
+	s := newDotStore()
+	for _, vo := range delta.GetOperations() {
+		if vo.GetOperation() != nil && len(vo.GetDots()) > 0 {
+			s.operations[operationKey(vo.GetOperation())] = &element{
+				operation: vo.GetOperation(),
+				dots:      dotsFromProto(vo.GetDots()),
+			}
+		}
+	}
+	for _, vr := range delta.GetRelations() {
+		rel := vr.GetRelation()
+		if rel.GetFrom() != nil && rel.GetTo() != nil && len(vr.GetDots()) > 0 {
+			s.relations[relationKey(rel)] = &element{
+				relation: rel,
+				dots:     dotsFromProto(vr.GetDots()),
+			}
+		}
+	}
+	for replica, version := range delta.GetContext().GetVersions() {
+		s.ctx.versions[replica] = version
+	}
+	for d := range dotsFromProto(delta.GetContext().GetDots()) {
+		s.ctx.add(d)
+	}
+	// dots of elements are always seen.
+	for _, elements := range []map[string]*element{s.operations, s.relations} {
+		for _, e := range elements {
+			for d := range e.dots {
+				s.ctx.add(d)
+			}
+		}
+	}
+	s.ctx.compact()
+	return s
+}
+
+func dotsToProto(dots map[dot]struct{}) []*api_v1.Dot {
+	ret := make([]*api_v1.Dot, 0, len(dots))
+	for d := range dots {
+		ret = append(ret, &api_v1.Dot{
+			Replica: d.replica,
+			Counter: d.counter,
+		})
+	}
+	return ret
+}
+
+func dotsFromProto(dots []*api_v1.Dot) map[dot]struct{} {
+	ret := make(map[dot]struct{}, len(dots))
+	for _, d := range dots {
+		ret[dot{replica: d.GetReplica(), counter: d.GetCounter()}] = struct{}{}
+	}
+	return ret
+}
@@ -0,0 +1,298 @@
+// Copyright (c) 2021 The Houyi Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tg
+
+import (
+	"fmt"
+	"github.com/golang/protobuf/proto"
+	"github.com/houyi-tracing/houyi/idl/api_v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"math/rand"
+	"testing"
+)
+
+type delivery struct {
+	to    int
+	delta []byte
+}
+
+// cluster simulates replicas of trace graph gossiping deltas through an unreliable network, which reorders,
+// duplicates and loses messages.
+type cluster struct {
+	t        *testing.T
+	rnd      *rand.Rand
+	replicas []TraceGraph
+	inflight []delivery
+	lossRate float64
+}
+
+func newCluster(t *testing.T, size int, seed int64, lossRate float64) *cluster {
+	c := &cluster{
+		t:        t,
+		rnd:      rand.New(rand.NewSource(seed)),
+		lossRate: lossRate,
+	}
+	for i := 0; i < size; i++ {
+		c.replicas = append(c.replicas, NewTraceGraph(zap.NewNop()))
+	}
+	return c
+}
+
+func (c *cluster) gossip(from int) {
+	delta := c.replicas[from].FlushDelta()
+	if delta == nil {
+		return
+	}
+	data, err := proto.Marshal(delta)
+	require.Nil(c.t, err)
+	for to := range c.replicas {
+		if to == from {
+			continue
+		}
+		for copies := c.rnd.Intn(2) + 1; copies > 0; copies-- {
+			if c.rnd.Float64() >= c.lossRate {
+				c.inflight = append(c.inflight, delivery{to: to, delta: data})
+			}
+		}
+	}
+}
+
+// deliver delivers n randomly picked messages in flight.
+func (c *cluster) deliver(n int) {
+	for ; n > 0 && len(c.inflight) > 0; n-- {
+		i := c.rnd.Intn(len(c.inflight))
+		d := c.inflight[i]
+		c.inflight = append(c.inflight[:i], c.inflight[i+1:]...)
+
+		delta := &api_v1.GraphDelta{}
+		require.Nil(c.t, proto.Unmarshal(d.delta, delta))
+		c.replicas[d.to].MergeDelta(delta)
+	}
+}
+
+// mutate makes a random change on a random replica in the way collectors and gossip handlers do.
+func (c *cluster) mutate(ops []*api_v1.Operation) {
+	i := c.rnd.Intn(len(c.replicas))
+	g := c.replicas[i]
+	from, to := ops[c.rnd.Intn(len(ops))], ops[c.rnd.Intn(len(ops))]
+	rel := &api_v1.Relation{From: from, To: to}
+	switch c.rnd.Intn(4) {
+	case 0:
+		if !g.Has(from) {
+			assert.Nil(c.t, g.Add(from))
+		}
+	case 1:
+		if g.Has(from) {
+			assert.Nil(c.t, g.Remove(from))
+		}
+	case 2:
+		if operationEqual(from, to) {
+			return
+		}
+		for _, op := range []*api_v1.Operation{from, to} {
+			if !g.Has(op) {
+				assert.Nil(c.t, g.Add(op))
+			}
+		}
+		assert.Nil(c.t, g.AddRelation(rel))
+	case 3:
+		if g.HasRelation(rel) {
+			assert.Nil(c.t, g.RemoveRelation(rel))
+		}
+	}
+	c.gossip(i)
+}
+
+// antiEntropy makes every replica merge full states of all other replicas, as pulling from peers does.
+func (c *cluster) antiEntropy() {
+	for round := 0; round < 2; round++ {
+		for i, g := range c.replicas {
+			for j, other := range c.replicas {
+				if i != j {
+					g.Merge(other.State())
+				}
+			}
+		}
+	}
+}
+
+func (c *cluster) assertConverged() {
+	first := c.replicas[0].State()
+	for i, g := range c.replicas {
+		state := g.State()
+		d := Diff(first, state)
+		assert.True(c.t, d.Empty(), "replica %d diverged: %+v", i, d)
+		assert.True(c.t, Diff(versionedState(g.(*traceGraph)), state).Empty(),
+			"replica %d does not match its version metadata", i)
+		for _, op := range state.GetOperations() {
+			assert.Equal(c.t, c.replicas[0].IsIngress(op), g.IsIngress(op))
+		}
+	}
+}
+
+// versionedState returns operations and relations existing in version metadata of t.
+func versionedState(t *traceGraph) *api_v1.GraphState {
+	state := &api_v1.GraphState{}
+	for _, e := range t.versions.operations {
+		state.Operations = append(state.Operations, e.operation)
+	}
+	for _, e := range t.versions.relations {
+		_, hasFrom := t.versions.operations[operationKey(e.relation.GetFrom())]
+		_, hasTo := t.versions.operations[operationKey(e.relation.GetTo())]
+		if hasFrom && hasTo {
+			state.Relations = append(state.Relations, e.relation)
+		}
+	}
+	return state
+}
+
+func testOperations(n int) []*api_v1.Operation {
+	ops := make([]*api_v1.Operation, 0, n)
+	for i := 0; i < n; i++ {
+		ops = append(ops, &api_v1.Operation{Service: fmt.Sprintf("svc-%d", i%3), Operation: fmt.Sprintf("op-%d", i)})
+	}
+	return ops
+}
+
+func TestConvergenceWithReordering(t *testing.T) {
+	ops := testOperations(6)
+	for seed := int64(0); seed < 20; seed++ {
+		c := newCluster(t, 3, seed, 0)
+		for i := 0; i < 200; i++ {
+			c.mutate(ops)
+			c.deliver(c.rnd.Intn(3))
+		}
+		c.deliver(len(c.inflight))
+		c.assertConverged()
+	}
+}
+
+func TestConvergenceWithLoss(t *testing.T) {
+	ops := testOperations(6)
+	for seed := int64(0); seed < 20; seed++ {
+		c := newCluster(t, 4, seed, 0.3)
+		for i := 0; i < 200; i++ {
+			c.mutate(ops)
+			c.deliver(c.rnd.Intn(3))
+		}
+		c.deliver(len(c.inflight))
+		c.antiEntropy()
+		c.assertConverged()
+	}
+}
+
+func TestAddRelationWinsOverConcurrentRemoval(t *testing.T) {
+	x := &api_v1.Operation{Service: "x", Operation: "x"}
+	y := &api_v1.Operation{Service: "y", Operation: "y"}
+	xy := &api_v1.Relation{From: x, To: y}
+
+	for _, removalFirst := range []bool{true, false} {
+		a, b := NewTraceGraph(zap.NewNop()), NewTraceGraph(zap.NewNop())
+		assert.Nil(t, a.Add(x))
+		assert.Nil(t, a.Add(y))
+		b.MergeDelta(a.FlushDelta())
+		assert.True(t, b.Has(x))
+
+		// a expires x while b discovers a new call from x concurrently.
+		assert.Nil(t, a.Remove(x))
+		assert.Nil(t, b.AddRelation(xy))
+		removal, addition := a.FlushDelta(), b.FlushDelta()
+		if removalFirst {
+			b.MergeDelta(removal)
+			a.MergeDelta(addition)
+		} else {
+			a.MergeDelta(addition)
+			b.MergeDelta(removal)
+		}
+
+		for _, g := range []TraceGraph{a, b} {
+			assert.True(t, g.Has(x))
+			assert.True(t, g.HasRelation(xy))
+			assert.True(t, g.IsIngress(x))
+			assert.False(t, g.IsIngress(y))
+		}
+	}
+}
+
+func TestRemovalOfObservedOperation(t *testing.T) {
+	x := &api_v1.Operation{Service: "x", Operation: "x"}
+	y := &api_v1.Operation{Service: "y", Operation: "y"}
+	xy := &api_v1.Relation{From: x, To: y}
+
+	a, b := NewTraceGraph(zap.NewNop()), NewTraceGraph(zap.NewNop())
+	assert.Nil(t, a.Add(x))
+	assert.Nil(t, a.Add(y))
+	assert.Nil(t, a.AddRelation(xy))
+	b.MergeDelta(a.FlushDelta())
+	assert.True(t, b.HasRelation(xy))
+	assert.False(t, b.IsIngress(y))
+
+	assert.Nil(t, b.Remove(x))
+	removal := b.FlushDelta()
+	a.MergeDelta(removal)
+	// merging the same delta again changes nothing.
+	a.MergeDelta(removal)
+	for _, g := range []TraceGraph{a, b} {
+		assert.False(t, g.Has(x))
+		assert.False(t, g.HasRelation(xy))
+		assert.True(t, g.IsIngress(y))
+	}
+
+	// a stale delta re-adding x is ignored because its dots have been seen.
+	assert.Nil(t, a.FlushDelta())
+}
+
+func TestMergeVersionedSnapshot(t *testing.T) {
+	g, ops := newDiamond(t)
+	data, err := g.Snapshot()
+	assert.Nil(t, err)
+
+	restored := NewTraceGraph(zap.NewNop())
+	assert.Nil(t, restored.Restore(data))
+	assert.True(t, Diff(g.State(), restored.State()).Empty())
+
+	// removals made after the snapshot are merged into the restored trace graph.
+	assert.Nil(t, g.Remove(ops["b1"]))
+	restored.MergeDelta(g.FlushDelta())
+	assert.False(t, restored.Has(ops["b1"]))
+	assert.True(t, Diff(g.State(), restored.State()).Empty())
+}
+
+func TestRestartedReplicaMustKeepIdentity(t *testing.T) {
+	g, _ := newDiamond(t)
+	replica := g.(*traceGraph).replica
+	counter := g.(*traceGraph).versions.ctx.versions[replica]
+	data, err := g.Snapshot()
+	assert.Nil(t, err)
+
+	restarted := NewTraceGraph(zap.NewNop())
+	assert.Nil(t, restarted.Restore(data))
+	assert.Equal(t, replica, restarted.(*traceGraph).replica)
+
+	// new dots follow the restored ones, without adding another replica into version metadata.
+	assert.Nil(t, restarted.Add(&api_v1.Operation{Service: "new", Operation: "new"}))
+	versions := restarted.(*traceGraph).versions.ctx.versions
+	assert.Len(t, versions, 1)
+	assert.Equal(t, counter+1, versions[replica])
+
+	// a trace graph which has made dots keeps its own identity.
+	changed := NewTraceGraph(zap.NewNop())
+	assert.Nil(t, changed.Add(&api_v1.Operation{Service: "x", Operation: "x"}))
+	own := changed.(*traceGraph).replica
+	assert.Nil(t, changed.Restore(data))
+	assert.Equal(t, own, changed.(*traceGraph).replica)
+}
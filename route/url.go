@@ -16,8 +16,11 @@ package route
 
 // evaluator
 const (
-	GetEvaluatorTagsRoute    = "/getEvaluator"
-	UpdateEvaluatorTagsRoute = "/updateEvaluator"
+	GetEvaluatorTagsRoute     = "/getEvaluator"
+	UpdateEvaluatorTagsRoute  = "/updateEvaluator"
+	GetEvaluatorRulesRoute    = "/getEvaluatorRules"
+	UpdateEvaluatorRulesRoute = "/updateEvaluatorRules"
+	GetEvaluatorMatchesRoute  = "/getEvaluatorMatches"
 )
 
 // Trace Graph
@@ -26,6 +29,15 @@ const (
 	GetIngressServicesRoute    = "/getIngressServices"
 	GetOperationsRoute         = "/getOperations"
 	GetCausalDependenciesRoute = "/getCausalDependencies"
+	GetUpstreamRoute           = "/getUpstream"
+	GetDownstreamRoute         = "/getDownstream"
+	GetCallPathRoute           = "/getCallPath"
+	GetDependentServicesRoute  = "/getDependentServices"
+	GetTraceGraphRoute         = "/getTraceGraph"
+	GetDependencyLinksRoute    = "/getDependencyLinks"
+	WatchTraceGraphRoute       = "/watchTraceGraph"
+	DiffTraceGraphRoute        = "/diffTraceGraph"
+	GetTraceGraphSnapshotRoute = "/getTraceGraphSnapshot"
 )
 
 // Strategy Manager
@@ -37,3 +49,8 @@ const (
 	GetDefaultStrategyRoute    = "/getDefaultStrategy"
 	UpdateDefaultStrategyRoute = "/updateDefaultStrategy"
 )
+
+// Sampling Strategy Tree
+const (
+	GetSamplingStrategyTreeRoute = "/getSamplingStrategyTree"
+)